@@ -1,3 +1,7 @@
+// The module targets an older Go version, so enable ServeMux patterns for the
+// standard library adapter.
+//go:debug httpmuxgo121=0
+
 // Package adapters_test runs basic verification tests on all adapters.
 package adapters_test
 
@@ -14,6 +18,7 @@ import (
 	"github.com/danielgtaylor/huma/v2/adapters/humaecho"
 	"github.com/danielgtaylor/huma/v2/adapters/humafiber"
 	"github.com/danielgtaylor/huma/v2/adapters/humagin"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
 	"github.com/danielgtaylor/huma/v2/adapters/humahttprouter"
 	"github.com/danielgtaylor/huma/v2/adapters/humamux"
 	"github.com/danielgtaylor/huma/v2/humatest"
@@ -83,6 +88,20 @@ func testAdapter(t *testing.T, api huma.API) {
 		"$schema": "http://localhost/schemas/TestOutputBody.json",
 		"message": "Hello, Daniel <daniel@example.com>! (foo, false, Bearer abc123)"
 	}`, resp.Body.String())
+
+		// Query params are not part of the route pattern, so the path param must
+		// be matched without them.
+		resp = testAPI.Do(method, "/bar?verbose=true",
+			"Host: localhost",
+			"Authorization: Bearer abc123",
+			strings.NewReader(`{"name": "Daniel", "email": "daniel@example.com"}`),
+		)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{
+		"$schema": "http://localhost/schemas/TestOutputBody.json",
+		"message": "Hello, Daniel <daniel@example.com>! (bar, true, Bearer abc123)"
+	}`, resp.Body.String())
 	}
 }
 
@@ -100,6 +119,7 @@ func TestAdapters(t *testing.T) {
 		{"echo", func() huma.API { return humaecho.New(echo.New(), config()) }},
 		{"fiber", func() huma.API { return humafiber.New(fiber.New(), config()) }},
 		{"gin", func() huma.API { return humagin.New(gin.New(), config()) }},
+		{"go", func() huma.API { return humago.New(http.NewServeMux(), config()) }},
 		{"httprouter", func() huma.API { return humahttprouter.New(httprouter.New(), config()) }},
 		{"mux", func() huma.API { return humamux.New(mux.NewRouter(), config()) }},
 		{"bunrouter", func() huma.API { return humabunrouter.New(bunrouter.New(), config()) }},
@@ -70,8 +70,7 @@ func (c *bunContext) BodyReader() io.Reader {
 }
 
 func (c *bunContext) GetMultipartForm() (*multipart.Form, error) {
-	err := c.r.ParseMultipartForm(MultipartMaxMemory)
-	return c.r.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.w, c.r.Request, MultipartMaxMemory)
 }
 
 func (c *bunContext) SetReadDeadline(deadline time.Time) error {
@@ -151,8 +150,7 @@ func (c *bunCompatContext) BodyReader() io.Reader {
 }
 
 func (c *bunCompatContext) GetMultipartForm() (*multipart.Form, error) {
-	err := c.r.ParseMultipartForm(8 * 1024)
-	return c.r.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.w, c.r, 8*1024)
 }
 
 func (c *bunCompatContext) SetReadDeadline(deadline time.Time) error {
@@ -74,8 +74,7 @@ func (c *chiContext) BodyReader() io.Reader {
 }
 
 func (c *chiContext) GetMultipartForm() (*multipart.Form, error) {
-	err := c.r.ParseMultipartForm(MultipartMaxMemory)
-	return c.r.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.w, c.r, MultipartMaxMemory)
 }
 
 func (c *chiContext) SetReadDeadline(deadline time.Time) error {
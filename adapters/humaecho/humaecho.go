@@ -67,8 +67,7 @@ func (c *echoCtx) BodyReader() io.Reader {
 }
 
 func (c *echoCtx) GetMultipartForm() (*multipart.Form, error) {
-	err := c.orig.Request().ParseMultipartForm(MultipartMaxMemory)
-	return c.orig.Request().MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.orig.Response(), c.orig.Request(), MultipartMaxMemory)
 }
 
 func (c *echoCtx) SetReadDeadline(deadline time.Time) error {
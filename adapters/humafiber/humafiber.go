@@ -71,6 +71,13 @@ func (c *fiberCtx) BodyReader() io.Reader {
 }
 
 func (c *fiberCtx) GetMultipartForm() (*multipart.Form, error) {
+	if c.op != nil && c.op.MaxBodyBytes > 0 && !c.orig.App().Server().StreamRequestBody {
+		// The body has already been read into memory, so check its size before
+		// parsing it.
+		if len(c.orig.BodyRaw()) > int(c.op.MaxBodyBytes) {
+			return nil, &http.MaxBytesError{Limit: c.op.MaxBodyBytes}
+		}
+	}
 	return c.orig.MultipartForm()
 }
 
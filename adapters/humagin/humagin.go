@@ -67,8 +67,7 @@ func (c *ginCtx) BodyReader() io.Reader {
 }
 
 func (c *ginCtx) GetMultipartForm() (*multipart.Form, error) {
-	err := c.orig.Request.ParseMultipartForm(MultipartMaxMemory)
-	return c.orig.Request.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.orig.Writer, c.orig.Request, MultipartMaxMemory)
 }
 
 func (c *ginCtx) SetReadDeadline(deadline time.Time) error {
@@ -75,8 +75,7 @@ func (c *goContext) BodyReader() io.Reader {
 }
 
 func (c *goContext) GetMultipartForm() (*multipart.Form, error) {
-	err := c.r.ParseMultipartForm(MultipartMaxMemory)
-	return c.r.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.w, c.r, MultipartMaxMemory)
 }
 
 func (c *goContext) SetReadDeadline(deadline time.Time) error {
@@ -130,12 +129,27 @@ func NewAdapter(m Mux, prefix string) huma.Adapter {
 //	mux := http.NewServeMux()
 //	api := humago.New(mux, huma.DefaultConfig("My API", "1.0.0"))
 func New(m Mux, config huma.Config) huma.API {
-	// Panic if Go version is less than 1.22
+	checkPatterns(m)
+	return huma.NewAPI(config, &goAdapter{m, ""})
+}
+
+// checkPatterns panics if the Go version is less than 1.22 or if a standard
+// library mux does not support method & wildcard patterns. The latter happens
+// when the main module's `go.mod` declares a Go version before 1.22, which
+// silently results in 404 responses for every operation.
+func checkPatterns(m Mux) {
 	var v any = &http.Request{}
 	if _, ok := v.(interface{ PathValue(string) string }); !ok {
 		panic("This adapter requires Go 1.22+")
 	}
-	return huma.NewAPI(config, &goAdapter{m, ""})
+
+	if _, ok := m.(*http.ServeMux); ok {
+		probe := http.NewServeMux()
+		probe.HandleFunc("GET /{id}", func(http.ResponseWriter, *http.Request) {})
+		if _, pattern := probe.Handler(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/probe"}}); pattern == "" {
+			panic("This adapter requires http.ServeMux patterns, which need `go 1.22` or later in go.mod or GODEBUG=httpmuxgo121=0")
+		}
+	}
 }
 
 // NewWithPrefix creates a new Huma API using an HTTP mux with a URL prefix.
@@ -149,5 +163,6 @@ func New(m Mux, config huma.Config) huma.API {
 //	config.Servers = []*huma.Server{{URL: "http://example.com/api"}}
 //	api := humago.NewWithPrefix(mux, "/api", config)
 func NewWithPrefix(m Mux, prefix string, config huma.Config) huma.API {
+	checkPatterns(m)
 	return huma.NewAPI(config, &goAdapter{m, prefix})
 }
@@ -70,8 +70,7 @@ func (c *httprouterContext) BodyReader() io.Reader {
 }
 
 func (c *httprouterContext) GetMultipartForm() (*multipart.Form, error) {
-	err := c.r.ParseMultipartForm(MultipartMaxMemory)
-	return c.r.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.w, c.r, MultipartMaxMemory)
 }
 
 func (c *httprouterContext) SetReadDeadline(deadline time.Time) error {
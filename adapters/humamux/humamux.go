@@ -68,8 +68,7 @@ func (c *gmuxContext) BodyReader() io.Reader {
 }
 
 func (c *gmuxContext) GetMultipartForm() (*multipart.Form, error) {
-	err := c.r.ParseMultipartForm(MultipartMaxMemory)
-	return c.r.MultipartForm, err
+	return huma.ParseMultipartForm(c.op, c.w, c.r, MultipartMaxMemory)
 }
 
 func (c *gmuxContext) SetReadDeadline(deadline time.Time) error {
@@ -18,10 +18,27 @@ import (
 	"github.com/danielgtaylor/huma/v2/negotiation"
 )
 
+// specContentTypes are the content types a client can request for the OpenAPI
+// spec via the `Accept` header. JSON comes first so it wins any ties.
+var specContentTypes = []string{
+	"application/vnd.oai.openapi+json",
+	"application/json",
+	"application/vnd.oai.openapi+yaml",
+	"application/yaml",
+	"application/x-yaml",
+	"text/yaml",
+}
+
 var rxSchema = regexp.MustCompile(`#/components/schemas/([^"]+)`)
 
 var ErrUnknownContentType = errors.New("unknown content type")
 
+// ErrTypedOnly is returned by a format's `Unmarshal` function when it can only
+// decode into typed values and not into generic ones like `map[string]any`,
+// for example XML. For validation the request body is then decoded into the
+// input body type and converted to generic values via JSON.
+var ErrTypedOnly = errors.New("format can only decode typed values")
+
 // Resolver runs a `Resolve` function after a request has been parsed, enabling
 // you to run custom validation or other code that can modify the request and /
 // or return errors.
@@ -161,11 +178,52 @@ type Config struct {
 	// blank and attach it directly to the router or adapter.
 	DocsPath string
 
+	// DocsRenderer selects the tool used to render the documentation at
+	// `DocsPath`, e.g. `huma.DocsRendererSwaggerUI`. If unset, Stoplight
+	// Elements is used.
+	DocsRenderer string
+
+	// DocsTitle is the title of the documentation page. If unset, the API's
+	// `Info.Title` followed by "Reference" is used.
+	DocsTitle string
+
+	// DocsTemplate renders the HTML of the documentation page instead of the
+	// built-in renderers, e.g. to change the layout or self-host the assets.
+	// It is given the HTML-escaped page title and the path to the OpenAPI spec
+	// without its extension, so `openAPIPath + ".json"` is the JSON spec.
+	DocsTemplate func(title, openAPIPath string) string
+
 	// SchemasPath is the path to the API schemas. If set to `/schemas` it will
 	// allow clients to get `/schemas/{schema}` to view the schema in a browser
 	// or for use in editors like VSCode to provide autocomplete & validation.
 	SchemasPath string
 
+	// SchemaDialect is the JSON Schema dialect used for the schemas served at
+	// `SchemasPath`, for tools which don't support the default of draft
+	// 2020-12, e.g. `huma.DialectDraft07`.
+	SchemaDialect SchemaDialect
+
+	// MaxBodyBytes is the request body size limit for operations which don't
+	// set their own `MaxBodyBytes`. If unset, 1 MiB is used. Use -1 for
+	// unlimited.
+	MaxBodyBytes int64
+
+	// BodyReadTimeout is the request body read timeout for operations which
+	// don't set their own `BodyReadTimeout`. If unset, 5 seconds is used. Use
+	// -1 for unlimited.
+	BodyReadTimeout time.Duration
+
+	// AdditionalProperties sets whether generated object schemas allow
+	// properties that are not defined on the struct, so request bodies with
+	// unknown fields pass validation. Individual structs can override it with
+	// `additionalProperties` on a `_` field.
+	AdditionalProperties bool
+
+	// SynthesizeExamples sets whether generated field schemas which have no
+	// `example` tag get a synthesized example value, so generated docs always
+	// have something to show.
+	SynthesizeExamples bool
+
 	// Formats defines the supported request/response formats by content type or
 	// extension (e.g. `json` for `application/my-format+json`).
 	Formats map[string]Format
@@ -366,6 +424,22 @@ func NewAPI(config Config, a Adapter) API {
 		config.OpenAPI.Components.Schemas = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
 	}
 
+	// Options set on the config are applied to the registry once here, so
+	// schemas generated before the first operation use them too, and options
+	// set directly on a `ConfigurableRegistry` are left alone otherwise.
+	if r, ok := config.OpenAPI.Components.Schemas.(ConfigurableRegistry); ok {
+		opts := r.SchemaOptions()
+		if config.AdditionalProperties {
+			opts.AdditionalProperties = true
+		}
+		if config.SynthesizeExamples {
+			opts.SynthesizeExamples = true
+		}
+	}
+
+	// Keep the config so `Register` can apply its defaults to operations.
+	config.OpenAPI.config = &config
+
 	if config.DefaultFormat == "" && config.Formats["application/json"].Marshal != nil {
 		config.DefaultFormat = "application/json"
 	}
@@ -422,9 +496,34 @@ func NewAPI(config Config, a Adapter) API {
 			}
 			ctx.BodyWriter().Write(specYAML30)
 		})
+		a.Handle(&Operation{
+			Method: http.MethodGet,
+			Path:   config.OpenAPIPath,
+		}, func(ctx Context) {
+			// Let the client pick JSON or YAML via the `Accept` header, defaulting
+			// to JSON when nothing matches.
+			ctx.SetHeader("Vary", "Accept")
+			ct := negotiation.SelectQValue(ctx.Header("Accept"), specContentTypes)
+			if strings.Contains(ct, "yaml") {
+				ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+yaml")
+				if specYAML == nil {
+					specYAML, _ = newAPI.OpenAPI().YAML()
+				}
+				ctx.BodyWriter().Write(specYAML)
+				return
+			}
+			ctx.SetHeader("Content-Type", "application/vnd.oai.openapi+json")
+			if specJSON == nil {
+				specJSON, _ = json.Marshal(newAPI.OpenAPI())
+			}
+			ctx.BodyWriter().Write(specJSON)
+		})
 	}
 
 	if config.DocsPath != "" {
+		if _, ok := docsTemplates[config.DocsRenderer]; !ok && config.DocsRenderer != "" {
+			panic(fmt.Errorf("unknown docs renderer %q", config.DocsRenderer))
+		}
 		a.Handle(&Operation{
 			Method: http.MethodGet,
 			Path:   config.DocsPath,
@@ -434,34 +533,14 @@ func NewAPI(config Config, a Adapter) API {
 				openAPIPath = path.Join(prefix, openAPIPath)
 			}
 			ctx.SetHeader("Content-Type", "text/html")
-			title := "Elements in HTML"
-			if config.Info != nil && config.Info.Title != "" {
-				title = config.Info.Title + " Reference"
+			title := config.DocsTitle
+			if title == "" {
+				title = "Elements in HTML"
+				if config.Info != nil && config.Info.Title != "" {
+					title = config.Info.Title + " Reference"
+				}
 			}
-			ctx.BodyWriter().Write([]byte(`<!doctype html>
-<html lang="en">
-  <head>
-    <meta charset="utf-8" />
-    <meta name="referrer" content="same-origin" />
-    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no" />
-    <title>` + title + `</title>
-    <!-- Embed elements Elements via Web Component -->
-    <link href="https://unpkg.com/@stoplight/elements@8.1.0/styles.min.css" rel="stylesheet" />
-    <script src="https://unpkg.com/@stoplight/elements@8.1.0/web-components.min.js"
-            integrity="sha256-985sDMZYbGa0LDS8jYmC4VbkVlh7DZ0TWejFv+raZII="
-            crossorigin="anonymous"></script>
-  </head>
-  <body style="height: 100vh;">
-
-    <elements-api
-      apiDescriptionUrl="` + openAPIPath + `.yaml"
-      router="hash"
-      layout="sidebar"
-      tryItCredentialsPolicy="same-origin"
-    />
-
-  </body>
-</html>`))
+			ctx.BodyWriter().Write(docsHTML(config, title, openAPIPath))
 		})
 	}
 
@@ -473,7 +552,7 @@ func NewAPI(config Config, a Adapter) API {
 			// Some routers dislike a path param+suffix, so we strip it here instead.
 			schema := strings.TrimSuffix(ctx.Param("schema"), ".json")
 			ctx.SetHeader("Content-Type", "application/json")
-			b, _ := json.Marshal(config.OpenAPI.Components.Schemas.Map()[schema])
+			b, _ := config.OpenAPI.Components.Schemas.Map()[schema].MarshalDialect(config.SchemaDialect)
 			b = rxSchema.ReplaceAll(b, []byte(config.SchemasPath+`/$1.json`))
 			ctx.BodyWriter().Write(b)
 		})
@@ -3,6 +3,8 @@ package huma_test
 import (
 	"context"
 	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -125,3 +127,80 @@ func TestRouterPrefix(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 	assert.Contains(t, resp.Body.String(), "/api/openapi.yaml")
 }
+
+func TestDocsRenderer(t *testing.T) {
+	for renderer, expected := range map[string]string{
+		"":                                 "elements-api",
+		huma.DocsRendererStoplightElements: "elements-api",
+		huma.DocsRendererScalar:            "@scalar/api-reference",
+		huma.DocsRendererSwaggerUI:         "SwaggerUIBundle",
+		huma.DocsRendererRapiDoc:           "rapi-doc",
+	} {
+		t.Run(renderer, func(t *testing.T) {
+			config := huma.DefaultConfig("My API", "1.0.0")
+			config.DocsRenderer = renderer
+			_, api := humatest.New(t, config)
+
+			resp := api.Get("/docs")
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Contains(t, resp.Body.String(), expected)
+			assert.Contains(t, resp.Body.String(), "<title>My API Reference</title>")
+			assert.Contains(t, resp.Body.String(), "/openapi.")
+
+			// CDN assets are pinned to an exact version and loaded anonymously.
+			assets := regexp.MustCompile(`(?:src|href)="(https://[^"]+)"`).FindAllStringSubmatch(resp.Body.String(), -1)
+			assert.NotEmpty(t, assets)
+			for _, asset := range assets {
+				assert.Regexp(t, `@\d+\.\d+\.\d+(/|$)`, asset[1])
+			}
+			assert.Equal(t, len(assets), strings.Count(resp.Body.String(), `crossorigin="anonymous"`))
+		})
+	}
+
+	assert.Panics(t, func() {
+		config := huma.DefaultConfig("My API", "1.0.0")
+		config.DocsRenderer = "bogus"
+		humatest.New(t, config)
+	})
+}
+
+func TestDocsTemplate(t *testing.T) {
+	config := huma.DefaultConfig("My API", "1.0.0")
+	config.DocsPath = "/reference"
+	config.DocsTitle = "Things & Stuff"
+	config.DocsTemplate = func(title, openAPIPath string) string {
+		return "<title>" + title + "</title><a href=\"" + openAPIPath + ".json\">spec</a>"
+	}
+	_, api := humatest.New(t, config)
+
+	resp := api.Get("/reference")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `<title>Things &amp; Stuff</title><a href="/openapi.json">spec</a>`, resp.Body.String())
+
+	// The title also applies to the built-in renderers.
+	config = huma.DefaultConfig("My API", "1.0.0")
+	config.DocsTitle = "Custom"
+	_, api = humatest.New(t, config)
+	assert.Contains(t, api.Get("/docs").Body.String(), "<title>Custom</title>")
+}
+
+func TestSchemaDialectPath(t *testing.T) {
+	config := huma.DefaultConfig("My API", "1.0.0")
+	config.SchemaDialect = huma.DialectOpenAPI30
+	_, api := humatest.New(t, config)
+
+	type DialectOutput struct {
+		Body struct {
+			Count *int `json:"count" exclusiveMinimum:"0"`
+		}
+	}
+
+	huma.Get(api, "/dialect", func(ctx context.Context, input *struct{}) (*DialectOutput, error) {
+		return &DialectOutput{}, nil
+	})
+
+	resp := api.Get("/schemas/DialectOutputBody.json")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"nullable":true`)
+	assert.Contains(t, resp.Body.String(), `"exclusiveMinimum":true`)
+}
@@ -118,7 +118,7 @@ func PatchResource(api huma.API, path *huma.PathItem) {
 					break
 				}
 			}
-			for status := range put.Errors {
+			for _, status := range put.Errors {
 				if status == code {
 					found = true
 					break
@@ -182,8 +182,10 @@ func PatchResource(api huma.API, path *huma.PathItem) {
 			return
 		}
 
-		// Perform the get!
-		origReq, err := http.NewRequest(http.MethodGet, ctx.URL().Path, nil)
+		// Perform the get! The query string is kept so that any query params
+		// used to select the resource are passed along.
+		u := ctx.URL()
+		origReq, err := http.NewRequest(http.MethodGet, u.RequestURI(), nil)
 		if err != nil {
 			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to get resource", err)
 			return
@@ -209,8 +211,7 @@ func PatchResource(api huma.API, path *huma.PathItem) {
 
 		// Accept JSON for the patches.
 		// TODO: could we accept other stuff here...?
-		ctx.SetHeader("Accept", "application/json")
-		ctx.SetHeader("Accept-Encoding", "")
+		origReq.Header.Set("Accept", "application/json")
 
 		origWriter := httptest.NewRecorder()
 		adapter.ServeHTTP(origWriter, origReq)
@@ -283,7 +284,7 @@ func PatchResource(api huma.API, path *huma.PathItem) {
 		}
 
 		// Write the updated data back to the server!
-		putReq, err := http.NewRequest(http.MethodPut, ctx.URL().Path, bytes.NewReader(patched))
+		putReq, err := http.NewRequest(http.MethodPut, u.RequestURI(), bytes.NewReader(patched))
 		if err != nil {
 			huma.WriteErr(api, ctx, http.StatusInternalServerError, "Unable to put modified resource", err)
 			return
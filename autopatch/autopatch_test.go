@@ -330,3 +330,63 @@ func TestDeprecatedPatch(t *testing.T) {
 
 	assert.True(t, api.OpenAPI().Paths["/things/{thing-id}"].Patch.Deprecated)
 }
+
+func TestPatchQueryParams(t *testing.T) {
+	_, api := humatest.New(t)
+
+	db := map[string]*ThingModel{
+		"v1": {ID: "test", Price: 1.00},
+	}
+
+	type VersionParam struct {
+		ThingIDParam
+		Version string `query:"version"`
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{thing-id}",
+		Errors:      []int{404},
+	}, func(ctx context.Context, input *VersionParam) (*struct{ Body *ThingModel }, error) {
+		thing := db[input.Version]
+		if thing == nil {
+			return nil, huma.Error404NotFound("Not found")
+		}
+		return &struct{ Body *ThingModel }{thing}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{thing-id}",
+		Errors:      []int{404, http.StatusUnsupportedMediaType},
+	}, func(ctx context.Context, input *struct {
+		VersionParam
+		Body ThingModel
+	}) (*struct{ Body *ThingModel }, error) {
+		db[input.Version] = &input.Body
+		return &struct{ Body *ThingModel }{&input.Body}, nil
+	})
+
+	AutoPatch(api)
+
+	// Errors from the PUT are not duplicated in the generated operation.
+	errs := api.OpenAPI().Paths["/things/{thing-id}"].Patch.Errors
+	count := 0
+	for _, code := range errs {
+		if code == http.StatusUnsupportedMediaType {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count, errs)
+
+	w := api.Patch("/things/test?version=v1",
+		"Content-Type: application/merge-patch+json",
+		"Accept: application/json",
+		strings.NewReader(`{"price": 2.5}`),
+	)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, w.Result().Header.Get("Accept"))
+	assert.InDelta(t, 2.5, db["v1"].Price, 0.001)
+}
@@ -0,0 +1,447 @@
+// Package cache provides an opt-in response cache for read-heavy operations.
+// Cached operations get declarative `Cache-Control` & `Expires` headers, their
+// `GET` and `HEAD` responses are stored and served from a pluggable store,
+// and conditional requests using `If-None-Match` or `If-Modified-Since` are
+// answered with `304 Not Modified` automatically.
+//
+// Caching is enabled per operation by setting the `cache` operation metadata
+// field to a `*cache.Policy`:
+//
+//	api.UseMiddleware(cache.New(cache.Options{}))
+//
+//	huma.Register(api, huma.Operation{
+//		OperationID: "list-things",
+//		Method:      http.MethodGet,
+//		Path:        "/things",
+//		Metadata: map[string]any{
+//			"cache": &cache.Policy{MaxAge: time.Minute, StaleWhileRevalidate: time.Minute},
+//		},
+//	}, handler)
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"hash/fnv"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/middleware"
+)
+
+// now returns the current time and can be replaced in tests.
+var now = time.Now
+
+// DefaultMaxEntries is the default maximum number of responses kept by the
+// in-memory store.
+var DefaultMaxEntries = 1000
+
+// Policy describes how an operation's responses may be cached.
+type Policy struct {
+	// MaxAge is how long a response stays fresh.
+	MaxAge time.Duration
+
+	// StaleWhileRevalidate is how long after becoming stale a response may
+	// still be served while it is refreshed in the background.
+	StaleWhileRevalidate time.Duration
+
+	// Private marks responses as specific to a single client. Only the headers
+	// are sent so the client may cache them, and nothing is stored on the
+	// server.
+	Private bool
+
+	// Vary lists the request headers which affect the response, e.g.
+	// `Accept-Language`. They are part of the cache key and sent in the `Vary`
+	// header. `Accept` is always included since it selects the response
+	// format.
+	Vary []string
+}
+
+// cacheControl returns the `Cache-Control` header value for the policy.
+func (p *Policy) cacheControl() string {
+	scope := "public"
+	if p.Private {
+		scope = "private"
+	}
+	value := scope + ", max-age=" + strconv.Itoa(int(p.MaxAge/time.Second))
+	if p.StaleWhileRevalidate > 0 {
+		value += ", stale-while-revalidate=" + strconv.Itoa(int(p.StaleWhileRevalidate/time.Second))
+	}
+	return value
+}
+
+// Entry is a stored response.
+type Entry struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Stored is when the response was generated.
+	Stored time.Time
+}
+
+// Store keeps cached responses. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	// Get returns the entry for a key, if present.
+	Get(key string) (*Entry, bool)
+
+	// Set stores an entry for a key. It may be removed after the TTL expires.
+	Set(key string, entry *Entry, ttl time.Duration)
+
+	// Delete removes the entry for a key, e.g. after the resource changes.
+	Delete(key string)
+}
+
+// Options configures the cache middleware.
+type Options struct {
+	// Store keeps the cached responses. Defaults to an in-memory store with
+	// `DefaultMaxEntries` entries.
+	Store Store
+}
+
+// New creates a new cache middleware with the given options. Operations
+// without a `cache` metadata policy are passed through untouched.
+//
+//	api.UseMiddleware(cache.New(cache.Options{}))
+func New(opts Options) func(ctx huma.Context, next func(huma.Context)) {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore(DefaultMaxEntries)
+	}
+
+	var mu sync.Mutex
+	refreshing := map[string]bool{}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		policy := operationPolicy(ctx.Operation())
+		if policy == nil || (ctx.Method() != http.MethodGet && ctx.Method() != http.MethodHead) {
+			next(ctx)
+			return
+		}
+
+		for _, name := range varyHeaders(policy.Vary) {
+			ctx.AppendHeader("Vary", name)
+		}
+
+		if policy.Private {
+			ctx.SetHeader("Cache-Control", policy.cacheControl())
+			ctx.SetHeader("Expires", now().Add(policy.MaxAge).UTC().Format(http.TimeFormat))
+			next(ctx)
+			return
+		}
+
+		key := Key(ctx, policy.Vary)
+		reqCache := ctx.Header("Cache-Control")
+		if !strings.Contains(reqCache, "no-cache") && !strings.Contains(reqCache, "no-store") {
+			if entry, ok := opts.Store.Get(key); ok {
+				age := now().Sub(entry.Stored)
+				if age < policy.MaxAge {
+					write(ctx, policy, entry)
+					return
+				}
+				if age < policy.MaxAge+policy.StaleWhileRevalidate {
+					// Serve the stale response and refresh it in the background so
+					// the next request gets a fresh one, unless that is already
+					// happening.
+					write(ctx, policy, entry)
+					mu.Lock()
+					if !refreshing[key] {
+						refreshing[key] = true
+						r := newRecorder(detach(ctx))
+						go func() {
+							next(r)
+							if entry := r.entry(); entry != nil {
+								opts.Store.Set(key, entry, policy.MaxAge+policy.StaleWhileRevalidate)
+							}
+							mu.Lock()
+							delete(refreshing, key)
+							mu.Unlock()
+						}()
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}
+
+		r := newRecorder(ctx)
+		next(r)
+		entry := r.entry()
+		if entry == nil {
+			r.flush(ctx)
+			return
+		}
+		if !strings.Contains(reqCache, "no-store") {
+			opts.Store.Set(key, entry, policy.MaxAge+policy.StaleWhileRevalidate)
+		}
+		write(ctx, policy, entry)
+	}
+}
+
+// operationPolicy returns the cache policy for an operation, or nil if it is
+// not cached.
+func operationPolicy(op *huma.Operation) *Policy {
+	if op == nil || op.Metadata == nil {
+		return nil
+	}
+	p, _ := op.Metadata["cache"].(*Policy)
+	return p
+}
+
+// Key returns the cache key for a request, made up of the method, URL, and
+// the values of the `Accept` header and the given request headers. It can be
+// used to delete entries from the store when a resource changes.
+func Key(ctx huma.Context, vary []string) string {
+	u := ctx.URL()
+	key := ctx.Method() + " " + u.RequestURI()
+	for _, name := range varyHeaders(vary) {
+		key += "\n" + http.CanonicalHeaderKey(name) + ": " + ctx.Header(name)
+	}
+	return key
+}
+
+// varyHeaders returns the request headers which affect the response, adding
+// `Accept` since it negotiates the response format, e.g. JSON or CBOR.
+func varyHeaders(vary []string) []string {
+	for _, name := range vary {
+		if strings.EqualFold(name, "Accept") {
+			return vary
+		}
+	}
+	return append([]string{"Accept"}, vary...)
+}
+
+// write sends a cached entry, or `304 Not Modified` if the client already has
+// the same response.
+func write(ctx huma.Context, policy *Policy, entry *Entry) {
+	age := now().Sub(entry.Stored)
+	for name, values := range entry.Header {
+		for i, value := range values {
+			if i == 0 {
+				ctx.SetHeader(name, value)
+			} else {
+				ctx.AppendHeader(name, value)
+			}
+		}
+	}
+	ctx.SetHeader("Cache-Control", policy.cacheControl())
+	ctx.SetHeader("Expires", entry.Stored.Add(policy.MaxAge).UTC().Format(http.TimeFormat))
+	ctx.SetHeader("Age", strconv.Itoa(int(age/time.Second)))
+
+	if notModified(ctx, entry) {
+		ctx.SetStatus(http.StatusNotModified)
+		return
+	}
+
+	ctx.SetStatus(entry.Status)
+	if ctx.Method() != http.MethodHead {
+		ctx.BodyWriter().Write(entry.Body)
+	}
+}
+
+// notModified returns whether the client's conditional request headers match
+// the entry.
+func notModified(ctx huma.Context, entry *Entry) bool {
+	if match := ctx.Header("If-None-Match"); match != "" {
+		etag := strings.TrimPrefix(entry.Header.Get("ETag"), "W/")
+		for _, m := range strings.Split(match, ",") {
+			m = strings.TrimPrefix(strings.TrimSpace(m), "W/")
+			if m == "*" || m == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if since := ctx.Header("If-Modified-Since"); since != "" {
+		t, err := http.ParseTime(since)
+		modified, err2 := http.ParseTime(entry.Header.Get("Last-Modified"))
+		if err == nil && err2 == nil {
+			return !modified.After(t)
+		}
+	}
+	return false
+}
+
+// recorder wraps a `huma.Context` to capture the response so it can be
+// stored before being sent.
+type recorder struct {
+	middleware.Wrapper
+	status   int
+	header   http.Header
+	body     bytes.Buffer
+	hijacked bool
+}
+
+func newRecorder(ctx huma.Context) *recorder {
+	return &recorder{Wrapper: middleware.Wrap(ctx), header: http.Header{}}
+}
+
+func (r *recorder) SetStatus(code int) {
+	r.status = code
+}
+
+func (r *recorder) SetHeader(name, value string) {
+	r.header.Set(name, value)
+}
+
+func (r *recorder) AppendHeader(name, value string) {
+	r.header.Add(name, value)
+}
+
+func (r *recorder) BodyWriter() io.Writer {
+	return r
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	return r.body.Write(p)
+}
+
+// Flush is a no-op as the response is buffered until the handler finishes.
+func (r *recorder) Flush() {}
+
+// Hijack takes over the underlying connection, after which the response is
+// neither cached nor sent.
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	nc, rw, err := r.Wrapper.Hijack()
+	if err == nil {
+		r.hijacked = true
+	}
+	return nc, rw, err
+}
+
+// entry returns a new entry for a successful response, or nil if the response
+// must not be cached.
+func (r *recorder) entry() *Entry {
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	cc := r.header.Get("Cache-Control")
+	if r.hijacked || status != http.StatusOK || r.header.Get("Set-Cookie") != "" || strings.Contains(cc, "no-store") || strings.Contains(cc, "private") {
+		return nil
+	}
+
+	entry := &Entry{
+		Status: status,
+		Header: r.header.Clone(),
+		Body:   append([]byte(nil), r.body.Bytes()...),
+		Stored: now(),
+	}
+	if entry.Header.Get("ETag") == "" {
+		h := fnv.New64a()
+		h.Write(entry.Body)
+		entry.Header.Set("ETag", `"`+strconv.FormatUint(h.Sum64(), 16)+`"`)
+	}
+	if entry.Header.Get("Last-Modified") == "" {
+		entry.Header.Set("Last-Modified", entry.Stored.UTC().Format(http.TimeFormat))
+	}
+	return entry
+}
+
+// flush sends a response which was not cached as-is.
+func (r *recorder) flush(ctx huma.Context) {
+	if r.hijacked {
+		return
+	}
+	for name, values := range r.header {
+		for i, value := range values {
+			if i == 0 {
+				ctx.SetHeader(name, value)
+			} else {
+				ctx.AppendHeader(name, value)
+			}
+		}
+	}
+	if r.status != 0 {
+		ctx.SetStatus(r.status)
+	}
+	if r.body.Len() > 0 {
+		ctx.BodyWriter().Write(r.body.Bytes())
+	}
+}
+
+// detachedContext is a copy of a request's context which can be used after
+// the request has finished to refresh a cached response in the background.
+type detachedContext struct {
+	op     *huma.Operation
+	ctx    context.Context
+	method string
+	host   string
+	url    url.URL
+	params map[string]string
+	header http.Header
+}
+
+// detach copies everything needed to refresh a response from the request.
+// The handler sees the request's context values, but not its cancelation.
+func detach(ctx huma.Context) huma.Context {
+	d := &detachedContext{
+		op:     ctx.Operation(),
+		ctx:    withoutCancel{ctx.Context()},
+		method: ctx.Method(),
+		host:   ctx.Host(),
+		url:    ctx.URL(),
+		params: map[string]string{},
+		header: http.Header{},
+	}
+	if d.op != nil {
+		for _, p := range d.op.Parameters {
+			if p.In == "path" {
+				d.params[p.Name] = ctx.Param(p.Name)
+			}
+		}
+	}
+	ctx.EachHeader(func(name, value string) {
+		d.header.Add(name, value)
+	})
+	// Background refreshes must not be answered with a 304.
+	d.header.Del("If-None-Match")
+	d.header.Del("If-Modified-Since")
+	return d
+}
+
+func (d *detachedContext) Operation() *huma.Operation      { return d.op }
+func (d *detachedContext) Context() context.Context        { return d.ctx }
+func (d *detachedContext) Method() string                  { return d.method }
+func (d *detachedContext) Host() string                    { return d.host }
+func (d *detachedContext) URL() url.URL                    { return d.url }
+func (d *detachedContext) Param(name string) string        { return d.params[name] }
+func (d *detachedContext) Query(name string) string        { return d.url.Query().Get(name) }
+func (d *detachedContext) Header(name string) string       { return d.header.Get(name) }
+func (d *detachedContext) BodyReader() io.Reader           { return http.NoBody }
+func (d *detachedContext) SetReadDeadline(time.Time) error { return nil }
+func (d *detachedContext) SetStatus(code int)              {}
+func (d *detachedContext) SetHeader(name, value string)    {}
+func (d *detachedContext) AppendHeader(name, value string) {}
+func (d *detachedContext) BodyWriter() io.Writer           { return io.Discard }
+
+func (d *detachedContext) EachHeader(cb func(name, value string)) {
+	for name, values := range d.header {
+		for _, value := range values {
+			cb(name, value)
+		}
+	}
+}
+
+func (d *detachedContext) GetMultipartForm() (*multipart.Form, error) {
+	return nil, http.ErrNotMultipart
+}
+
+// withoutCancel keeps a context's values but is never canceled.
+type withoutCancel struct {
+	parent context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (withoutCancel) Done() <-chan struct{}       { return nil }
+func (withoutCancel) Err() error                  { return nil }
+func (c withoutCancel) Value(key any) any         { return c.parent.Value(key) }
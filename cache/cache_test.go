@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func useClock(t *testing.T) *clock {
+	c := &clock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	now = c.now
+	t.Cleanup(func() { now = time.Now })
+	return c
+}
+
+type CountOutput struct {
+	Body struct {
+		Count int64  `json:"count"`
+		Lang  string `json:"lang,omitempty"`
+	}
+}
+
+func register(api huma.API, path string, policy *Policy, calls *int64) {
+	op := huma.Operation{
+		OperationID: "get" + path,
+		Method:      http.MethodGet,
+		Path:        path + "/{id}",
+	}
+	if policy != nil {
+		op.Metadata = map[string]any{"cache": policy}
+	}
+	huma.Register(api, op, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Lang string `header:"Accept-Language"`
+	}) (*CountOutput, error) {
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("not found")
+		}
+		out := &CountOutput{}
+		out.Body.Count = atomic.AddInt64(calls, 1)
+		out.Body.Lang = input.Lang
+		return out, nil
+	})
+}
+
+func TestCache(t *testing.T) {
+	c := useClock(t)
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{}))
+
+	var calls int64
+	register(api, "/cached", &Policy{MaxAge: time.Minute, StaleWhileRevalidate: time.Minute, Vary: []string{"Accept-Language"}}, &calls)
+
+	resp := api.Get("/cached/a")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "public, max-age=60, stale-while-revalidate=60", resp.Header().Get("Cache-Control"))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:05:05 GMT", resp.Header().Get("Expires"))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", resp.Header().Get("Last-Modified"))
+	assert.Equal(t, []string{"Accept", "Accept-Language"}, resp.Header().Values("Vary"))
+	assert.Equal(t, "0", resp.Header().Get("Age"))
+	etag := resp.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.JSONEq(t, `{"count": 1}`, resp.Body.String())
+
+	t.Run("hit", func(t *testing.T) {
+		c.advance(10 * time.Second)
+		resp := api.Get("/cached/a")
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "10", resp.Header().Get("Age"))
+		assert.Equal(t, etag, resp.Header().Get("ETag"))
+		assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"count": 1}`, resp.Body.String())
+	})
+
+	t.Run("not-modified", func(t *testing.T) {
+		resp := api.Get("/cached/a", "If-None-Match: "+etag)
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+		assert.Empty(t, resp.Body.String())
+
+		resp = api.Get("/cached/a", "If-Modified-Since: Tue, 02 Jan 2024 03:04:05 GMT")
+		assert.Equal(t, http.StatusNotModified, resp.Code)
+
+		resp = api.Get("/cached/a", "If-None-Match: \"other\"")
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("vary", func(t *testing.T) {
+		resp := api.Get("/cached/a", "Accept-Language: de")
+		assert.JSONEq(t, `{"count": 2, "lang": "de"}`, resp.Body.String())
+
+		resp = api.Get("/cached/a", "Accept-Language: de")
+		assert.JSONEq(t, `{"count": 2, "lang": "de"}`, resp.Body.String())
+	})
+
+	t.Run("no-cache", func(t *testing.T) {
+		resp := api.Get("/cached/a", "Cache-Control: no-cache")
+		assert.JSONEq(t, `{"count": 3}`, resp.Body.String())
+
+		// The refreshed response was stored.
+		resp = api.Get("/cached/a")
+		assert.JSONEq(t, `{"count": 3}`, resp.Body.String())
+	})
+
+	t.Run("stale-while-revalidate", func(t *testing.T) {
+		c.advance(90 * time.Second)
+		resp := api.Get("/cached/a")
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "90", resp.Header().Get("Age"))
+		assert.JSONEq(t, `{"count": 3}`, resp.Body.String())
+
+		assert.Eventually(t, func() bool {
+			return api.Get("/cached/a").Body.String() == `{"count":4}`+"\n"
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c.advance(5 * time.Minute)
+		resp := api.Get("/cached/a")
+		assert.JSONEq(t, `{"count": 5}`, resp.Body.String())
+	})
+
+	t.Run("errors-not-cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			resp := api.Get("/cached/missing")
+			assert.Equal(t, http.StatusNotFound, resp.Code)
+			assert.Empty(t, resp.Header().Get("Cache-Control"))
+		}
+	})
+}
+
+func TestCacheAccept(t *testing.T) {
+	useClock(t)
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(New(Options{}))
+
+	var calls int64
+	register(api, "/cached", &Policy{MaxAge: time.Minute}, &calls)
+
+	resp := api.Get("/cached/a")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Accept", resp.Header().Get("Vary"))
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+	// The response format is negotiated, so CBOR clients don't get the cached
+	// JSON response.
+	resp = api.Get("/cached/a", "Accept: application/cbor")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/cbor", resp.Header().Get("Content-Type"))
+	assert.EqualValues(t, 2, calls)
+
+	resp = api.Get("/cached/a")
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), `"count":1`)
+	assert.EqualValues(t, 2, calls)
+}
+
+func TestCachePrivate(t *testing.T) {
+	useClock(t)
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{}))
+
+	var calls int64
+	register(api, "/private", &Policy{MaxAge: time.Hour, Private: true}, &calls)
+
+	for i := 1; i <= 2; i++ {
+		resp := api.Get("/private/a")
+		assert.Equal(t, "private, max-age=3600", resp.Header().Get("Cache-Control"))
+		assert.Equal(t, "Tue, 02 Jan 2024 04:04:05 GMT", resp.Header().Get("Expires"))
+		assert.JSONEq(t, `{"count": `+strconv.Itoa(i)+`}`, resp.Body.String())
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{}))
+
+	var calls int64
+	register(api, "/uncached", nil, &calls)
+
+	for i := 1; i <= 2; i++ {
+		resp := api.Get("/uncached/a")
+		assert.Empty(t, resp.Header().Get("Cache-Control"))
+		assert.Empty(t, resp.Header().Get("ETag"))
+		assert.JSONEq(t, `{"count": `+strconv.Itoa(i)+`}`, resp.Body.String())
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	c := useClock(t)
+	s := NewMemoryStore(2)
+
+	s.Set("a", &Entry{Status: 200}, time.Minute)
+	s.Set("b", &Entry{Status: 200}, time.Minute)
+	_, ok := s.Get("a")
+	assert.True(t, ok)
+
+	// Adding a third entry evicts the least recently used one.
+	s.Set("c", &Entry{Status: 200}, time.Minute)
+	_, ok = s.Get("b")
+	assert.False(t, ok)
+	_, ok = s.Get("a")
+	assert.True(t, ok)
+
+	s.Delete("a")
+	_, ok = s.Get("a")
+	assert.False(t, ok)
+
+	c.advance(2 * time.Minute)
+	_, ok = s.Get("c")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { NewMemoryStore(0) })
+}
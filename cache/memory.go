@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// memoryStore is an in-memory store which evicts the least recently used
+// entries once full.
+type memoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+type memoryItem struct {
+	key     string
+	entry   *Entry
+	expires time.Time
+}
+
+// NewMemoryStore creates an in-memory store holding up to `maxEntries`
+// responses. The least recently used responses are evicted first.
+func NewMemoryStore(maxEntries int) Store {
+	if maxEntries <= 0 {
+		panic("cache: max entries must be positive")
+	}
+	return &memoryStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+func (s *memoryStore) Get(key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	item := el.Value.(*memoryItem)
+	if now().After(item.expires) {
+		s.remove(el)
+		return nil, false
+	}
+	s.lru.MoveToFront(el)
+	return item.entry, true
+}
+
+func (s *memoryStore) Set(key string, entry *Entry, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := &memoryItem{key: key, entry: entry, expires: now().Add(ttl)}
+	if el, ok := s.entries[key]; ok {
+		el.Value = item
+		s.lru.MoveToFront(el)
+		return
+	}
+	s.entries[key] = s.lru.PushFront(item)
+	for s.lru.Len() > s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *memoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+}
+
+func (s *memoryStore) remove(el *list.Element) {
+	s.lru.Remove(el)
+	delete(s.entries, el.Value.(*memoryItem).key)
+}
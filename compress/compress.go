@@ -0,0 +1,284 @@
+// Package compress provides a middleware for transparently compressing
+// response bodies with gzip when the client supports it via the
+// `Accept-Encoding` request header.
+//
+// Small responses and content types which do not benefit from compression,
+// such as images or event streams, are written as-is. Writers and buffers are
+// pooled and reused across requests.
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/middleware"
+	"github.com/danielgtaylor/huma/v2/negotiation"
+)
+
+// DefaultMinSize is the default minimum response body size in bytes before
+// compression is used. Smaller bodies are sent uncompressed as the overhead
+// is not worth it.
+var DefaultMinSize = 1024
+
+// DefaultContentTypes is the default list of compressible content type
+// prefixes.
+var DefaultContentTypes = []string{
+	"text/html",
+	"text/plain",
+	"text/css",
+	"text/csv",
+	"text/javascript",
+	"text/xml",
+	"application/json",
+	"application/problem+json",
+	"application/merge-patch+json",
+	"application/javascript",
+	"application/xml",
+	"application/yaml",
+	"application/vnd.oai.openapi",
+	"image/svg+xml",
+}
+
+var encodings = []string{"gzip"}
+
+// Options configures the compression middleware.
+type Options struct {
+	// MinSize is the minimum response body size in bytes before compression is
+	// used. Defaults to `DefaultMinSize`.
+	MinSize int
+
+	// ContentTypes is the list of content type prefixes which may be
+	// compressed, e.g. `application/json` also matches
+	// `application/json; charset=utf-8`. Defaults to `DefaultContentTypes`.
+	ContentTypes []string
+
+	// Level is the gzip compression level. Defaults to
+	// `gzip.DefaultCompression`.
+	Level int
+}
+
+// New creates a new compression middleware with the given options.
+//
+//	api.UseMiddleware(compress.New(compress.Options{}))
+func New(opts Options) func(ctx huma.Context, next func(huma.Context)) {
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultMinSize
+	}
+	if opts.ContentTypes == nil {
+		opts.ContentTypes = DefaultContentTypes
+	}
+	if opts.Level == 0 {
+		opts.Level = gzip.DefaultCompression
+	}
+	if _, err := gzip.NewWriterLevel(io.Discard, opts.Level); err != nil {
+		panic(err)
+	}
+
+	gzipPool := sync.Pool{
+		New: func() any {
+			gz, _ := gzip.NewWriterLevel(io.Discard, opts.Level)
+			return gz
+		},
+	}
+
+	writerPool := sync.Pool{
+		New: func() any {
+			return &writer{
+				opts:     &opts,
+				gzipPool: &gzipPool,
+				buf:      make([]byte, 0, opts.MinSize),
+			}
+		},
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		// The response may differ based on the request's accepted encodings,
+		// even when it ends up not being compressed.
+		ctx.AppendHeader("Vary", "Accept-Encoding")
+
+		if negotiation.SelectQValueFast(ctx.Header("Accept-Encoding"), encodings) == "" {
+			next(ctx)
+			return
+		}
+
+		w := writerPool.Get().(*writer)
+		w.Wrapper = middleware.Wrap(ctx)
+		next(w)
+		w.close()
+		w.reset()
+		writerPool.Put(w)
+	}
+}
+
+// writer wraps a `huma.Context` to delay writing the response status until
+// enough of the body has been written to decide whether to compress it.
+type writer struct {
+	middleware.Wrapper
+	opts     *Options
+	gzipPool *sync.Pool
+
+	status        int
+	contentType   string
+	contentLength string
+	encoded       bool
+	decided       bool
+	buf           []byte
+	gz            *gzip.Writer
+}
+
+func (w *writer) reset() {
+	w.Wrapper = middleware.Wrapper{}
+	w.status = 0
+	w.contentType = ""
+	w.contentLength = ""
+	w.encoded = false
+	w.decided = false
+	w.buf = w.buf[:0]
+	w.gz = nil
+}
+
+func (w *writer) SetStatus(code int) {
+	w.status = code
+}
+
+func (w *writer) SetHeader(name, value string) {
+	if w.header(name, value) {
+		w.Unwrap().SetHeader(name, value)
+	}
+}
+
+func (w *writer) AppendHeader(name, value string) {
+	if w.header(name, value) {
+		w.Unwrap().AppendHeader(name, value)
+	}
+}
+
+// header tracks headers which affect compression and returns whether the
+// header should be passed through to the response now.
+func (w *writer) header(name, value string) bool {
+	switch {
+	case strings.EqualFold(name, "Content-Type"):
+		w.contentType = value
+	case strings.EqualFold(name, "Content-Encoding"):
+		// The handler has already encoded the body itself.
+		w.encoded = true
+	case strings.EqualFold(name, "Content-Length"):
+		// Only known to be correct once we decide not to compress.
+		w.contentLength = value
+		return false
+	}
+	return true
+}
+
+func (w *writer) BodyWriter() io.Writer {
+	return w
+}
+
+// compressible returns whether the response can be compressed based on its
+// status, headers, and content type.
+func (w *writer) compressible() bool {
+	if w.encoded || w.status == http.StatusNoContent || w.status == http.StatusNotModified || w.status == http.StatusPartialContent {
+		return false
+	}
+	if w.contentLength != "" {
+		if length, err := strconv.Atoi(w.contentLength); err == nil && length < w.opts.MinSize {
+			return false
+		}
+	}
+	for _, prefix := range w.opts.ContentTypes {
+		if strings.HasPrefix(w.contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide writes the response status and headers, starting compression if
+// requested, then writes out any buffered body data.
+func (w *writer) decide(compress bool) error {
+	w.decided = true
+	if compress {
+		w.Unwrap().SetHeader("Content-Encoding", "gzip")
+		w.gz = w.gzipPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.Unwrap().BodyWriter())
+	} else if w.contentLength != "" {
+		w.Unwrap().SetHeader("Content-Length", w.contentLength)
+	}
+
+	if w.status != 0 {
+		w.Unwrap().SetStatus(w.status)
+	}
+
+	if len(w.buf) > 0 {
+		_, err := w.write(w.buf)
+		return err
+	}
+	return nil
+}
+
+func (w *writer) write(p []byte) (int, error) {
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.Unwrap().BodyWriter().Write(p)
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.decided {
+		return w.write(p)
+	}
+
+	if len(w.buf)+len(p) < w.opts.MinSize {
+		// Not enough data to decide yet, so buffer it.
+		w.buf = append(w.buf, p...)
+		return len(p), nil
+	}
+
+	if err := w.decide(w.compressible()); err != nil {
+		return 0, err
+	}
+	return w.write(p)
+}
+
+// Flush writes out any buffered data and flushes the underlying writer if
+// it supports flushing. Streaming responses are compressed based on their
+// content type since the final size is unknown.
+func (w *writer) Flush() {
+	if !w.decided {
+		w.decide(w.compressible())
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.Wrapper.Flush()
+}
+
+// Hijack takes over the underlying connection. Nothing is written once the
+// handler finishes, as the connection now belongs to it.
+func (w *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	nc, rw, err := w.Wrapper.Hijack()
+	if err == nil {
+		w.decided = true
+	}
+	return nc, rw, err
+}
+
+// close writes out any remaining response data once the handler has
+// finished. Bodies smaller than the minimum size are written uncompressed.
+func (w *writer) close() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		w.gz.Reset(io.Discard)
+		w.gzipPool.Put(w.gz)
+	}
+}
@@ -0,0 +1,137 @@
+package compress
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TextOutput struct {
+	ContentType string `header:"Content-Type"`
+	Body        []byte
+}
+
+func TestCompress(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{MinSize: 100}))
+
+	huma.Get(api, "/text", func(ctx context.Context, input *struct {
+		Size        int    `query:"size"`
+		ContentType string `query:"type" default:"text/plain"`
+	}) (*TextOutput, error) {
+		return &TextOutput{
+			ContentType: input.ContentType,
+			Body:        []byte(strings.Repeat("a", input.Size)),
+		}, nil
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		resp := api.Get("/text?size=1000", "Accept-Encoding: br, gzip;q=0.9")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+		assert.Empty(t, resp.Header().Get("Content-Length"))
+
+		gz, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 1000), string(body))
+	})
+
+	t.Run("not-accepted", func(t *testing.T) {
+		for _, header := range []string{"", "Accept-Encoding: br", "Accept-Encoding: gzip;q=0"} {
+			resp := api.Get("/text?size=1000", header)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Empty(t, resp.Header().Get("Content-Encoding"), header)
+			assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+			assert.Equal(t, 1000, resp.Body.Len())
+		}
+	})
+
+	t.Run("too-small", func(t *testing.T) {
+		resp := api.Get("/text?size=50", "Accept-Encoding: gzip")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get("Content-Encoding"))
+		assert.Equal(t, strings.Repeat("a", 50), resp.Body.String())
+	})
+
+	t.Run("content-type", func(t *testing.T) {
+		resp := api.Get("/text?size=1000&type=image/png", "Accept-Encoding: gzip")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get("Content-Encoding"))
+		assert.Equal(t, 1000, resp.Body.Len())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		resp := api.Get("/text?size=abc", "Accept-Encoding: gzip")
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+
+		gz, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "size")
+	})
+}
+
+func TestCompressStream(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{}))
+
+	huma.Get(api, "/stream", func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				ctx.SetHeader("Content-Type", "text/plain")
+				ctx.SetHeader("Content-Length", "5")
+				w := ctx.BodyWriter()
+				w.Write([]byte("hello"))
+				w.(http.Flusher).Flush()
+			},
+		}, nil
+	})
+
+	// Flushing forces a decision even though the body is small.
+	resp := api.Get("/stream", "Accept-Encoding: gzip")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, resp.Flushed)
+	assert.Empty(t, resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, "5", resp.Header().Get("Content-Length"))
+	assert.Equal(t, "hello", resp.Body.String())
+}
+
+func TestCompressPreEncoded(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{MinSize: 1}))
+
+	huma.Get(api, "/encoded", func(ctx context.Context, input *struct{}) (*struct {
+		ContentType     string `header:"Content-Type"`
+		ContentEncoding string `header:"Content-Encoding"`
+		Body            []byte
+	}, error) {
+		return &struct {
+			ContentType     string `header:"Content-Type"`
+			ContentEncoding string `header:"Content-Encoding"`
+			Body            []byte
+		}{"text/plain", "br", []byte("already compressed")}, nil
+	})
+
+	resp := api.Get("/encoded", "Accept-Encoding: gzip")
+	assert.Equal(t, "br", resp.Header().Get("Content-Encoding"))
+	assert.Equal(t, "already compressed", resp.Body.String())
+}
+
+func TestCompressBadLevel(t *testing.T) {
+	assert.Panics(t, func() {
+		New(Options{Level: 100})
+	})
+}
@@ -0,0 +1,54 @@
+package conditional
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ETag computes a strong ETag for a value from a hash of its JSON
+// representation. The result is unquoted, so it can be passed directly to
+// `Params.PreconditionFailed`. An empty string is returned if the value cannot
+// be marshaled.
+//
+//	if err := input.PreconditionFailed(conditional.ETag(thing), thing.Modified); err != nil {
+//		return nil, err
+//	}
+func ETag(v any) string {
+	h := fnv.New64a()
+	if err := json.NewEncoder(h).Encode(v); err != nil {
+		return ""
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// ETagTransformer is a response transformer which sets an `ETag` header on
+// successful `GET` and `HEAD` responses using `ETag` on the response body.
+// Operations which document their own `ETag` response header are skipped so
+// handlers remain in control. Add it to an API via `config.Transformers`.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Transformers = append(config.Transformers, conditional.ETagTransformer)
+func ETagTransformer(ctx huma.Context, status string, v any) (any, error) {
+	if v == nil || (ctx.Method() != http.MethodGet && ctx.Method() != http.MethodHead) {
+		return v, nil
+	}
+
+	if code, err := strconv.Atoi(status); err != nil || code < 200 || code >= 300 {
+		return v, nil
+	}
+
+	if op := ctx.Operation(); op != nil {
+		if resp := op.Responses[status]; resp != nil && resp.Headers["ETag"] != nil {
+			return v, nil
+		}
+	}
+
+	if etag := ETag(v); etag != "" {
+		ctx.SetHeader("ETag", `"`+etag+`"`)
+	}
+	return v, nil
+}
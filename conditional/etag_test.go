@@ -0,0 +1,70 @@
+package conditional
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type etagThing struct {
+	Name string `json:"name"`
+}
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, ETag(etagThing{"a"}), ETag(&etagThing{"a"}))
+	assert.NotEqual(t, ETag(etagThing{"a"}), ETag(etagThing{"b"}))
+	assert.Empty(t, ETag(make(chan int)))
+}
+
+func TestETagTransformer(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Transformers = append(config.Transformers, ETagTransformer)
+	_, api := humatest.New(t, config)
+
+	thing := &etagThing{Name: "test"}
+
+	huma.Get(api, "/thing", func(ctx context.Context, input *struct {
+		Params
+	}) (*struct{ Body *etagThing }, error) {
+		if err := input.PreconditionFailed(ETag(thing), time.Time{}); err != nil {
+			return nil, err
+		}
+		return &struct{ Body *etagThing }{thing}, nil
+	})
+
+	huma.Get(api, "/custom", func(ctx context.Context, input *struct{}) (*struct {
+		ETag string `header:"ETag"`
+		Body *etagThing
+	}, error) {
+		return &struct {
+			ETag string `header:"ETag"`
+			Body *etagThing
+		}{`"custom"`, thing}, nil
+	})
+
+	resp := api.Get("/thing")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	etag := resp.Header().Get("ETag")
+	assert.Equal(t, `"`+ETag(thing)+`"`, etag)
+
+	// The generated ETag can be used for conditional requests.
+	resp = api.Get("/thing", "If-None-Match: "+etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+
+	// Handlers which set their own ETag are left alone.
+	resp = api.Get("/custom")
+	assert.Equal(t, []string{`"custom"`}, resp.Header().Values("ETag"))
+
+	// Other methods don't get an ETag.
+	huma.Put(api, "/thing", func(ctx context.Context, input *struct{}) (*struct{ Body *etagThing }, error) {
+		return &struct{ Body *etagThing }{thing}, nil
+	})
+	resp = api.Put("/thing")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("ETag"))
+}
@@ -0,0 +1,158 @@
+// Package contract provides contract testing for Huma APIs by recording real
+// request/response pairs into golden files and replaying them against a new
+// build to catch behavioral regressions.
+//
+// While recording, each request and response is validated against the
+// operation's generated schemas, so recorded interactions always match the
+// documented contract. Replaying sends the recorded requests again and
+// compares the new responses with the recorded ones, validating them against
+// the current schemas.
+//
+//	// Record interactions, e.g. while running integration tests.
+//	recorder := contract.NewRecorder(api, contract.RecordOptions{})
+//	// ... make requests ...
+//	recorder.Save("testdata/contracts")
+//
+//	// Later, replay them against the new build.
+//	contract.Replay(t, api, "testdata/contracts", contract.ReplayOptions{})
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Interaction is a recorded request/response pair for an operation.
+type Interaction struct {
+	// Seq orders interactions across operations, so replays happen in the
+	// same order as they were recorded.
+	Seq      int      `json:"seq"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"headers,omitempty"`
+	Body   *Body       `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"headers,omitempty"`
+	Body   *Body       `json:"body,omitempty"`
+}
+
+// Body is a recorded message body. JSON bodies are stored as JSON to keep
+// golden files readable and diffable, while other bodies are stored as text.
+type Body struct {
+	JSON json.RawMessage `json:"json,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+// newBody creates a body from raw bytes, returning nil for an empty body.
+func newBody(b []byte) *Body {
+	if len(b) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if json.Compact(&buf, b) == nil {
+		return &Body{JSON: buf.Bytes()}
+	}
+	return &Body{Text: string(b)}
+}
+
+// Bytes returns the raw body.
+func (b *Body) Bytes() []byte {
+	if b == nil {
+		return nil
+	}
+	if b.JSON != nil {
+		return b.JSON
+	}
+	return []byte(b.Text)
+}
+
+// mediaType returns the media type from a `Content-Type` header without any
+// parameters.
+func mediaType(header http.Header) string {
+	ct := header.Get("Content-Type")
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	return ct
+}
+
+// isJSON returns whether the media type is JSON, including `+json` types
+// like `application/problem+json`.
+func isJSON(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// findContent returns the media type entry for the content type, falling
+// back to JSON for JSON-based content types.
+func findContent(content map[string]*huma.MediaType, mt string) *huma.MediaType {
+	if c := content[mt]; c != nil {
+		return c
+	}
+	if isJSON(mt) {
+		for name, c := range content {
+			if isJSON(name) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// validateBody validates a JSON message body against a schema, returning an
+// error for each validation failure.
+func validateBody(oapi *huma.OpenAPI, schema *huma.Schema, mode huma.ValidateMode, prefix string, body []byte) []error {
+	if schema == nil || len(body) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []error{fmt.Errorf("%s: invalid JSON: %w", prefix, err)}
+	}
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	pb.Push(prefix)
+	res := &huma.ValidateResult{}
+	huma.Validate(oapi.Components.Schemas, schema, pb, mode, value, res)
+	return res.Errors
+}
+
+// validate checks an interaction against the operation's schemas. Requests
+// are only validated when they succeeded, since rejecting invalid requests
+// is also part of the contract.
+func validate(oapi *huma.OpenAPI, op *huma.Operation, in *Interaction) []error {
+	var errs []error
+	if in.Response.Status < 400 && op.RequestBody != nil {
+		if c := findContent(op.RequestBody.Content, mediaType(in.Request.Header)); c != nil && isJSON(mediaType(in.Request.Header)) {
+			errs = append(errs, validateBody(oapi, c.Schema, huma.ModeWriteToServer, "request.body", in.Request.Body.Bytes())...)
+		}
+	}
+
+	resp := op.Responses[strconv.Itoa(in.Response.Status)]
+	if resp == nil {
+		resp = op.Responses["default"]
+	}
+	if resp == nil {
+		return append(errs, fmt.Errorf("response status %d is not documented", in.Response.Status))
+	}
+	mt := mediaType(in.Response.Header)
+	if c := findContent(resp.Content, mt); c != nil && isJSON(mt) {
+		errs = append(errs, validateBody(oapi, c.Schema, huma.ModeReadFromServer, "response.body", in.Response.Body.Bytes())...)
+	}
+	return errs
+}
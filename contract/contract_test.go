@@ -0,0 +1,222 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Thing struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name" minLength:"3"`
+	Kind      string    `json:"kind" enum:"small,large"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ThingOutput struct {
+	ETag string `header:"ETag"`
+	Body *Thing
+}
+
+// newAPI creates an API storing things in memory. The kind is returned for
+// all created things, allowing tests to change the API's behavior. If
+// recording, the recorder is returned too.
+func newAPI(t *testing.T, kind string, recording bool) (humatest.TestAPI, *Recorder) {
+	_, api := humatest.New(t)
+
+	var recorder *Recorder
+	if recording {
+		recorder = NewRecorder(api, RecordOptions{})
+	}
+
+	var mu sync.Mutex
+	things := map[string]*Thing{}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-thing",
+		Method:      http.MethodPost,
+		Path:        "/things",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name" minLength:"3"`
+		}
+	}) (*ThingOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		thing := &Thing{
+			ID:        fmt.Sprintf("t%d", len(things)+1),
+			Name:      input.Body.Name,
+			Kind:      kind,
+			CreatedAt: time.Now(),
+		}
+		things[thing.ID] = thing
+		return &ThingOutput{ETag: `"` + thing.ID + `"`, Body: thing}, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*ThingOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		thing := things[input.ID]
+		if thing == nil {
+			return nil, huma.Error404NotFound("thing not found")
+		}
+		return &ThingOutput{ETag: `"` + thing.ID + `"`, Body: thing}, nil
+	})
+
+	return api, recorder
+}
+
+// record records a few interactions against an API and saves them.
+func record(t *testing.T, dir string) *Recorder {
+	api, recorder := newAPI(t, "small", true)
+
+	resp := api.Post("/things", "Authorization: secret", map[string]any{"name": "Widget"})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, http.StatusUnprocessableEntity, api.Post("/things", map[string]any{"name": "x"}).Code)
+	assert.Equal(t, http.StatusOK, api.Get("/things/t1").Code)
+	assert.Equal(t, http.StatusNotFound, api.Get("/things/missing").Code)
+
+	require.NoError(t, recorder.Save(dir))
+	return recorder
+}
+
+func replayAPI(t *testing.T, kind string) humatest.TestAPI {
+	api, _ := newAPI(t, kind, false)
+	return api
+}
+
+type failures struct {
+	errors []string
+}
+
+func (f *failures) Helper() {}
+
+func (f *failures) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorder := record(t, dir)
+	assert.Empty(t, recorder.Violations())
+
+	b, err := os.ReadFile(filepath.Join(dir, "create-thing.json"))
+	require.NoError(t, err)
+	golden := string(b)
+	assert.Contains(t, golden, `"seq": 1`)
+	assert.Contains(t, golden, `"url": "/things"`)
+	assert.Contains(t, golden, `"json": {`)
+	assert.NotContains(t, golden, "secret")
+	assert.FileExists(t, filepath.Join(dir, "get-thing.json"))
+
+	opts := ReplayOptions{IgnoreFields: []string{"/createdAt"}}
+
+	// The same build passes.
+	f := &failures{}
+	Replay(f, replayAPI(t, "small"), dir, opts)
+	assert.Empty(t, f.errors)
+
+	// Without ignoring the timestamps, they differ.
+	f = &failures{}
+	Replay(f, replayAPI(t, "small"), dir, ReplayOptions{})
+	require.Len(t, f.errors, 2)
+	assert.Contains(t, f.errors[0], "create-thing POST /things: expected body")
+
+	// A behavior change is caught, along with responses which no longer
+	// match the schema.
+	f = &failures{}
+	Replay(f, replayAPI(t, "medium"), dir, opts)
+	assert.Len(t, f.errors, 4)
+	assert.Contains(t, strings.Join(f.errors, "\n"), `"kind":"medium"`)
+	assert.Contains(t, strings.Join(f.errors, "\n"), "response.body.kind")
+}
+
+func TestRecordViolations(t *testing.T) {
+	api, recorder := newAPI(t, "medium", true)
+
+	api.Post("/things", map[string]any{"name": "Widget"})
+
+	violations := recorder.Violations()
+	require.Len(t, violations, 1)
+	assert.Contains(t, violations[0].Error(), "POST /things: expected value to be one of")
+	assert.Contains(t, violations[0].Error(), "response.body.kind")
+}
+
+func TestReplayErrors(t *testing.T) {
+	api := replayAPI(t, "small")
+
+	f := &failures{}
+	Replay(f, api, t.TempDir(), ReplayOptions{})
+	require.Len(t, f.errors, 1)
+	assert.Contains(t, f.errors[0], "no recorded interactions found")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o644))
+	_, err := Verify(api, dir, ReplayOptions{})
+	assert.Error(t, err)
+}
+
+func TestRemoveField(t *testing.T) {
+	v := map[string]any{
+		"id":    "a",
+		"a/b":   1,
+		"items": []any{map[string]any{"id": 1, "name": "x"}, map[string]any{"id": 2}},
+	}
+	v = removeField(v, []string{"id"}).(map[string]any)
+	v = removeField(v, []string{"a~1b"}).(map[string]any)
+	v = removeField(v, []string{"items", "*", "id"}).(map[string]any)
+	assert.Equal(t, map[string]any{
+		"items": []any{map[string]any{"name": "x"}, map[string]any{}},
+	}, v)
+}
+
+func TestRecordSensitiveHeaders(t *testing.T) {
+	for _, item := range []struct {
+		name     string
+		opts     RecordOptions
+		recorded []string
+	}{
+		{"default", RecordOptions{}, nil},
+		{"opt-in", RecordOptions{RecordHeaders: []string{"cookie"}}, []string{"Cookie"}},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			_, api := humatest.New(t)
+			recorder := NewRecorder(api, item.opts)
+			huma.Get(api, "/session", func(ctx context.Context, input *struct{}) (*struct {
+				SetCookie string `header:"Set-Cookie"`
+			}, error) {
+				return &struct {
+					SetCookie string `header:"Set-Cookie"`
+				}{SetCookie: "session=abc"}, nil
+			})
+
+			api.Get("/session", "Authorization: secret", "Cookie: session=abc")
+
+			in := recorder.interactions["get-session"][0]
+			recorded := []string{}
+			for _, name := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+				if in.Request.Header.Get(name) != "" || in.Response.Header.Get(name) != "" {
+					recorded = append(recorded, name)
+				}
+			}
+			assert.ElementsMatch(t, item.recorded, recorded)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/middleware"
+)
+
+// SensitiveHeaders are never recorded unless listed in
+// `RecordOptions.RecordHeaders`, since recorded interactions are usually
+// committed and must not leak credentials.
+var SensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// RecordOptions configures recording.
+type RecordOptions struct {
+	// IgnoreHeaders lists request and response headers which are not
+	// recorded, e.g. `Date`, in addition to the `SensitiveHeaders`. Header
+	// names are not case sensitive.
+	IgnoreHeaders []string
+
+	// RecordHeaders lists `SensitiveHeaders` which are recorded anyway, e.g.
+	// `Set-Cookie` when the test only uses fake sessions. Header names are not
+	// case sensitive.
+	RecordHeaders []string
+}
+
+// Recorder records interactions flowing through an API. It is safe for
+// concurrent use.
+type Recorder struct {
+	oapi   *huma.OpenAPI
+	ignore map[string]bool
+
+	mu           sync.Mutex
+	seq          int
+	interactions map[string][]*Interaction
+	violations   []error
+}
+
+// NewRecorder creates a recorder and adds its middleware to the API. Every
+// request to an operation is recorded along with its response, and validated
+// against the operation's schemas. The `Authorization`, `Cookie`, and
+// `Set-Cookie` headers are left out unless opted into with `RecordHeaders`. Like other middleware, the recorder only
+// sees operations registered after it is created.
+func NewRecorder(api huma.API, opts RecordOptions) *Recorder {
+	r := &Recorder{
+		oapi:         api.OpenAPI(),
+		ignore:       map[string]bool{},
+		interactions: map[string][]*Interaction{},
+	}
+	for _, name := range SensitiveHeaders {
+		r.ignore[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, name := range opts.RecordHeaders {
+		delete(r.ignore, http.CanonicalHeaderKey(name))
+	}
+	for _, name := range opts.IgnoreHeaders {
+		r.ignore[http.CanonicalHeaderKey(name)] = true
+	}
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if op == nil || op.OperationID == "" {
+			next(ctx)
+			return
+		}
+
+		tc := &teeContext{Wrapper: middleware.Wrap(ctx), header: http.Header{}}
+		next(tc)
+		r.add(op, ctx, tc)
+	})
+
+	return r
+}
+
+// add records an interaction for an operation.
+func (r *Recorder) add(op *huma.Operation, ctx huma.Context, tc *teeContext) {
+	u := ctx.URL()
+	in := &Interaction{
+		Request: Request{
+			Method: ctx.Method(),
+			URL:    u.RequestURI(),
+			Header: http.Header{},
+			Body:   newBody(tc.reqBody.Bytes()),
+		},
+		Response: Response{
+			Status: tc.status,
+			Header: http.Header{},
+			Body:   newBody(tc.respBody.Bytes()),
+		},
+	}
+	if in.Response.Status == 0 {
+		in.Response.Status = http.StatusOK
+	}
+	ctx.EachHeader(func(name, value string) {
+		if name = http.CanonicalHeaderKey(name); !r.ignore[name] {
+			in.Request.Header.Add(name, value)
+		}
+	})
+	for name, values := range tc.header {
+		if !r.ignore[name] {
+			in.Response.Header[name] = values
+		}
+	}
+
+	errs := validate(r.oapi, op, in)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	in.Seq = r.seq
+	r.interactions[op.OperationID] = append(r.interactions[op.OperationID], in)
+	for _, err := range errs {
+		r.violations = append(r.violations, fmt.Errorf("%s %s: %w", in.Request.Method, in.Request.URL, err))
+	}
+}
+
+// Violations returns any schema validation failures of recorded requests or
+// responses.
+func (r *Recorder) Violations() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.violations...)
+}
+
+// Save writes the recorded interactions as golden files into the directory,
+// one `<operation-id>.json` file per recorded operation. Existing files for
+// recorded operations are replaced, while other files are left as-is.
+func (r *Recorder) Save(dir string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(r.interactions))
+	for id := range r.interactions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r.interactions[id]); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, id+".json"), buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teeContext wraps a `huma.Context` to capture the request body and response
+// as they are read & written by the handler.
+type teeContext struct {
+	middleware.Wrapper
+	reqBody  bytes.Buffer
+	status   int
+	header   http.Header
+	respBody bytes.Buffer
+}
+
+func (c *teeContext) BodyReader() io.Reader {
+	return io.TeeReader(c.Unwrap().BodyReader(), &c.reqBody)
+}
+
+func (c *teeContext) SetStatus(code int) {
+	c.status = code
+	c.Unwrap().SetStatus(code)
+}
+
+func (c *teeContext) SetHeader(name, value string) {
+	c.header.Set(name, value)
+	c.Unwrap().SetHeader(name, value)
+}
+
+func (c *teeContext) AppendHeader(name, value string) {
+	c.header.Add(name, value)
+	c.Unwrap().AppendHeader(name, value)
+}
+
+func (c *teeContext) BodyWriter() io.Writer {
+	return c
+}
+
+func (c *teeContext) Write(p []byte) (int, error) {
+	n, err := c.Unwrap().BodyWriter().Write(p)
+	c.respBody.Write(p[:n])
+	return n, err
+}
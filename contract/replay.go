@@ -0,0 +1,234 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// ReplayOptions configures how replayed responses are compared with the
+// recorded ones.
+type ReplayOptions struct {
+	// IgnoreHeaders lists response headers which are not compared, e.g.
+	// `Date` or `ETag`. Header names are not case sensitive.
+	IgnoreHeaders []string
+
+	// IgnoreFields lists JSON Pointers to response body fields which are not
+	// compared, like generated IDs or timestamps. A `*` part matches any
+	// array index or object property, e.g. `/items/*/id`.
+	IgnoreFields []string
+}
+
+// TB is the subset of `testing.TB` used to report replay failures.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Replay replays the interactions recorded in the directory against the API
+// and reports every difference from the recorded responses, as well as any
+// schema validation failures of the new responses, as test errors.
+func Replay(tb TB, api huma.API, dir string, opts ReplayOptions) {
+	tb.Helper()
+	errs, err := Verify(api, dir, opts)
+	if err != nil {
+		tb.Errorf("unable to replay %s: %v", dir, err)
+		return
+	}
+	for _, err := range errs {
+		tb.Errorf("%v", err)
+	}
+}
+
+// recorded is an interaction loaded from a golden file.
+type recorded struct {
+	operationID string
+	*Interaction
+}
+
+// Verify replays the interactions recorded in the directory against the API
+// and returns every difference from the recorded responses, as well as any
+// schema validation failures of the new responses. Interactions are replayed
+// in the order they were recorded.
+func Verify(api huma.API, dir string, opts ReplayOptions) ([]error, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded interactions found")
+	}
+	sort.Strings(files)
+
+	var all []recorded
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var interactions []*Interaction
+		if err := json.Unmarshal(b, &interactions); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		id := strings.TrimSuffix(filepath.Base(file), ".json")
+		for _, in := range interactions {
+			all = append(all, recorded{operationID: id, Interaction: in})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Seq < all[j].Seq
+	})
+
+	ignoreHeaders := map[string]bool{}
+	for _, name := range opts.IgnoreHeaders {
+		ignoreHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+	ignoreFields := make([][]string, 0, len(opts.IgnoreFields))
+	for _, ptr := range opts.IgnoreFields {
+		ignoreFields = append(ignoreFields, strings.Split(strings.TrimPrefix(ptr, "/"), "/"))
+	}
+
+	oapi := api.OpenAPI()
+	var errs []error
+	for _, rec := range all {
+		got := replay(api, rec.Interaction)
+		prefix := fmt.Sprintf("%s %s %s", rec.operationID, rec.Request.Method, rec.Request.URL)
+
+		if got.Response.Status != rec.Response.Status {
+			errs = append(errs, fmt.Errorf("%s: expected status %d but got %d", prefix, rec.Response.Status, got.Response.Status))
+		}
+
+		names := make([]string, 0, len(rec.Response.Header))
+		for name := range rec.Response.Header {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ignoreHeaders[http.CanonicalHeaderKey(name)] {
+				continue
+			}
+			expected := strings.Join(rec.Response.Header.Values(name), ", ")
+			actual := strings.Join(got.Response.Header.Values(name), ", ")
+			if expected != actual {
+				errs = append(errs, fmt.Errorf("%s: expected header %s to be %q but got %q", prefix, name, expected, actual))
+			}
+		}
+
+		if err := compareBodies(rec.Response.Body, got.Response.Body, ignoreFields); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+
+		if op := findOperation(oapi, rec.operationID); op != nil {
+			for _, err := range validate(oapi, op, got) {
+				errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+			}
+		}
+	}
+	return errs, nil
+}
+
+// replay sends the recorded request to the API and returns the interaction
+// with the new response.
+func replay(api huma.API, in *Interaction) *Interaction {
+	req := httptest.NewRequest(in.Request.Method, in.Request.URL, bytes.NewReader(in.Request.Body.Bytes()))
+	for name, values := range in.Request.Header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	api.Adapter().ServeHTTP(w, req)
+
+	return &Interaction{
+		Request: in.Request,
+		Response: Response{
+			Status: w.Code,
+			Header: w.Header(),
+			Body:   newBody(w.Body.Bytes()),
+		},
+	}
+}
+
+// findOperation returns the documented operation with the ID, if any.
+func findOperation(oapi *huma.OpenAPI, id string) *huma.Operation {
+	for _, item := range oapi.Paths {
+		for _, op := range []*huma.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch, item.Trace} {
+			if op != nil && op.OperationID == id {
+				return op
+			}
+		}
+	}
+	return nil
+}
+
+// compareBodies compares a recorded body with a replayed one. JSON bodies are
+// compared by value, ignoring the given fields.
+func compareBodies(expected, actual *Body, ignore [][]string) error {
+	if expected != nil && actual != nil && expected.JSON != nil && actual.JSON != nil {
+		var e, a any
+		if err := json.Unmarshal(expected.JSON, &e); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(actual.JSON, &a); err != nil {
+			return err
+		}
+		for _, parts := range ignore {
+			e = removeField(e, parts)
+			a = removeField(a, parts)
+		}
+		if !reflect.DeepEqual(e, a) {
+			eb, _ := json.Marshal(e)
+			ab, _ := json.Marshal(a)
+			return fmt.Errorf("expected body %s but got %s", eb, ab)
+		}
+		return nil
+	}
+
+	if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+		return fmt.Errorf("expected body %q but got %q", expected.Bytes(), actual.Bytes())
+	}
+	return nil
+}
+
+// removeField removes the field at the JSON Pointer parts from a decoded
+// JSON value.
+func removeField(v any, parts []string) any {
+	if len(parts) == 0 {
+		return v
+	}
+	part := strings.ReplaceAll(strings.ReplaceAll(parts[0], "~1", "/"), "~0", "~")
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if part != "*" && k != part {
+				continue
+			}
+			if len(parts) == 1 {
+				delete(v, k)
+			} else {
+				v[k] = removeField(item, parts[1:])
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if part != "*" && strconv.Itoa(i) != part {
+				continue
+			}
+			if len(parts) == 1 {
+				// Array items can't be removed without shifting the others.
+				v[i] = nil
+			} else {
+				v[i] = removeField(item, parts[1:])
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,311 @@
+// Package cors provides Cross-Origin Resource Sharing (CORS) support for Huma
+// APIs. Allowed origins, methods, headers, credentials, and the preflight max
+// age can be configured for the whole API and overridden per operation.
+//
+// Because the API knows its route table, `OPTIONS` preflight requests are
+// answered automatically for every registered path, advertising only the
+// methods which actually exist for that path. Each operation's CORS options
+// are documented in the OpenAPI via an `x-cors` extension.
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Options configures which cross-origin requests are allowed.
+type Options struct {
+	// AllowOrigins is the list of origins which may make cross-origin
+	// requests, e.g. `https://example.com`. A single `*` wildcard may be used
+	// within an origin to match subdomains, e.g. `https://*.example.com`, and
+	// the special value `*` allows any origin.
+	AllowOrigins []string
+
+	// AllowMethods is the list of methods allowed for cross-origin requests.
+	// If empty, all methods registered for the requested path are allowed.
+	AllowMethods []string
+
+	// AllowHeaders is the list of request headers allowed for cross-origin
+	// requests. If empty, the headers requested by the preflight request are
+	// allowed.
+	AllowHeaders []string
+
+	// ExposeHeaders is the list of response headers which browsers may expose
+	// to the client.
+	ExposeHeaders []string
+
+	// AllowCredentials sets whether cookies & authorization headers may be
+	// sent with cross-origin requests. It can't be combined with the `*`
+	// origin, as any site could then make requests using the user's
+	// credentials, so the allowed origins must be listed instead.
+	AllowCredentials bool
+
+	// MaxAge is the number of seconds browsers may cache preflight responses.
+	// Zero omits the header, leaving the browser default.
+	MaxAge int
+}
+
+// allowsOrigin returns whether the given request origin is allowed.
+func (o *Options) allowsOrigin(origin string) bool {
+	for _, allowed := range o.AllowOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(allowed, "*"); ok {
+			if len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowOrigin returns the `Access-Control-Allow-Origin` value for a request
+// origin, which is `*` when any origin is allowed.
+func (o *Options) allowOrigin(origin string) string {
+	for _, allowed := range o.AllowOrigins {
+		if allowed == "*" {
+			return "*"
+		}
+	}
+	return origin
+}
+
+// validate panics if the options would allow any site to make requests with
+// the user's credentials.
+func (o *Options) validate() {
+	if !o.AllowCredentials {
+		return
+	}
+	for _, allowed := range o.AllowOrigins {
+		if allowed == "*" {
+			panic("cors: the * origin can't be used with AllowCredentials, list the allowed origins instead")
+		}
+	}
+}
+
+// Enable adds CORS support to the API. Cross-origin requests to registered
+// operations get the appropriate response headers, and an `OPTIONS`
+// preflight handler is registered for every path. It must be called before
+// registering operations so that they use the CORS middleware, and panics
+// otherwise.
+//
+//	cors.Enable(api, cors.Options{
+//		AllowOrigins: []string{"https://example.com"},
+//		MaxAge:       3600,
+//	})
+//
+// The options for a single operation can be overridden by setting the `cors`
+// operation metadata field to an `*cors.Options`, or disabled by setting it
+// to `false`.
+//
+// Operations which explicitly handle `OPTIONS` must be registered before
+// calling `Enable`, otherwise they would conflict with the generated
+// preflight handler.
+func Enable(api huma.API, opts Options) {
+	if len(opts.AllowOrigins) == 0 {
+		panic("cors: at least one allowed origin is required")
+	}
+	opts.validate()
+
+	oapi := api.OpenAPI()
+	for path, item := range oapi.Paths {
+		// Middleware is bound when an operation is registered, so existing
+		// operations would silently never get CORS headers.
+		for _, op := range operations(item) {
+			panic(fmt.Errorf("cors: %s %s was registered before enabling CORS, call Enable before registering operations", op.Method, path))
+		}
+	}
+
+	preflights := map[string]bool{}
+	addPreflight := func(path string) {
+		if preflights[path] {
+			return
+		}
+		if item := oapi.Paths[path]; item != nil && item.Options != nil {
+			// The user handles OPTIONS requests for this path themselves.
+			return
+		}
+		preflights[path] = true
+		api.Adapter().Handle(&huma.Operation{
+			Method: http.MethodOptions,
+			Path:   path,
+			Hidden: true,
+		}, func(ctx huma.Context) {
+			preflight(oapi, &opts, path, ctx)
+		})
+	}
+
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		if op.Method == http.MethodOptions {
+			if preflights[op.Path] {
+				panic(fmt.Errorf("cors: OPTIONS operation for %s conflicts with the preflight handler, register it before enabling CORS", op.Path))
+			}
+		} else {
+			document(op, &opts)
+		}
+		addPreflight(op.Path)
+	})
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		// Responses differ by origin, so caches must take it into account.
+		ctx.AppendHeader("Vary", "Origin")
+
+		origin := ctx.Header("Origin")
+		if origin == "" {
+			next(ctx)
+			return
+		}
+
+		if o := operationOptions(ctx.Operation(), &opts); o != nil && o.allowsOrigin(origin) {
+			ctx.SetHeader("Access-Control-Allow-Origin", o.allowOrigin(origin))
+			if o.AllowCredentials {
+				ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+			}
+			if len(o.ExposeHeaders) > 0 {
+				ctx.SetHeader("Access-Control-Expose-Headers", strings.Join(o.ExposeHeaders, ", "))
+			}
+		}
+		next(ctx)
+	})
+}
+
+// operationOptions returns the CORS options for an operation, or `nil` if
+// CORS has been disabled for it.
+func operationOptions(op *huma.Operation, opts *Options) *Options {
+	if op == nil || op.Metadata == nil {
+		return opts
+	}
+	switch v := op.Metadata["cors"].(type) {
+	case *Options:
+		return v
+	case bool:
+		if !v {
+			return nil
+		}
+	}
+	return opts
+}
+
+// document describes an operation's CORS options in the OpenAPI.
+func document(op *huma.Operation, opts *Options) {
+	o := operationOptions(op, opts)
+	if o == nil {
+		return
+	}
+	o.validate()
+
+	ext := map[string]any{
+		"allowOrigins": o.AllowOrigins,
+	}
+	if len(o.AllowMethods) > 0 {
+		ext["allowMethods"] = o.AllowMethods
+	}
+	if len(o.AllowHeaders) > 0 {
+		ext["allowHeaders"] = o.AllowHeaders
+	}
+	if len(o.ExposeHeaders) > 0 {
+		ext["exposeHeaders"] = o.ExposeHeaders
+	}
+	if o.AllowCredentials {
+		ext["allowCredentials"] = true
+	}
+	if o.MaxAge > 0 {
+		ext["maxAge"] = o.MaxAge
+	}
+	if op.Extensions == nil {
+		op.Extensions = map[string]any{}
+	}
+	op.Extensions["x-cors"] = ext
+}
+
+// operations returns the operations registered for a path.
+func operations(item *huma.PathItem) []*huma.Operation {
+	ops := []*huma.Operation{}
+	if item == nil {
+		return ops
+	}
+	for _, op := range []*huma.Operation{item.Get, item.Head, item.Post, item.Put, item.Patch, item.Delete, item.Trace} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// preflight answers an `OPTIONS` request for a path. Requests which are not
+// allowed get a response without any CORS headers, which the browser will
+// then reject.
+func preflight(oapi *huma.OpenAPI, opts *Options, path string, ctx huma.Context) {
+	ops := operations(oapi.Paths[path])
+
+	origin := ctx.Header("Origin")
+	method := ctx.Header("Access-Control-Request-Method")
+	if origin == "" || method == "" {
+		// Not a CORS preflight, so just describe the available methods.
+		allow := make([]string, 0, len(ops)+1)
+		for _, op := range ops {
+			allow = append(allow, op.Method)
+		}
+		allow = append(allow, http.MethodOptions)
+		ctx.SetHeader("Allow", strings.Join(allow, ", "))
+		ctx.SetStatus(http.StatusNoContent)
+		return
+	}
+
+	ctx.AppendHeader("Vary", "Origin")
+	ctx.AppendHeader("Vary", "Access-Control-Request-Method")
+	ctx.AppendHeader("Vary", "Access-Control-Request-Headers")
+
+	var op *huma.Operation
+	for _, candidate := range ops {
+		if candidate.Method == strings.ToUpper(method) {
+			op = candidate
+			break
+		}
+	}
+	o := operationOptions(op, opts)
+	if op == nil || o == nil || !o.allowsOrigin(origin) {
+		ctx.SetStatus(http.StatusNoContent)
+		return
+	}
+
+	allowed := o.AllowMethods
+	if len(allowed) == 0 {
+		for _, candidate := range ops {
+			allowed = append(allowed, candidate.Method)
+		}
+	}
+	found := false
+	for _, m := range allowed {
+		if strings.EqualFold(m, op.Method) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		ctx.SetStatus(http.StatusNoContent)
+		return
+	}
+
+	ctx.SetHeader("Access-Control-Allow-Origin", o.allowOrigin(origin))
+	ctx.SetHeader("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+	if len(o.AllowHeaders) > 0 {
+		ctx.SetHeader("Access-Control-Allow-Headers", strings.Join(o.AllowHeaders, ", "))
+	} else if headers := ctx.Header("Access-Control-Request-Headers"); headers != "" {
+		ctx.SetHeader("Access-Control-Allow-Headers", headers)
+	}
+	if o.AllowCredentials {
+		ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+	}
+	if o.MaxAge > 0 {
+		ctx.SetHeader("Access-Control-Max-Age", strconv.Itoa(o.MaxAge))
+	}
+	ctx.SetStatus(http.StatusNoContent)
+}
@@ -0,0 +1,274 @@
+package cors
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type thingOutput struct {
+	Body struct {
+		ID string `json:"id"`
+	}
+}
+
+func registerThings(api huma.API, metadata map[string]any) {
+	handler := func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*thingOutput, error) {
+		resp := &thingOutput{}
+		resp.Body.ID = input.ID
+		return resp, nil
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, handler)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-thing",
+		Method:      http.MethodDelete,
+		Path:        "/things/{id}",
+		Metadata:    metadata,
+	}, handler)
+}
+
+func TestCORS(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Options{
+		AllowOrigins:  []string{"https://example.com", "https://*.example.org"},
+		ExposeHeaders: []string{"ETag"},
+		MaxAge:        600,
+	})
+	registerThings(api, nil)
+
+	// Regular cross-origin request.
+	resp := api.Get("/things/abc", "Origin: https://example.com")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "https://example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "ETag", resp.Header().Get("Access-Control-Expose-Headers"))
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Origin", resp.Header().Get("Vary"))
+
+	// Wildcard subdomains.
+	resp = api.Get("/things/abc", "Origin: https://app.example.org")
+	assert.Equal(t, "https://app.example.org", resp.Header().Get("Access-Control-Allow-Origin"))
+
+	// Disallowed origins still get a response, but without CORS headers.
+	resp = api.Get("/things/abc", "Origin: https://evil.com")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	// Same-origin requests are unaffected.
+	resp = api.Get("/things/abc")
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	// Preflight requests are answered with the methods for the path.
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: DELETE",
+		"Access-Control-Request-Headers: Authorization",
+	)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "https://example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, DELETE", resp.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization", resp.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", resp.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, resp.Header().Values("Vary"))
+
+	// Preflight for a method which doesn't exist.
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: PUT",
+	)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	// Preflight from a disallowed origin.
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://evil.com",
+		"Access-Control-Request-Method: GET",
+	)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	// Plain OPTIONS requests describe the path.
+	resp = api.Do(http.MethodOptions, "/things/abc")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "GET, DELETE, OPTIONS", resp.Header().Get("Allow"))
+
+	// Preflight routes are not documented, but the CORS options are.
+	item := api.OpenAPI().Paths["/things/{id}"]
+	assert.Nil(t, item.Options)
+	assert.Equal(t, map[string]any{
+		"allowOrigins":  []string{"https://example.com", "https://*.example.org"},
+		"exposeHeaders": []string{"ETag"},
+		"maxAge":        600,
+	}, item.Get.Extensions["x-cors"])
+}
+
+func TestCORSCredentials(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Options{
+		AllowOrigins:     []string{"https://example.com"},
+		AllowMethods:     []string{http.MethodGet},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+	})
+	registerThings(api, nil)
+
+	resp := api.Get("/things/abc", "Origin: https://example.com")
+	assert.Equal(t, "https://example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", resp.Header().Get("Access-Control-Allow-Credentials"))
+
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: GET",
+		"Access-Control-Request-Headers: X-Custom",
+	)
+	assert.Equal(t, "https://example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET", resp.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", resp.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "true", resp.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, resp.Header().Get("Access-Control-Max-Age"))
+
+	// Methods which aren't allowed fail the preflight.
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: DELETE",
+	)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Options{AllowOrigins: []string{"*"}})
+	registerThings(api, nil)
+
+	resp := api.Get("/things/abc", "Origin: https://example.com")
+	assert.Equal(t, "*", resp.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSPerOperation(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Options{AllowOrigins: []string{"https://example.com"}})
+	registerThings(api, map[string]any{
+		"cors": &Options{AllowOrigins: []string{"https://admin.example.com"}},
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "internal",
+		Method:      http.MethodGet,
+		Path:        "/internal",
+		Metadata:    map[string]any{"cors": false},
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Delete("/things/abc", "Origin: https://example.com")
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	resp = api.Delete("/things/abc", "Origin: https://admin.example.com")
+	assert.Equal(t, "https://admin.example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://admin.example.com",
+		"Access-Control-Request-Method: DELETE",
+	)
+	assert.Equal(t, "https://admin.example.com", resp.Header().Get("Access-Control-Allow-Origin"))
+
+	resp = api.Do(http.MethodOptions, "/things/abc",
+		"Origin: https://admin.example.com",
+		"Access-Control-Request-Method: GET",
+	)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	resp = api.Get("/internal", "Origin: https://example.com")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+
+	resp = api.Do(http.MethodOptions, "/internal",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: GET",
+	)
+	assert.Empty(t, resp.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSExistingOptions(t *testing.T) {
+	_, api := humatest.New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "options-custom",
+		Method:      http.MethodOptions,
+		Path:        "/custom",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	Enable(api, Options{AllowOrigins: []string{"*"}})
+
+	// The custom handler is used instead of the preflight handler.
+	resp := api.Do(http.MethodOptions, "/custom",
+		"Origin: https://example.com",
+		"Access-Control-Request-Method: GET",
+	)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get("Allow"))
+
+	// Registering an OPTIONS operation first for a new path is fine.
+	huma.Register(api, huma.Operation{
+		OperationID: "options-other",
+		Method:      http.MethodOptions,
+		Path:        "/other",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	// But not once the path has a generated preflight handler.
+	registerThings(api, nil)
+	assert.Panics(t, func() {
+		huma.Register(api, huma.Operation{
+			OperationID: "options-thing",
+			Method:      http.MethodOptions,
+			Path:        "/things/{id}",
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+func TestCORSAnyOriginCredentials(t *testing.T) {
+	_, api := humatest.New(t)
+	assert.PanicsWithValue(t, "cors: the * origin can't be used with AllowCredentials, list the allowed origins instead", func() {
+		Enable(api, Options{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	})
+
+	_, api = humatest.New(t)
+	Enable(api, Options{AllowOrigins: []string{"https://example.com"}})
+	assert.Panics(t, func() {
+		registerThings(api, map[string]any{
+			"cors": &Options{AllowOrigins: []string{"*"}, AllowCredentials: true},
+		})
+	})
+}
+
+func TestCORSRegisteredBefore(t *testing.T) {
+	_, api := humatest.New(t)
+	registerThings(api, nil)
+
+	assert.PanicsWithError(t, "cors: GET /things/{id} was registered before enabling CORS, call Enable before registering operations", func() {
+		Enable(api, Options{AllowOrigins: []string{"https://example.com"}})
+	})
+}
+
+func TestCORSNoOrigins(t *testing.T) {
+	_, api := humatest.New(t)
+	assert.Panics(t, func() {
+		Enable(api, Options{})
+	})
+}
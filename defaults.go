@@ -2,6 +2,7 @@ package huma
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"io"
 
 	"github.com/fxamacker/cbor/v2"
@@ -49,6 +50,25 @@ var DefaultCBORFormat = Format{
 	Unmarshal: cbor.Unmarshal,
 }
 
+// DefaultXMLFormat is an XML formatter using the `xml` struct tags, which can
+// be set in the API's `Config.Formats` map for clients which require XML. It
+// is not enabled by `DefaultConfig`.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Formats["application/xml"] = huma.DefaultXMLFormat
+//	config.Formats["xml"] = huma.DefaultXMLFormat
+var DefaultXMLFormat = Format{
+	Marshal: func(w io.Writer, v any) error {
+		return xml.NewEncoder(w).Encode(v)
+	},
+	Unmarshal: func(data []byte, v any) error {
+		if _, ok := v.(*any); ok {
+			return ErrTypedOnly
+		}
+		return xml.Unmarshal(data, v)
+	},
+}
+
 // DefaultConfig returns a default configuration for a new API. It is a good
 // starting point for creating your own configuration. It supports JSON and
 // CBOR formats out of the box. The registry uses references for structs and
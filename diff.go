@@ -0,0 +1,485 @@
+package huma
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffMethods lists the path item methods in the order they are compared.
+var diffMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Change describes a single difference between two OpenAPI documents.
+type Change struct {
+	// Breaking is true if existing clients may stop working due to the change.
+	Breaking bool `json:"breaking"`
+
+	// Operation is the affected operation, e.g. `GET /things/{id}`.
+	Operation string `json:"operation"`
+
+	// Location is where in the operation the change occurred, e.g.
+	// `request.body.name`, `query.limit`, or `response.200.body.tags[]`. It
+	// is empty if the operation itself was added or removed.
+	Location string `json:"location,omitempty"`
+
+	// Message is a human-readable description of the change.
+	Message string `json:"message"`
+}
+
+// String returns a one-line description of the change.
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s: %s", kind, c.Operation, c.Message)
+	}
+	return fmt.Sprintf("%s: %s %s: %s", kind, c.Operation, c.Location, c.Message)
+}
+
+// DiffReport is the result of comparing two OpenAPI documents.
+type DiffReport struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns only the breaking changes.
+func (r *DiffReport) Breaking() []Change {
+	breaking := []Change{}
+	for _, c := range r.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// HasBreaking returns whether any changes are breaking.
+func (r *DiffReport) HasBreaking() bool {
+	return len(r.Breaking()) > 0
+}
+
+// Diff compares two OpenAPI documents and classifies each change to the
+// operations, parameters, request bodies, and responses as breaking or
+// non-breaking for existing clients. This can be used to gate deployments in
+// CI, for example by comparing a committed spec against the current one.
+//
+// Schemas are compared based on how they are used. Clients send request
+// schemas, so new required properties, narrowed enums, and tightened
+// constraints are breaking. Clients receive response schemas, so properties
+// which are no longer required and new enum values are breaking. Removed
+// properties are breaking in both.
+//
+//	report, err := huma.Diff(oldSpec, api.OpenAPI())
+//	for _, change := range report.Breaking() {
+//		fmt.Println(change)
+//	}
+func Diff(oldSpec, newSpec *OpenAPI) (*DiffReport, error) {
+	oldJSON, err := json.Marshal(oldSpec)
+	if err != nil {
+		return nil, err
+	}
+	newJSON, err := json.Marshal(newSpec)
+	if err != nil {
+		return nil, err
+	}
+	return DiffJSON(oldJSON, newJSON)
+}
+
+// DiffJSON compares two OpenAPI documents in JSON format, e.g. previously
+// generated spec files. See `Diff` for details.
+func DiffJSON(oldSpec, newSpec []byte) (*DiffReport, error) {
+	d := &differ{report: &DiffReport{Changes: []Change{}}, seen: map[string]bool{}}
+	if err := json.Unmarshal(oldSpec, &d.oldDoc); err != nil {
+		return nil, fmt.Errorf("unable to parse old spec: %w", err)
+	}
+	if err := json.Unmarshal(newSpec, &d.newDoc); err != nil {
+		return nil, fmt.Errorf("unable to parse new spec: %w", err)
+	}
+	d.diffPaths()
+	return d.report, nil
+}
+
+// differ holds the state for comparing two decoded OpenAPI documents.
+type differ struct {
+	oldDoc, newDoc map[string]any
+	report         *DiffReport
+	op             string
+
+	// seen tracks compared pairs of schema references to stop recursion.
+	seen map[string]bool
+}
+
+func (d *differ) add(breaking bool, location, format string, args ...any) {
+	d.report.Changes = append(d.report.Changes, Change{
+		Breaking:  breaking,
+		Operation: d.op,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// object returns the named field as a JSON object, or nil.
+func (d *differ) object(v map[string]any, name string) map[string]any {
+	if v == nil {
+		return nil
+	}
+	o, _ := v[name].(map[string]any)
+	return o
+}
+
+// sortedKeys returns the union of the keys of both objects, sorted.
+func (d *differ) sortedKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolve follows a local `$ref` within the document, returning the target
+// and the reference followed, if any.
+func (d *differ) resolve(doc, v map[string]any) (map[string]any, string) {
+	ref, _ := v["$ref"].(string)
+	if !strings.HasPrefix(ref, "#/") {
+		return v, ""
+	}
+	target := doc
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if target = d.object(target, part); target == nil {
+			return nil, ref
+		}
+	}
+	return target, ref
+}
+
+func (d *differ) diffPaths() {
+	oldPaths, newPaths := d.object(d.oldDoc, "paths"), d.object(d.newDoc, "paths")
+	for _, path := range d.sortedKeys(oldPaths, newPaths) {
+		oldItem, newItem := d.object(oldPaths, path), d.object(newPaths, path)
+		for _, method := range diffMethods {
+			oldOp, newOp := d.object(oldItem, method), d.object(newItem, method)
+			d.op = strings.ToUpper(method) + " " + path
+			switch {
+			case oldOp == nil && newOp == nil:
+				continue
+			case newOp == nil:
+				d.add(true, "", "operation removed")
+			case oldOp == nil:
+				d.add(false, "", "operation added")
+			default:
+				d.diffParams(oldItem, oldOp, newItem, newOp)
+				d.diffRequestBody(oldOp, newOp)
+				d.diffResponses(oldOp, newOp)
+			}
+		}
+	}
+}
+
+// params returns the parameters for an operation, including any inherited
+// from the path item, keyed by location & name.
+func (d *differ) params(doc, item, op map[string]any) map[string]any {
+	result := map[string]any{}
+	for _, source := range []map[string]any{item, op} {
+		list, _ := source["parameters"].([]any)
+		for _, p := range list {
+			if param, ok := p.(map[string]any); ok {
+				param, _ = d.resolve(doc, param)
+				if param != nil {
+					result[fmt.Sprintf("%v.%v", param["in"], param["name"])] = param
+				}
+			}
+		}
+	}
+	return result
+}
+
+func (d *differ) diffParams(oldItem, oldOp, newItem, newOp map[string]any) {
+	oldParams, newParams := d.params(d.oldDoc, oldItem, oldOp), d.params(d.newDoc, newItem, newOp)
+	for _, key := range d.sortedKeys(oldParams, newParams) {
+		oldParam, _ := oldParams[key].(map[string]any)
+		newParam, _ := newParams[key].(map[string]any)
+		oldRequired, _ := oldParam["required"].(bool)
+		newRequired, _ := newParam["required"].(bool)
+		switch {
+		case newParam == nil:
+			d.add(false, key, "parameter removed")
+		case oldParam == nil:
+			if newRequired {
+				d.add(true, key, "required parameter added")
+			} else {
+				d.add(false, key, "optional parameter added")
+			}
+		default:
+			if newRequired && !oldRequired {
+				d.add(true, key, "parameter is now required")
+			} else if oldRequired && !newRequired {
+				d.add(false, key, "parameter is now optional")
+			}
+			d.diffSchema(true, key, d.object(oldParam, "schema"), d.object(newParam, "schema"))
+		}
+	}
+}
+
+func (d *differ) diffRequestBody(oldOp, newOp map[string]any) {
+	oldBody, _ := d.resolve(d.oldDoc, d.object(oldOp, "requestBody"))
+	newBody, _ := d.resolve(d.newDoc, d.object(newOp, "requestBody"))
+	oldRequired, _ := oldBody["required"].(bool)
+	newRequired, _ := newBody["required"].(bool)
+
+	const loc = "request.body"
+	switch {
+	case oldBody == nil && newBody == nil:
+		return
+	case newBody == nil:
+		d.add(false, loc, "request body removed")
+		return
+	case oldBody == nil:
+		if newRequired {
+			d.add(true, loc, "required request body added")
+		} else {
+			d.add(false, loc, "optional request body added")
+		}
+		return
+	}
+
+	if newRequired && !oldRequired {
+		d.add(true, loc, "request body is now required")
+	}
+	d.diffContent(true, loc, d.object(oldBody, "content"), d.object(newBody, "content"))
+}
+
+func (d *differ) diffResponses(oldOp, newOp map[string]any) {
+	oldResponses, newResponses := d.object(oldOp, "responses"), d.object(newOp, "responses")
+	for _, status := range d.sortedKeys(oldResponses, newResponses) {
+		oldResp, _ := d.resolve(d.oldDoc, d.object(oldResponses, status))
+		newResp, _ := d.resolve(d.newDoc, d.object(newResponses, status))
+		loc := "response." + status
+		switch {
+		case newResp == nil:
+			// Clients rely on success responses, while errors are exceptional.
+			d.add(strings.HasPrefix(status, "2"), loc, "response removed")
+		case oldResp == nil:
+			d.add(false, loc, "response added")
+		default:
+			oldHeaders, newHeaders := d.object(oldResp, "headers"), d.object(newResp, "headers")
+			for _, name := range d.sortedKeys(oldHeaders, newHeaders) {
+				if newHeaders[name] == nil {
+					d.add(true, loc+".header."+name, "response header removed")
+				} else if oldHeaders[name] == nil {
+					d.add(false, loc+".header."+name, "response header added")
+				}
+			}
+			d.diffContent(false, loc+".body", d.object(oldResp, "content"), d.object(newResp, "content"))
+		}
+	}
+}
+
+// diffContent compares the media types of a request body or response.
+func (d *differ) diffContent(request bool, loc string, oldContent, newContent map[string]any) {
+	for _, ct := range d.sortedKeys(oldContent, newContent) {
+		oldMT, newMT := d.object(oldContent, ct), d.object(newContent, ct)
+		switch {
+		case newMT == nil:
+			// Clients sending or expecting this content type will break.
+			d.add(true, loc, "content type %s removed", ct)
+		case oldMT == nil:
+			d.add(false, loc, "content type %s added", ct)
+		default:
+			d.diffSchema(request, loc, d.object(oldMT, "schema"), d.object(newMT, "schema"))
+		}
+	}
+}
+
+// schemaTypes returns the set of types allowed by a schema, which may be a
+// single type or a list like `["string", "null"]`.
+func (d *differ) schemaTypes(s map[string]any) map[string]bool {
+	types := map[string]bool{}
+	switch t := s["type"].(type) {
+	case string:
+		types[t] = true
+	case []any:
+		for _, v := range t {
+			if str, ok := v.(string); ok {
+				types[str] = true
+			}
+		}
+	}
+	if nullable, _ := s["nullable"].(bool); nullable && len(types) > 0 {
+		// OpenAPI 3.0 style nullable.
+		types["null"] = true
+	}
+	if types["number"] {
+		// Integers are a subset of numbers.
+		types["integer"] = true
+	}
+	return types
+}
+
+// missing returns the sorted keys of `a` which are not in `b`.
+func (d *differ) missing(a, b map[string]bool) []string {
+	keys := []string{}
+	for k := range a {
+		if !b[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (d *differ) enumValues(s map[string]any) (map[string]bool, bool) {
+	list, ok := s["enum"].([]any)
+	if !ok {
+		return nil, false
+	}
+	values := map[string]bool{}
+	for _, v := range list {
+		b, _ := json.Marshal(v)
+		values[string(b)] = true
+	}
+	return values, true
+}
+
+func (d *differ) requiredSet(s map[string]any) map[string]bool {
+	required := map[string]bool{}
+	list, _ := s["required"].([]any)
+	for _, v := range list {
+		if name, ok := v.(string); ok {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+// diffSchema compares two schemas, where `request` is true for schemas sent
+// by the client and false for those received by the client.
+func (d *differ) diffSchema(request bool, loc string, oldSchema, newSchema map[string]any) {
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	oldSchema, oldRef := d.resolve(d.oldDoc, oldSchema)
+	newSchema, newRef := d.resolve(d.newDoc, newSchema)
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+	if oldRef != "" || newRef != "" {
+		key := fmt.Sprintf("%s|%s|%s|%v", d.op, oldRef, newRef, request)
+		if d.seen[key] {
+			return
+		}
+		d.seen[key] = true
+	}
+
+	// Clients send requests, so the server must accept every value it used to.
+	// Clients receive responses, so they must understand every new value.
+	oldTypes, newTypes := d.schemaTypes(oldSchema), d.schemaTypes(newSchema)
+	if len(oldTypes) > 0 && len(newTypes) > 0 {
+		if request {
+			if removed := d.missing(oldTypes, newTypes); len(removed) > 0 {
+				d.add(true, loc, "type %s no longer allowed", strings.Join(removed, ", "))
+			}
+		} else if added := d.missing(newTypes, oldTypes); len(added) > 0 {
+			d.add(true, loc, "type %s may now be returned", strings.Join(added, ", "))
+		}
+	}
+
+	oldEnum, oldHasEnum := d.enumValues(oldSchema)
+	newEnum, newHasEnum := d.enumValues(newSchema)
+	switch {
+	case oldHasEnum && newHasEnum:
+		for _, v := range d.missing(oldEnum, newEnum) {
+			d.add(request, loc, "enum value %s removed", v)
+		}
+		for _, v := range d.missing(newEnum, oldEnum) {
+			d.add(!request, loc, "enum value %s added", v)
+		}
+	case newHasEnum:
+		d.add(request, loc, "values restricted to an enum")
+	case oldHasEnum:
+		d.add(!request, loc, "enum restriction removed")
+	}
+
+	if request {
+		d.diffConstraints(loc, oldSchema, newSchema)
+	}
+
+	oldProps, newProps := d.object(oldSchema, "properties"), d.object(newSchema, "properties")
+	oldRequired, newRequired := d.requiredSet(oldSchema), d.requiredSet(newSchema)
+	for _, name := range d.sortedKeys(oldProps, newProps) {
+		propLoc := loc + "." + name
+		oldProp, newProp := d.object(oldProps, name), d.object(newProps, name)
+		switch {
+		case newProp == nil:
+			d.add(true, propLoc, "property removed")
+		case oldProp == nil:
+			if request && newRequired[name] {
+				d.add(true, propLoc, "required property added")
+			} else {
+				d.add(false, propLoc, "property added")
+			}
+		default:
+			if request && newRequired[name] && !oldRequired[name] {
+				d.add(true, propLoc, "property is now required")
+			} else if !request && oldRequired[name] && !newRequired[name] {
+				d.add(true, propLoc, "property is no longer required")
+			}
+			d.diffSchema(request, propLoc, oldProp, newProp)
+		}
+	}
+
+	d.diffSchema(request, loc+"[]", d.object(oldSchema, "items"), d.object(newSchema, "items"))
+}
+
+// diffConstraints compares the validation constraints of request schemas,
+// where tightening a constraint may reject previously valid requests.
+func (d *differ) diffConstraints(loc string, oldSchema, newSchema map[string]any) {
+	for _, c := range []struct {
+		name  string
+		lower bool
+	}{
+		{"minimum", true},
+		{"exclusiveMinimum", true},
+		{"minLength", true},
+		{"minItems", true},
+		{"minProperties", true},
+		{"maximum", false},
+		{"exclusiveMaximum", false},
+		{"maxLength", false},
+		{"maxItems", false},
+		{"maxProperties", false},
+	} {
+		oldValue, oldOK := oldSchema[c.name].(float64)
+		newValue, newOK := newSchema[c.name].(float64)
+		switch {
+		case !oldOK && newOK:
+			d.add(true, loc, "%s %v added", c.name, newValue)
+		case oldOK && !newOK:
+			d.add(false, loc, "%s %v removed", c.name, oldValue)
+		case oldOK && newOK && oldValue != newValue:
+			tightened := newValue > oldValue
+			if !c.lower {
+				tightened = newValue < oldValue
+			}
+			d.add(tightened, loc, "%s changed from %v to %v", c.name, oldValue, newValue)
+		}
+	}
+
+	oldPattern, _ := oldSchema["pattern"].(string)
+	newPattern, _ := newSchema["pattern"].(string)
+	switch {
+	case newPattern != "" && oldPattern != newPattern:
+		d.add(true, loc, "pattern changed to %s", newPattern)
+	case oldPattern != "" && newPattern == "":
+		d.add(false, loc, "pattern removed")
+	}
+}
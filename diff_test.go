@@ -0,0 +1,185 @@
+package huma_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DiffThingV1 struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Color  string   `json:"color" enum:"red,green,blue"`
+	Legacy string   `json:"legacy,omitempty"`
+	Tags   []string `json:"tags" maxItems:"10"`
+}
+
+type DiffThingV2 struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name,omitempty" maxLength:"50"`
+	Color string   `json:"color" enum:"red,green,blue,pink"`
+	Owner string   `json:"owner"`
+	Tags  []string `json:"tags" maxItems:"20"`
+	Extra int      `json:"extra,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	noop := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+
+	_, oldAPI := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Put(oldAPI, "/things/{id}", func(ctx context.Context, input *struct {
+		ID    string `path:"id"`
+		Limit int    `query:"limit"`
+		Body  DiffThingV1
+	}) (*struct{ Body DiffThingV1 }, error) {
+		return nil, nil
+	})
+	huma.Delete(oldAPI, "/things/{id}", func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	_, newAPI := humatest.New(t, huma.DefaultConfig("Test API", "2.0.0"))
+	huma.Put(newAPI, "/things/{id}", func(ctx context.Context, input *struct {
+		ID     string `path:"id"`
+		Limit  int    `query:"limit"`
+		Format string `header:"X-Format" required:"true"`
+		Body   DiffThingV2
+	}) (*struct{ Body DiffThingV2 }, error) {
+		return nil, nil
+	})
+	huma.Get(newAPI, "/health", noop)
+
+	report, err := huma.Diff(oldAPI.OpenAPI(), newAPI.OpenAPI())
+	require.NoError(t, err)
+
+	changes := map[string]bool{}
+	for _, c := range report.Changes {
+		changes[c.String()] = true
+	}
+
+	for _, expected := range []string{
+		"breaking: DELETE /things/{id}: operation removed",
+		"non-breaking: GET /health: operation added",
+		"breaking: PUT /things/{id} header.X-Format: required parameter added",
+
+		// Request schemas.
+		"breaking: PUT /things/{id} request.body.legacy: property removed",
+		"breaking: PUT /things/{id} request.body.owner: required property added",
+		"non-breaking: PUT /things/{id} request.body.extra: property added",
+		"non-breaking: PUT /things/{id} request.body.color: enum value \"pink\" added",
+		"breaking: PUT /things/{id} request.body.name: maxLength 50 added",
+		"non-breaking: PUT /things/{id} request.body.tags: maxItems changed from 10 to 20",
+
+		// Response schemas.
+		"breaking: PUT /things/{id} response.200.body.legacy: property removed",
+		"breaking: PUT /things/{id} response.200.body.name: property is no longer required",
+		"breaking: PUT /things/{id} response.200.body.color: enum value \"pink\" added",
+		"non-breaking: PUT /things/{id} response.200.body.owner: property added",
+	} {
+		assert.True(t, changes[expected], expected)
+	}
+
+	// Constraints only matter for requests.
+	assert.False(t, changes["breaking: PUT /things/{id} response.200.body.name: maxLength 50 added"])
+	assert.True(t, report.HasBreaking())
+	assert.Len(t, report.Breaking(), 8)
+}
+
+func TestDiffJSON(t *testing.T) {
+	oldSpec := []byte(`{
+		"openapi": "3.1.0",
+		"paths": {
+			"/things": {
+				"post": {
+					"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}},
+					"responses": {"201": {"description": "Created"}, "400": {"description": "Bad request"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Thing": {
+					"type": "object",
+					"properties": {
+						"size": {"type": "number", "minimum": 0},
+						"kind": {"type": "string"},
+						"child": {"$ref": "#/components/schemas/Thing"}
+					}
+				}
+			}
+		}
+	}`)
+
+	newSpec := []byte(`{
+		"openapi": "3.1.0",
+		"paths": {
+			"/things": {
+				"post": {
+					"requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}},
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Thing": {
+					"type": "object",
+					"properties": {
+						"size": {"type": "integer", "minimum": 1},
+						"kind": {"type": "string", "enum": ["a", "b"], "pattern": "^[a-z]$"},
+						"child": {"$ref": "#/components/schemas/Thing"}
+					}
+				}
+			}
+		}
+	}`)
+
+	report, err := huma.DiffJSON(oldSpec, newSpec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []huma.Change{
+		{Breaking: true, Operation: "POST /things", Location: "request.body", Message: "request body is now required"},
+		{Breaking: true, Operation: "POST /things", Location: "request.body.kind", Message: "values restricted to an enum"},
+		{Breaking: true, Operation: "POST /things", Location: "request.body.kind", Message: "pattern changed to ^[a-z]$"},
+		{Breaking: true, Operation: "POST /things", Location: "request.body.size", Message: "type number no longer allowed"},
+		{Breaking: true, Operation: "POST /things", Location: "request.body.size", Message: "minimum changed from 0 to 1"},
+		{Breaking: false, Operation: "POST /things", Location: "response.400", Message: "response removed"},
+	}, report.Changes)
+
+	b, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"breaking":true`)
+
+	_, err = huma.DiffJSON([]byte("bad"), newSpec)
+	assert.Error(t, err)
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Get(api, "/things/{id}", func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body DiffThingV1 }, error) {
+		return nil, nil
+	})
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPost,
+		Path:   "/things",
+	}, func(ctx context.Context, input *struct{ Body DiffThingV1 }) (*struct{}, error) {
+		return nil, nil
+	})
+
+	report, err := huma.Diff(api.OpenAPI(), api.OpenAPI())
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.False(t, report.HasBreaking())
+}
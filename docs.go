@@ -0,0 +1,114 @@
+package huma
+
+import "html"
+
+// Supported renderers for the built-in API documentation, selectable via
+// `config.DocsRenderer`.
+const (
+	DocsRendererStoplightElements = "stoplight-elements"
+	DocsRendererScalar            = "scalar"
+	DocsRendererSwaggerUI         = "swagger-ui"
+	DocsRendererRapiDoc           = "rapidoc"
+)
+
+// docsTemplates maps a docs renderer to a function that generates its HTML
+// given the page title and the path to the OpenAPI spec without extension.
+var docsTemplates = map[string]func(title, openAPIPath string) string{
+	DocsRendererStoplightElements: func(title, openAPIPath string) string {
+		return `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="referrer" content="same-origin" />
+    <meta name="viewport" content="width=device-width, initial-scale=1, shrink-to-fit=no" />
+    <title>` + title + `</title>
+    <!-- Embed elements Elements via Web Component -->
+    <link href="https://unpkg.com/@stoplight/elements@8.1.0/styles.min.css" rel="stylesheet"
+          crossorigin="anonymous" />
+    <script src="https://unpkg.com/@stoplight/elements@8.1.0/web-components.min.js"
+            integrity="sha256-985sDMZYbGa0LDS8jYmC4VbkVlh7DZ0TWejFv+raZII="
+            crossorigin="anonymous"></script>
+  </head>
+  <body style="height: 100vh;">
+
+    <elements-api
+      apiDescriptionUrl="` + openAPIPath + `.yaml"
+      router="hash"
+      layout="sidebar"
+      tryItCredentialsPolicy="same-origin"
+    />
+
+  </body>
+</html>`
+	},
+	DocsRendererScalar: func(title, openAPIPath string) string {
+		return `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="referrer" content="same-origin" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + title + `</title>
+  </head>
+  <body>
+    <script id="api-reference" data-url="` + openAPIPath + `.json"></script>
+    <script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.0"
+            crossorigin="anonymous"></script>
+  </body>
+</html>`
+	},
+	DocsRendererSwaggerUI: func(title, openAPIPath string) string {
+		return `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + title + `</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui.css"
+          crossorigin="anonymous" />
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-bundle.js"
+            crossorigin="anonymous"></script>
+    <script>
+      window.onload = () => {
+        window.ui = SwaggerUIBundle({
+          url: '` + openAPIPath + `.json',
+          dom_id: '#swagger-ui',
+        });
+      };
+    </script>
+  </body>
+</html>`
+	},
+	DocsRendererRapiDoc: func(title, openAPIPath string) string {
+		return `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1" />
+    <title>` + title + `</title>
+    <script type="module" src="https://unpkg.com/rapidoc@9.3.4/dist/rapidoc-min.js"
+            crossorigin="anonymous"></script>
+  </head>
+  <body>
+    <rapi-doc spec-url="` + openAPIPath + `.json" render-style="read"></rapi-doc>
+  </body>
+</html>`
+	},
+}
+
+// docsHTML renders the documentation page using the config's template or
+// renderer, falling back to Stoplight Elements when neither is set.
+func docsHTML(config Config, title, openAPIPath string) []byte {
+	title = html.EscapeString(title)
+	if config.DocsTemplate != nil {
+		return []byte(config.DocsTemplate(title, openAPIPath))
+	}
+	renderer := config.DocsRenderer
+	if renderer == "" {
+		renderer = DocsRendererStoplightElements
+	}
+	return []byte(docsTemplates[renderer](title, openAPIPath))
+}
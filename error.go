@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // ErrorDetailer returns error details for responses & debugging. This enables
@@ -15,18 +16,23 @@ type ErrorDetailer interface {
 // ErrorDetail provides details about a specific error.
 type ErrorDetail struct {
 	// Message is a human-readable explanation of the error.
-	Message string `json:"message,omitempty" doc:"Error message text"`
+	Message string `json:"message,omitempty" xml:"message,omitempty" doc:"Error message text"`
 
 	// Location is a path-like string indicating where the error occurred.
 	// It typically begins with `path`, `query`, `header`, or `body`. Example:
 	// `body.items[3].tags` or `path.thing-id`.
-	Location string `json:"location,omitempty" doc:"Where the error occurred, e.g. 'body.items[3].tags' or 'path.thing-id'"`
+	Location string `json:"location,omitempty" xml:"location,omitempty" doc:"Where the error occurred, e.g. 'body.items[3].tags' or 'path.thing-id'"`
 
 	// Value is the value at the given location, echoed back to the client
 	// to help with debugging. This can be useful for e.g. validating that
 	// the client didn't send extra whitespace or help when the client
 	// did not log an outgoing request.
-	Value any `json:"value,omitempty" doc:"The value at the given location"`
+	Value any `json:"value,omitempty" xml:"-" doc:"The value at the given location"`
+
+	// key and args describe validation messages which can be translated, see
+	// `RegisterMessages`.
+	key  string
+	args []any
 }
 
 // Error returns the error message / satisfies the `error` interface. If a
@@ -44,6 +50,37 @@ func (e *ErrorDetail) ErrorDetail() *ErrorDetail {
 	return e
 }
 
+// JSONPointer returns the location as an RFC 6901 JSON Pointer, which some
+// clients and tools prefer for locating the error in the request. Since the
+// location uses `.` as a separator, property names containing a `.` cannot
+// be distinguished from nested properties.
+//
+//	detail := &huma.ErrorDetail{Location: "body.items[3].price"}
+//	detail.JSONPointer() // "/body/items/3/price"
+func (e *ErrorDetail) JSONPointer() string {
+	if e.Location == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(len(e.Location) + 1)
+	b.WriteByte('/')
+	for i := 0; i < len(e.Location); i++ {
+		switch c := e.Location[i]; c {
+		case '.', '[':
+			b.WriteByte('/')
+		case ']':
+			// Closing brackets are implied by the next separator.
+		case '~':
+			b.WriteString("~0")
+		case '/':
+			b.WriteString("~1")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
 // ErrorModel defines a basic error message model based on RFC 9457 Problem
 // Details for HTTP APIs (https://datatracker.ietf.org/doc/html/rfc9457). It
 // is augmented with an `errors` field of `huma.ErrorDetail` objects that
@@ -68,26 +105,26 @@ func (e *ErrorDetail) ErrorDetail() *ErrorDetail {
 //	}
 type ErrorModel struct {
 	// Type is a URI to get more information about the error type.
-	Type string `json:"type,omitempty" format:"uri" default:"about:blank" example:"https://example.com/errors/example" doc:"A URI reference to human-readable documentation for the error."`
+	Type string `json:"type,omitempty" xml:"type,omitempty" format:"uri" default:"about:blank" example:"https://example.com/errors/example" doc:"A URI reference to human-readable documentation for the error."`
 
 	// Title provides a short static summary of the problem. Huma will default this
 	// to the HTTP response status code text if not present.
-	Title string `json:"title,omitempty" example:"Bad Request" doc:"A short, human-readable summary of the problem type. This value should not change between occurrences of the error."`
+	Title string `json:"title,omitempty" xml:"title,omitempty" example:"Bad Request" doc:"A short, human-readable summary of the problem type. This value should not change between occurrences of the error."`
 
 	// Status provides the HTTP status code for client convenience. Huma will
 	// default this to the response status code if unset. This SHOULD match the
 	// response status code (though proxies may modify the actual status code).
-	Status int `json:"status,omitempty" example:"400" doc:"HTTP status code"`
+	Status int `json:"status,omitempty" xml:"status,omitempty" example:"400" doc:"HTTP status code"`
 
 	// Detail is an explanation specific to this error occurrence.
-	Detail string `json:"detail,omitempty" example:"Property foo is required but is missing." doc:"A human-readable explanation specific to this occurrence of the problem."`
+	Detail string `json:"detail,omitempty" xml:"detail,omitempty" example:"Property foo is required but is missing." doc:"A human-readable explanation specific to this occurrence of the problem."`
 
 	// Instance is a URI to get more info about this error occurrence.
-	Instance string `json:"instance,omitempty" format:"uri" example:"https://example.com/error-log/abc123" doc:"A URI reference that identifies the specific occurrence of the problem."`
+	Instance string `json:"instance,omitempty" xml:"instance,omitempty" format:"uri" example:"https://example.com/error-log/abc123" doc:"A URI reference that identifies the specific occurrence of the problem."`
 
 	// Errors provides an optional mechanism of passing additional error details
 	// as a list.
-	Errors []*ErrorDetail `json:"errors,omitempty" doc:"Optional list of individual error details"`
+	Errors []*ErrorDetail `json:"errors,omitempty" xml:"errors>error,omitempty" doc:"Optional list of individual error details"`
 }
 
 // Error satisfies the `error` interface. It returns the error's detail field.
@@ -218,12 +255,16 @@ func WriteErr(api API, ctx Context, status int, msg string, errs ...error) error
 		ct = ctf.ContentType(ct)
 	}
 
-	ctx.SetHeader("Content-Type", ct)
-	ctx.SetStatus(status)
+	// Transform before writing the status so transformers can set headers.
 	tval, terr := api.Transform(ctx, strconv.Itoa(status), err)
 	if terr != nil {
+		// Callers generally ignore the error, so make sure the client still
+		// gets the error status rather than an empty success response.
+		ctx.SetStatus(status)
 		return terr
 	}
+	ctx.SetHeader("Content-Type", ct)
+	ctx.SetStatus(status)
 	return api.Marshal(ctx.BodyWriter(), ct, tval)
 }
 
@@ -1,6 +1,7 @@
 package huma_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -45,6 +46,21 @@ func TestError(t *testing.T) {
 	assert.Equal(t, "other", err.ContentType("other"))
 }
 
+func TestErrorDetailJSONPointer(t *testing.T) {
+	for location, pointer := range map[string]string{
+		"":                     "",
+		"body":                 "/body",
+		"body.items[3].price":  "/body/items/3/price",
+		"body[0][1]":           "/body/0/1",
+		"path.thing-id":        "/path/thing-id",
+		"body.a/b.c~d":         "/body/a~1b/c~0d",
+		"body.tags[2]":         "/body/tags/2",
+		"query.filter[0].name": "/query/filter/0/name",
+	} {
+		assert.Equal(t, pointer, (&huma.ErrorDetail{Location: location}).JSONPointer(), location)
+	}
+}
+
 func TestErrorResponses(t *testing.T) {
 	// NotModified has a slightly different signature.
 	assert.Equal(t, 304, huma.Status304NotModified().GetStatus())
@@ -100,4 +116,26 @@ func TestTransformError(t *testing.T) {
 	ctx := humatest.NewContext(nil, req, resp)
 
 	require.Error(t, huma.WriteErr(api, ctx, 400, "bad request"))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestTransformErrorKeepsStatus(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Transformers = []huma.Transformer{
+		func(ctx huma.Context, status string, v any) (any, error) {
+			if status == "422" {
+				return nil, errors.New("whoops")
+			}
+			return v, nil
+		},
+	}
+	_, api := humatest.New(t, config)
+	huma.Get(api, "/x", func(ctx context.Context, input *struct {
+		N int `query:"n"`
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	resp := api.Get("/x?n=abc")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
 }
@@ -0,0 +1,181 @@
+package huma
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// formatExamples contains example values for well-known string formats.
+var formatExamples = map[string]string{
+	"date-time":             "2024-01-01T12:00:00Z",
+	"date-time-http":        "Mon, 01 Jan 2024 12:00:00 GMT",
+	"date":                  "2024-01-01",
+	"time":                  "12:00:00",
+	"duration":              "P1D",
+	"email":                 "user@example.com",
+	"idn-email":             "user@example.com",
+	"hostname":              "example.com",
+	"idn-hostname":          "example.com",
+	"ipv4":                  "192.0.2.1",
+	"ipv6":                  "2001:db8::1",
+	"uri":                   "https://example.com",
+	"iri":                   "https://example.com",
+	"uri-reference":         "/example",
+	"iri-reference":         "/example",
+	"uri-template":          "https://example.com/{id}",
+	"json-pointer":          "/example",
+	"relative-json-pointer": "0/1",
+	"uuid":                  "123e4567-e89b-12d3-a456-426614174000",
+	"regex":                 "^[a-z]+$",
+}
+
+// SynthesizeExample generates a plausible example value for a schema which
+// satisfies its `enum`, `format`, length, range, and `pattern` constraints
+// where feasible. A schema's default value is used if present. Returns `nil`
+// for objects, references, or when no valid value could be found.
+func SynthesizeExample(s *Schema) any {
+	if s == nil || s.Ref != "" {
+		return nil
+	}
+
+	if s.Default != nil {
+		return s.Default
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case TypeBoolean:
+		return true
+	case TypeInteger:
+		if v, ok := synthesizeNumber(s, true); ok {
+			return int64(v)
+		}
+	case TypeNumber:
+		if v, ok := synthesizeNumber(s, false); ok {
+			return v
+		}
+	case TypeString:
+		if v, ok := synthesizeString(s); ok {
+			return v
+		}
+	case TypeArray:
+		return synthesizeArray(s)
+	}
+
+	return nil
+}
+
+// synthesizeNumber returns a number within the schema's bounds, preferring
+// `1` when it is allowed.
+func synthesizeNumber(s *Schema, integer bool) (float64, bool) {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	loExclusive, hiExclusive := false, false
+	if s.Minimum != nil {
+		lo = *s.Minimum
+	}
+	if s.ExclusiveMinimum != nil && *s.ExclusiveMinimum >= lo {
+		lo, loExclusive = *s.ExclusiveMinimum, true
+	}
+	if s.Maximum != nil {
+		hi = *s.Maximum
+	}
+	if s.ExclusiveMaximum != nil && *s.ExclusiveMaximum <= hi {
+		hi, hiExclusive = *s.ExclusiveMaximum, true
+	}
+
+	inRange := func(v float64) bool {
+		return (v > lo || (!loExclusive && v == lo)) && (v < hi || (!hiExclusive && v == hi))
+	}
+
+	v := 1.0
+	if !inRange(v) {
+		switch {
+		case !math.IsInf(lo, -1) && !math.IsInf(hi, 1):
+			v = lo + (hi-lo)/2
+		case !math.IsInf(lo, -1):
+			v = lo + 1
+		default:
+			v = hi - 1
+		}
+		if integer {
+			v = math.Ceil(v)
+		}
+	}
+
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		v = math.Ceil(v / *s.MultipleOf) * *s.MultipleOf
+	}
+
+	if integer && v != math.Trunc(v) {
+		return 0, false
+	}
+
+	return v, inRange(v)
+}
+
+// synthesizeString returns a string matching the schema's format, length,
+// and pattern constraints.
+func synthesizeString(s *Schema) (string, bool) {
+	v, formatted := formatExamples[s.Format]
+	if !formatted {
+		v = "string"
+		if s.ContentEncoding == "base64" {
+			v = "c3RyaW5n"
+			formatted = true
+		}
+	}
+
+	if s.MinLength != nil && len(v) < *s.MinLength {
+		if formatted {
+			return "", false
+		}
+		v += strings.Repeat("s", *s.MinLength-len(v))
+	}
+
+	if s.MaxLength != nil && len(v) > *s.MaxLength {
+		if formatted {
+			return "", false
+		}
+		v = v[:*s.MaxLength]
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil || !re.MatchString(v) {
+			// Generating values from arbitrary patterns isn't feasible.
+			return "", false
+		}
+	}
+
+	return v, true
+}
+
+// synthesizeArray returns an array of example items satisfying the schema's
+// item count constraints.
+func synthesizeArray(s *Schema) any {
+	item := SynthesizeExample(s.Items)
+	if item == nil {
+		return nil
+	}
+
+	n := 1
+	if s.MinItems != nil && *s.MinItems > n {
+		n = *s.MinItems
+	}
+	if s.MaxItems != nil && *s.MaxItems < n {
+		n = *s.MaxItems
+	}
+	if s.UniqueItems && n > 1 {
+		return nil
+	}
+
+	items := make([]any, n)
+	for i := range items {
+		items[i] = item
+	}
+	return items
+}
@@ -55,12 +55,10 @@ func main() {
 
 		// Create a schema for the output body.
 		registry := api.OpenAPI().Components.Schemas
-		schema := &huma.Schema{
-			OneOf: []*huma.Schema{
-				registry.Schema(reflect.TypeOf(GreetingBody{}), true, ""),
-				registry.Schema(reflect.TypeOf(GreetingBodyOld{}), true, ""),
-			},
-		}
+		schema := huma.OneOf(registry,
+			reflect.TypeOf(GreetingBody{}),
+			reflect.TypeOf(GreetingBodyOld{}),
+		)
 
 		// Register GET /greeting/{name}
 		huma.Register(api, huma.Operation{
@@ -0,0 +1,101 @@
+// This example shows how to register an additional serialization format, in
+// this case YAML, which clients can select via content negotiation. Try the
+// following requests:
+//
+//	# Get a YAML response
+//	restish :8888/greeting/world -H Accept:application/yaml
+//
+//	# Send a YAML request body
+//	echo 'name: world' | restish put :8888/greeting -H Content-Type:application/yaml
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/danielgtaylor/huma/v2/humacli"
+	hyaml "github.com/danielgtaylor/huma/v2/yaml"
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Options for the CLI.
+type Options struct {
+	Port int `help:"Port to listen on" short:"p" default:"8888"`
+}
+
+// YAMLFormat marshals & unmarshals YAML. It goes through JSON so that the
+// same `json` struct tags are used for every format.
+var YAMLFormat = huma.Format{
+	Marshal: func(w io.Writer, v any) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return hyaml.Convert(w, bytes.NewReader(b))
+	},
+	Unmarshal: func(data []byte, v any) error {
+		var tmp any
+		if err := yaml.Unmarshal(data, &tmp); err != nil {
+			return err
+		}
+		b, err := json.Marshal(tmp)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, v)
+	},
+}
+
+// GreetingOutput represents the greeting operation response.
+type GreetingOutput struct {
+	Body struct {
+		Message string `json:"message" example:"Hello, world!" doc:"Greeting message"`
+	}
+}
+
+func main() {
+	// Create a CLI app which takes a port option.
+	cli := humacli.New(func(hooks humacli.Hooks, options *Options) {
+		// Register YAML alongside the default JSON & CBOR formats.
+		config := huma.DefaultConfig("My API", "1.0.0")
+		config.Formats["application/yaml"] = YAMLFormat
+		config.Formats["yaml"] = YAMLFormat
+
+		// Create a new router & API
+		router := chi.NewMux()
+		api := humachi.New(router, config)
+
+		huma.Get(api, "/greeting/{name}", func(ctx context.Context, input *struct {
+			Name string `path:"name" maxLength:"30" example:"world" doc:"Name to greet"`
+		}) (*GreetingOutput, error) {
+			resp := &GreetingOutput{}
+			resp.Body.Message = fmt.Sprintf("Hello, %s!", input.Name)
+			return resp, nil
+		})
+
+		huma.Put(api, "/greeting", func(ctx context.Context, input *struct {
+			Body struct {
+				Name string `json:"name" maxLength:"30" example:"world" doc:"Name to greet"`
+			}
+		}) (*GreetingOutput, error) {
+			resp := &GreetingOutput{}
+			resp.Body.Message = fmt.Sprintf("Hello, %s!", input.Body.Name)
+			return resp, nil
+		})
+
+		// Tell the CLI how to start your router.
+		hooks.OnStart(func() {
+			http.ListenAndServe(fmt.Sprintf(":%d", options.Port), router)
+		})
+	})
+
+	// Run the CLI. When passed no commands, it starts the server.
+	cli.Run()
+}
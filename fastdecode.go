@@ -0,0 +1,861 @@
+package huma
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// fastMaxDepth is the maximum nesting depth of fast decoded values, matching
+// `encoding/json`.
+const fastMaxDepth = 10000
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type fastKind uint8
+
+const (
+	fastBool fastKind = iota
+	fastInt
+	fastUint
+	fastFloat
+	fastString
+	fastSlice
+	fastStruct
+	fastPtr
+)
+
+// fastNode is a step in a plan for decoding JSON directly into a Go type
+// while validating it against the type's schema. Plans are built once at
+// registration time so decoding needs no reflection-based lookups.
+type fastNode struct {
+	kind   fastKind
+	typ    reflect.Type
+	bits   int
+	schema *Schema
+
+	// elem is the slice item or pointer target.
+	elem *fastNode
+
+	// Struct fields and whether unknown properties are skipped.
+	fields []fastField
+	index  map[string]int
+	addl   bool
+}
+
+type fastField struct {
+	name     string
+	index    int
+	readOnly bool
+	node     *fastNode
+}
+
+type fastKey struct {
+	typ    reflect.Type
+	schema *Schema
+}
+
+// newFastPlan creates a fast decoding plan for the type and its schema, or
+// returns an error describing why the type can't be fast decoded.
+func newFastPlan(r Registry, s *Schema, t reflect.Type) (*fastNode, error) {
+	return fastPlan(r, s, t, "body", map[fastKey]*fastNode{})
+}
+
+func fastPlan(r Registry, s *Schema, t reflect.Type, path string, structs map[fastKey]*fastNode) (*fastNode, error) {
+	for s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+
+	if t.Kind() == reflect.Ptr {
+		elem, err := fastPlan(r, s, t.Elem(), path, structs)
+		if err != nil {
+			return nil, err
+		}
+		return &fastNode{kind: fastPtr, typ: t.Elem(), schema: s, elem: elem}, nil
+	}
+
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return nil, fmt.Errorf("%s: type %s has a custom unmarshaler", path, t)
+	}
+	if s.Discriminator != nil || s.OneOf != nil || s.AnyOf != nil || s.AllOf != nil || s.Not != nil || s.If != nil {
+		return nil, fmt.Errorf("%s: composed or conditional schemas are not supported", path)
+	}
+	if len(s.DependentRequired) > 0 || s.UniqueItems {
+		return nil, fmt.Errorf("%s: dependentRequired and uniqueItems are not supported", path)
+	}
+
+	n := &fastNode{typ: t, schema: s}
+	expected := ""
+	switch t.Kind() {
+	case reflect.Bool:
+		n.kind, expected = fastBool, TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.kind, n.bits, expected = fastInt, t.Bits(), TypeInteger
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n.kind, n.bits, expected = fastUint, t.Bits(), TypeInteger
+	case reflect.Float32, reflect.Float64:
+		n.kind, n.bits, expected = fastFloat, t.Bits(), TypeNumber
+	case reflect.String:
+		n.kind, expected = fastString, TypeString
+	case reflect.Slice:
+		n.kind, expected = fastSlice, TypeArray
+	case reflect.Struct:
+		n.kind, expected = fastStruct, TypeObject
+	default:
+		return nil, fmt.Errorf("%s: type %s is not supported", path, t)
+	}
+	if s.Type != expected {
+		return nil, fmt.Errorf("%s: schema type %q for type %s is not supported", path, s.Type, t)
+	}
+
+	switch n.kind {
+	case fastSlice:
+		if s.Items == nil {
+			return nil, fmt.Errorf("%s: array schema without items is not supported", path)
+		}
+		elem, err := fastPlan(r, s.Items, t.Elem(), path+"[]", structs)
+		if err != nil {
+			return nil, err
+		}
+		n.elem = elem
+	case fastStruct:
+		key := fastKey{t, s}
+		if existing := structs[key]; existing != nil {
+			// Recursive types reuse the plan being built.
+			return existing, nil
+		}
+		structs[key] = n
+		if err := fastPlanStruct(r, n, path, structs); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func fastPlanStruct(r Registry, n *fastNode, path string, structs map[fastKey]*fastNode) error {
+	s, t := n.schema, n.typ
+	switch addl := s.AdditionalProperties.(type) {
+	case nil:
+		n.addl = true
+	case bool:
+		n.addl = addl
+	default:
+		return fmt.Errorf("%s: additionalProperties schemas are not supported", path)
+	}
+
+	n.index = map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous {
+			return fmt.Errorf("%s: embedded field %s is not supported", path, f.Name)
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := f.Name
+		if parts[0] != "" {
+			name = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			if opt == "string" {
+				return fmt.Errorf("%s.%s: the json string option is not supported", path, name)
+			}
+		}
+		if _, ok := n.index[name]; ok {
+			return fmt.Errorf("%s.%s: duplicate field name", path, name)
+		}
+
+		prop := s.Properties[name]
+		if prop == nil {
+			return fmt.Errorf("%s.%s: field is hidden or not in the schema", path, name)
+		}
+		node, err := fastPlan(r, prop, f.Type, path+"."+name, structs)
+		if err != nil {
+			return err
+		}
+		n.index[name] = len(n.fields)
+		n.fields = append(n.fields, fastField{name: name, index: i, readOnly: prop.ReadOnly, node: node})
+	}
+
+	// Properties without a field, like the `$schema` link, are validated but
+	// not decoded.
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		if _, ok := n.index[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := s.Properties[name]
+		n.index[name] = len(n.fields)
+		n.fields = append(n.fields, fastField{name: name, index: -1, readOnly: prop.ReadOnly, node: fastPlanValue(r, prop)})
+	}
+	return nil
+}
+
+// fastPlanValue creates a plan to validate a scalar value which is not
+// decoded. It returns nil if the value needs the regular decoder.
+func fastPlanValue(r Registry, s *Schema) *fastNode {
+	for s.Ref != "" {
+		s = r.SchemaFromRef(s.Ref)
+	}
+	if s.Discriminator != nil || s.OneOf != nil || s.AnyOf != nil || s.AllOf != nil || s.Not != nil || s.If != nil {
+		return nil
+	}
+	switch s.Type {
+	case TypeBoolean:
+		return &fastNode{kind: fastBool, schema: s}
+	case TypeInteger, TypeNumber:
+		return &fastNode{kind: fastFloat, bits: 64, schema: s}
+	case TypeString:
+		return &fastNode{kind: fastString, schema: s}
+	}
+	return nil
+}
+
+// isFastJSON returns whether the content type uses the JSON format, using
+// the same rules as `api.Unmarshal`.
+func isFastJSON(contentType string) bool {
+	start := strings.IndexRune(contentType, '+') + 1
+	end := strings.IndexRune(contentType, ';')
+	if end == -1 {
+		end = len(contentType)
+	}
+	if start > end {
+		return false
+	}
+	ct := contentType[start:end]
+	return ct == "" || ct == "application/json" || ct == "json"
+}
+
+// fastDecoder holds the state for decoding a single value. Decoders are
+// pooled and reuse their scratch space across requests.
+type fastDecoder struct {
+	data    []byte
+	pos     int
+	depth   int
+	scratch []byte
+	seen    []bool
+}
+
+var fastDecoderPool = sync.Pool{
+	New: func() any {
+		return &fastDecoder{}
+	},
+}
+
+// fastDecode decodes the JSON data into `v`, validating it against the plan's
+// schemas as it goes. It returns false if the data is invalid or needs the
+// regular decoder, in which case `v` has been reset to its zero value so the
+// regular decoder can be used to generate the errors.
+func fastDecode(n *fastNode, data []byte, v reflect.Value) bool {
+	d := fastDecoderPool.Get().(*fastDecoder)
+	d.data, d.pos, d.depth = data, 0, 0
+	ok := d.value(n, v)
+	if ok {
+		d.skipSpace()
+		ok = d.pos == len(d.data)
+	}
+	d.data = nil
+	d.seen = d.seen[:0]
+	fastDecoderPool.Put(d)
+
+	if !ok {
+		v.Set(reflect.Zero(v.Type()))
+	}
+	return ok
+}
+
+func (d *fastDecoder) skipSpace() {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+// next skips whitespace and returns whether there is any data left.
+func (d *fastDecoder) next() bool {
+	d.skipSpace()
+	return d.pos < len(d.data)
+}
+
+// value decodes a value into `v`. An invalid `v` only validates the value.
+func (d *fastDecoder) value(n *fastNode, v reflect.Value) bool {
+	if !d.next() {
+		return false
+	}
+
+	s := n.schema
+	switch n.kind {
+	case fastPtr:
+		if d.data[d.pos] == 'n' {
+			// Let the regular decoder decide what `null` means.
+			return false
+		}
+		p := reflect.New(n.typ)
+		if !d.value(n.elem, p.Elem()) {
+			return false
+		}
+		v.Set(p)
+	case fastBool:
+		var b bool
+		if d.literal("true") {
+			b = true
+		} else if !d.literal("false") {
+			return false
+		}
+		if len(s.Enum) > 0 && !fastEnumBool(s, b) {
+			return false
+		}
+		if v.IsValid() {
+			v.SetBool(b)
+		}
+	case fastInt, fastUint, fastFloat:
+		lit, ok := d.number()
+		if !ok {
+			return false
+		}
+		str := *(*string)(unsafe.Pointer(&lit))
+		num, err := strconv.ParseFloat(str, 64)
+		if err != nil || !fastValidNumber(s, num) {
+			return false
+		}
+		if !v.IsValid() {
+			break
+		}
+		switch n.kind {
+		case fastInt:
+			i, err := strconv.ParseInt(str, 10, n.bits)
+			if err != nil {
+				return false
+			}
+			v.SetInt(i)
+		case fastUint:
+			u, err := strconv.ParseUint(str, 10, n.bits)
+			if err != nil {
+				return false
+			}
+			v.SetUint(u)
+		default:
+			f, err := strconv.ParseFloat(str, n.bits)
+			if err != nil {
+				return false
+			}
+			v.SetFloat(f)
+		}
+	case fastString:
+		b, ok := d.string()
+		if !ok {
+			return false
+		}
+		if !v.IsValid() {
+			return fastValidString(s, *(*string)(unsafe.Pointer(&b)))
+		}
+		str := string(b)
+		if !fastValidString(s, str) {
+			return false
+		}
+		v.SetString(str)
+	case fastSlice:
+		return d.array(n, v)
+	case fastStruct:
+		return d.object(n, v)
+	}
+	return true
+}
+
+func (d *fastDecoder) array(n *fastNode, v reflect.Value) bool {
+	if d.data[d.pos] != '[' {
+		return false
+	}
+	d.pos++
+	if d.depth++; d.depth > fastMaxDepth {
+		return false
+	}
+
+	length := 0
+	if !d.next() {
+		return false
+	}
+	if d.data[d.pos] == ']' {
+		d.pos++
+		v.Set(reflect.MakeSlice(n.typ, 0, 0))
+	} else {
+		for {
+			if length == v.Cap() {
+				grown := reflect.MakeSlice(n.typ, length, 2*length+4)
+				reflect.Copy(grown, v)
+				v.Set(grown)
+			}
+			v.SetLen(length + 1)
+			if !d.value(n.elem, v.Index(length)) {
+				return false
+			}
+			length++
+			if !d.next() {
+				return false
+			}
+			if d.data[d.pos] == ',' {
+				d.pos++
+				continue
+			}
+			if d.data[d.pos] == ']' {
+				d.pos++
+				break
+			}
+			return false
+		}
+	}
+	d.depth--
+
+	s := n.schema
+	if s.MinItems != nil && length < *s.MinItems {
+		return false
+	}
+	if s.MaxItems != nil && length > *s.MaxItems {
+		return false
+	}
+	return true
+}
+
+func (d *fastDecoder) object(n *fastNode, v reflect.Value) bool {
+	if d.data[d.pos] != '{' {
+		return false
+	}
+	d.pos++
+	if d.depth++; d.depth > fastMaxDepth {
+		return false
+	}
+
+	// Track which fields were set to detect duplicates & missing fields. The
+	// slice stays valid even if nested objects grow `d.seen`.
+	start := len(d.seen)
+	for range n.fields {
+		d.seen = append(d.seen, false)
+	}
+	seen := d.seen[start:]
+
+	count := 0
+	unknown := false
+	if !d.next() {
+		return false
+	}
+	if d.data[d.pos] == '}' {
+		d.pos++
+	} else {
+		for {
+			if !d.next() {
+				return false
+			}
+			key, ok := d.string()
+			if !ok || !d.next() || d.data[d.pos] != ':' {
+				return false
+			}
+			d.pos++
+			count++
+
+			if i, ok := n.index[string(key)]; ok {
+				if seen[i] {
+					// Duplicate keys are left to the regular decoder.
+					return false
+				}
+				seen[i] = true
+				f := n.fields[i]
+				if f.index == -1 {
+					if f.node == nil || !d.value(f.node, reflect.Value{}) {
+						return false
+					}
+				} else if !d.value(f.node, v.Field(f.index)) {
+					return false
+				}
+			} else {
+				if !n.addl || !fastUnmatched(n, key) {
+					return false
+				}
+				unknown = true
+				if !d.skip() {
+					return false
+				}
+			}
+
+			if !d.next() {
+				return false
+			}
+			if d.data[d.pos] == ',' {
+				d.pos++
+				continue
+			}
+			if d.data[d.pos] == '}' {
+				d.pos++
+				break
+			}
+			return false
+		}
+	}
+	d.depth--
+
+	s := n.schema
+	if s.MinProperties != nil || s.MaxProperties != nil {
+		if unknown {
+			// Unknown keys aren't tracked, so they may be duplicates.
+			return false
+		}
+		if s.MinProperties != nil && count < *s.MinProperties {
+			return false
+		}
+		if s.MaxProperties != nil && count > *s.MaxProperties {
+			return false
+		}
+	}
+	for i, f := range n.fields {
+		// Read-only fields are never required when writing to the server.
+		if !seen[i] && s.requiredMap[f.name] && !f.readOnly {
+			return false
+		}
+	}
+	d.seen = d.seen[:start]
+	return true
+}
+
+// fastUnmatched returns whether an unknown key is ignored by `encoding/json`,
+// which matches field names case-insensitively.
+func fastUnmatched(n *fastNode, key []byte) bool {
+	for _, c := range key {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	str := *(*string)(unsafe.Pointer(&key))
+	for _, f := range n.fields {
+		if strings.EqualFold(f.name, str) {
+			return false
+		}
+	}
+	return true
+}
+
+// skip skips over a value, checking that it is valid JSON.
+func (d *fastDecoder) skip() bool {
+	if !d.next() {
+		return false
+	}
+	switch d.data[d.pos] {
+	case '"':
+		_, ok := d.string()
+		return ok
+	case 't':
+		return d.literal("true")
+	case 'f':
+		return d.literal("false")
+	case 'n':
+		return d.literal("null")
+	case '{', '[':
+		end := byte('}')
+		if d.data[d.pos] == '[' {
+			end = ']'
+		}
+		d.pos++
+		if d.depth++; d.depth > fastMaxDepth || !d.next() {
+			return false
+		}
+		if d.data[d.pos] == end {
+			d.pos++
+			d.depth--
+			return true
+		}
+		for {
+			if end == '}' {
+				if !d.next() {
+					return false
+				}
+				if _, ok := d.string(); !ok || !d.next() || d.data[d.pos] != ':' {
+					return false
+				}
+				d.pos++
+			}
+			if !d.skip() || !d.next() {
+				return false
+			}
+			if d.data[d.pos] == ',' {
+				d.pos++
+				continue
+			}
+			if d.data[d.pos] == end {
+				d.pos++
+				d.depth--
+				return true
+			}
+			return false
+		}
+	default:
+		_, ok := d.number()
+		return ok
+	}
+}
+
+func (d *fastDecoder) literal(lit string) bool {
+	if len(d.data)-d.pos < len(lit) {
+		return false
+	}
+	b := d.data[d.pos : d.pos+len(lit)]
+	if *(*string)(unsafe.Pointer(&b)) != lit {
+		return false
+	}
+	d.pos += len(lit)
+	return true
+}
+
+// number reads a number, returning its literal bytes.
+func (d *fastDecoder) number() ([]byte, bool) {
+	start := d.pos
+	if d.pos < len(d.data) && d.data[d.pos] == '-' {
+		d.pos++
+	}
+	if d.pos < len(d.data) && d.data[d.pos] == '0' {
+		d.pos++
+	} else if !d.digits() {
+		return nil, false
+	}
+	if d.pos < len(d.data) && d.data[d.pos] == '.' {
+		d.pos++
+		if !d.digits() {
+			return nil, false
+		}
+	}
+	if d.pos < len(d.data) && (d.data[d.pos] == 'e' || d.data[d.pos] == 'E') {
+		d.pos++
+		if d.pos < len(d.data) && (d.data[d.pos] == '+' || d.data[d.pos] == '-') {
+			d.pos++
+		}
+		if !d.digits() {
+			return nil, false
+		}
+	}
+	return d.data[start:d.pos], true
+}
+
+// digits reads one or more digits.
+func (d *fastDecoder) digits() bool {
+	start := d.pos
+	for d.pos < len(d.data) && d.data[d.pos] >= '0' && d.data[d.pos] <= '9' {
+		d.pos++
+	}
+	return d.pos > start
+}
+
+// string reads a string, returning its unescaped bytes. The bytes are only
+// valid until the next call.
+func (d *fastDecoder) string() ([]byte, bool) {
+	if d.data[d.pos] != '"' {
+		return nil, false
+	}
+	d.pos++
+	start := d.pos
+	ascii := true
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		switch {
+		case c == '"':
+			b := d.data[start:d.pos]
+			d.pos++
+			// Invalid UTF-8 gets replaced by `encoding/json`.
+			if !ascii && !utf8.Valid(b) {
+				return nil, false
+			}
+			return b, true
+		case c == '\\':
+			return d.unescape(start)
+		case c < 0x20:
+			return nil, false
+		case c >= utf8.RuneSelf:
+			ascii = false
+		}
+		d.pos++
+	}
+	return nil, false
+}
+
+// unescape continues reading a string with escapes into the scratch buffer.
+func (d *fastDecoder) unescape(start int) ([]byte, bool) {
+	buf := append(d.scratch[:0], d.data[start:d.pos]...)
+
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		switch {
+		case c == '"':
+			d.pos++
+			d.scratch = buf[:0]
+			if !utf8.Valid(buf) {
+				return nil, false
+			}
+			return buf, true
+		case c < 0x20:
+			return nil, false
+		case c != '\\':
+			buf = append(buf, c)
+			d.pos++
+			continue
+		}
+
+		if d.pos+1 >= len(d.data) {
+			return nil, false
+		}
+		switch e := d.data[d.pos+1]; e {
+		case '"', '\\', '/':
+			buf = append(buf, e)
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, ok := d.hex4(d.pos + 2)
+			if !ok {
+				return nil, false
+			}
+			d.pos += 6
+			if utf16.IsSurrogate(r) {
+				// Lone surrogates get replaced by `encoding/json`.
+				if d.pos+1 >= len(d.data) || d.data[d.pos] != '\\' || d.data[d.pos+1] != 'u' {
+					return nil, false
+				}
+				r2, ok := d.hex4(d.pos + 2)
+				if !ok {
+					return nil, false
+				}
+				if r = utf16.DecodeRune(r, r2); r == utf8.RuneError {
+					return nil, false
+				}
+				d.pos += 6
+			}
+			buf = utf8.AppendRune(buf, r)
+			continue
+		default:
+			return nil, false
+		}
+		d.pos += 2
+	}
+	return nil, false
+}
+
+func (d *fastDecoder) hex4(i int) (rune, bool) {
+	if len(d.data)-i < 4 {
+		return 0, false
+	}
+	var r rune
+	for _, c := range d.data[i : i+4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
+
+// fastValidNumber mirrors the number checks of `Validate`.
+func fastValidNumber(s *Schema, num float64) bool {
+	if s.Type == TypeInteger && num != math.Trunc(num) {
+		return false
+	}
+	if s.Minimum != nil && num < *s.Minimum {
+		return false
+	}
+	if s.ExclusiveMinimum != nil && num <= *s.ExclusiveMinimum {
+		return false
+	}
+	if s.Maximum != nil && num > *s.Maximum {
+		return false
+	}
+	if s.ExclusiveMaximum != nil && num >= *s.ExclusiveMaximum {
+		return false
+	}
+	if s.MultipleOf != nil && math.Mod(num, *s.MultipleOf) != 0 {
+		return false
+	}
+	if len(s.Enum) > 0 {
+		for _, e := range s.Enum {
+			if e == any(num) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// fastValidString mirrors the string checks of `Validate`.
+func fastValidString(s *Schema, str string) bool {
+	if s.MinLength != nil || s.MaxLength != nil {
+		count := utf8.RuneCountInString(str)
+		if s.MinLength != nil && count < *s.MinLength {
+			return false
+		}
+		if s.MaxLength != nil && count > *s.MaxLength {
+			return false
+		}
+	}
+	if s.patternRe != nil && !s.patternRe.MatchString(str) {
+		return false
+	}
+	if s.Format != "" {
+		if validator := formats[s.Format]; validator != nil && validator(str) != nil {
+			return false
+		}
+	}
+	if s.ContentEncoding == "base64" && !rxBase64.MatchString(str) {
+		return false
+	}
+	if len(s.Enum) > 0 {
+		for _, e := range s.Enum {
+			if e == any(str) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func fastEnumBool(s *Schema, b bool) bool {
+	for _, e := range s.Enum {
+		if e == any(b) {
+			return true
+		}
+	}
+	return false
+}
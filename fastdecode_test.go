@@ -0,0 +1,248 @@
+package huma_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type FastItem struct {
+	ID       string     `json:"id" readOnly:"true"`
+	Name     string     `json:"name" minLength:"1" maxLength:"10"`
+	Count    int        `json:"count,omitempty" minimum:"0" maximum:"100" default:"5"`
+	Price    float64    `json:"price,omitempty" multipleOf:"0.5"`
+	Kind     string     `json:"kind,omitempty" enum:"a,b"`
+	Level    uint8      `json:"level,omitempty" enum:"1,2"`
+	Small    int8       `json:"small,omitempty"`
+	Ratio    float32    `json:"ratio,omitempty"`
+	On       *bool      `json:"on,omitempty"`
+	Email    string     `json:"email,omitempty" format:"email"`
+	Tags     []string   `json:"tags,omitempty" maxItems:"3"`
+	Children []FastItem `json:"children,omitempty"`
+}
+
+type FastOpen struct {
+	Name string `json:"name"`
+	Meta struct {
+		Size int `json:"size,omitempty"`
+	} `json:"meta,omitempty" additionalProperties:"true"`
+}
+
+type FastBody struct {
+	Body FastItem
+}
+
+type FastBodyOutput struct {
+	Body FastItem
+}
+
+func newFastAPI(t testing.TB) humatest.TestAPI {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	handler := func(ctx context.Context, input *FastBody) (*FastBodyOutput, error) {
+		return &FastBodyOutput{Body: input.Body}, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "slow",
+		Method:      http.MethodPut,
+		Path:        "/slow",
+	}, handler)
+	huma.Register(api, huma.Operation{
+		OperationID:    "fast",
+		Method:         http.MethodPut,
+		Path:           "/fast",
+		FastBodyDecode: true,
+	}, handler)
+
+	openHandler := func(ctx context.Context, input *struct{ Body FastOpen }) (*struct{ Body FastOpen }, error) {
+		return &struct{ Body FastOpen }{Body: input.Body}, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "slow-open",
+		Method:      http.MethodPut,
+		Path:        "/slow-open",
+	}, openHandler)
+	huma.Register(api, huma.Operation{
+		OperationID:    "fast-open",
+		Method:         http.MethodPut,
+		Path:           "/fast-open",
+		FastBodyDecode: true,
+	}, openHandler)
+
+	return api
+}
+
+func TestFastBodyDecode(t *testing.T) {
+	api := newFastAPI(t)
+
+	for _, body := range []string{
+		// Valid bodies.
+		`{"name": "x"}`,
+		` { "id": "abc", "name" : "x" , "count": 0 } `,
+		`{"name": "full", "count": 100, "price": 1.5, "kind": "b", "level": 2, "small": -128, "ratio": 0.25, "on": true, "email": "a@example.com", "tags": ["a", "b"], "children": [{"name": "kid", "children": []}]}`,
+		`{"name": "esc\"\\\/\b\f\n\r\t"}`,
+		`{"name": "é😀"}`,
+		`{"name": "héllo 😀"}`,
+		`{"name": "x", "tags": []}`,
+		`{"name": "x", "count": 1e1}`,
+		`{"name": "x", "price": -0.5e0}`,
+		`{"name": "x", "on": false}`,
+		`{"$schema": "https://example.com/schemas/FastItem.json", "name": "x"}`,
+
+		// Invalid or unusual bodies.
+		`{"name": ""}`,
+		`{"name": "this is too long"}`,
+		`{}`,
+		`{"name": "x", "count": -1}`,
+		`{"name": "x", "count": 1.5}`,
+		`{"name": "x", "count": "5"}`,
+		`{"name": "x", "price": 1.2}`,
+		`{"name": "x", "kind": "c"}`,
+		`{"name": "x", "level": 3}`,
+		`{"name": "x", "small": 300}`,
+		`{"name": "x", "ratio": 1e40}`,
+		`{"name": "x", "on": null}`,
+		`{"name": "x", "email": "nope"}`,
+		`{"name": "x", "tags": ["a", "b", "c", "d"]}`,
+		`{"name": "x", "children": [{"name": ""}]}`,
+		`{"name": "x", "extra": true}`,
+		`{"$schema": 5, "name": "x"}`,
+		`{"Name": "x"}`,
+		`{"name": "x", "name": "y"}`,
+		`{"name": "\ud83d"}`,
+		`{"name": "x"} trailing`,
+		`{"name": "x",}`,
+		`{"name": 01}`,
+		`{"name": "x"`,
+		`[]`,
+		`null`,
+	} {
+		t.Run(body, func(t *testing.T) {
+			slow := api.Put("/slow", strings.NewReader(body))
+			fast := api.Put("/fast", strings.NewReader(body))
+			assert.Equal(t, slow.Code, fast.Code)
+			assert.Equal(t, slow.Body.String(), fast.Body.String())
+		})
+	}
+
+	for _, body := range []string{
+		`{"name": "x", "meta": {"size": 1, "other": {"a": [1, "b", null, true]}}}`,
+		`{"name": "x", "meta": {"Size": 1}}`,
+		`{"name": "x", "meta": {"other": [}}`,
+	} {
+		t.Run(body, func(t *testing.T) {
+			slow := api.Put("/slow-open", strings.NewReader(body))
+			fast := api.Put("/fast-open", strings.NewReader(body))
+			assert.Equal(t, slow.Code, fast.Code)
+			assert.Equal(t, slow.Body.String(), fast.Body.String())
+		})
+	}
+
+	// Other content types use the API's formats.
+	resp := api.Put("/fast", "Content-Type: application/cbor", strings.NewReader(`{"name": "x"}`))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestFastBodyDecodeAllocs(t *testing.T) {
+	api := newFastAPI(t)
+	body := []byte(`{"name": "widget", "count": 3, "price": 2.5, "kind": "a", "tags": ["a", "b"]}`)
+
+	allocs := func(path string) float64 {
+		return testing.AllocsPerRun(100, func() {
+			req, _ := http.NewRequest(http.MethodPut, path, bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			api.Adapter().ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+
+	slow := allocs("/slow")
+	fast := allocs("/fast")
+	assert.Less(t, fast, slow)
+}
+
+func TestFastBodyDecodeUnsupported(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	assert.PanicsWithValue(t, "fast body decoding requires a JSON request body", func() {
+		huma.Register(api, huma.Operation{
+			OperationID:    "no-body",
+			Method:         http.MethodGet,
+			Path:           "/no-body",
+			FastBodyDecode: true,
+		}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithError(t, "fast body decoding is not supported for time: body.at: type time.Time has a custom unmarshaler", func() {
+		huma.Register(api, huma.Operation{
+			OperationID:    "time",
+			Method:         http.MethodPut,
+			Path:           "/time",
+			FastBodyDecode: true,
+		}, func(ctx context.Context, input *struct {
+			Body struct {
+				At time.Time `json:"at"`
+			}
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+
+	assert.PanicsWithError(t, "fast body decoding is not supported for map: body.labels: type map[string]string is not supported", func() {
+		huma.Register(api, huma.Operation{
+			OperationID:    "map",
+			Method:         http.MethodPut,
+			Path:           "/map",
+			FastBodyDecode: true,
+		}, func(ctx context.Context, input *struct {
+			Body struct {
+				Labels map[string]string `json:"labels"`
+			}
+		}) (*struct{}, error) {
+			return nil, nil
+		})
+	})
+}
+
+var BenchmarkFastBodyResponse *httptest.ResponseRecorder
+
+// BenchmarkFastBodyDecode compares the regular body decoding, which parses the
+// body into maps for validation and then decodes it again, with the single
+// pass fast path.
+func BenchmarkFastBodyDecode(b *testing.B) {
+	api := newFastAPI(b)
+	body := []byte(`{"name": "widget", "count": 3, "price": 2.5, "kind": "a", "email": "a@example.com", "tags": ["a", "b"], "children": [{"name": "kid"}]}`)
+
+	for _, path := range []string{"/slow", "/fast"} {
+		b.Run(strings.TrimPrefix(path, "/"), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				req, _ := http.NewRequest(http.MethodPut, path, bytes.NewReader(body))
+				w := httptest.NewRecorder()
+				api.Adapter().ServeHTTP(w, req)
+				BenchmarkFastBodyResponse = w
+			}
+		})
+	}
+}
+
+func TestBodyParseErrorValue(t *testing.T) {
+	api := newFastAPI(t)
+
+	for _, path := range []string{"/slow", "/fast"} {
+		resp := api.Put(path, strings.NewReader(`{"name": "x"`))
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"value":"{\"name\": \"x\""`)
+	}
+}
@@ -0,0 +1,280 @@
+// Package health provides health, readiness, and build information endpoints
+// for Huma APIs, which are commonly used by load balancers and orchestrators
+// like Kubernetes to decide whether a service should receive traffic.
+//
+// Checks are functions which return an error when a dependency is unhealthy,
+// for example when a database ping fails. All checks for an endpoint are run
+// concurrently and their results are aggregated into a single status body,
+// which is returned with a `503 Service Unavailable` if any check fails.
+//
+//	health.Register(api, health.Options{
+//		Ready: map[string]health.Checker{
+//			"database": func(ctx context.Context) error {
+//				return db.PingContext(ctx)
+//			},
+//		},
+//	})
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Default paths for the registered endpoints.
+const (
+	DefaultLivePath    = "/healthz"
+	DefaultReadyPath   = "/readyz"
+	DefaultVersionPath = "/version"
+)
+
+// DefaultTimeout is the default time allowed for each check to complete.
+var DefaultTimeout = 5 * time.Second
+
+// Status values for checks and aggregated results.
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// Checker checks a single dependency, returning an error if it is unhealthy.
+// Checkers should respect context cancelation, which happens once the check
+// timeout is reached.
+type Checker func(ctx context.Context) error
+
+// Options configures the health endpoints.
+type Options struct {
+	// Live checks are run by the liveness endpoint, and should only fail when
+	// the service needs to be restarted. Most services need none, in which
+	// case the endpoint always passes while the service can respond.
+	Live map[string]Checker
+
+	// Ready checks are run by the readiness endpoint, and should fail when the
+	// service cannot currently handle requests, e.g. because a database is
+	// unreachable.
+	Ready map[string]Checker
+
+	// Timeout is the time allowed for each check to complete before it is
+	// considered failed. Defaults to `DefaultTimeout`.
+	Timeout time.Duration
+
+	// LivePath, ReadyPath, and VersionPath set the paths for the endpoints,
+	// defaulting to `/healthz`, `/readyz`, and `/version`. Set a path to `-`
+	// to skip registering that endpoint.
+	LivePath    string
+	ReadyPath   string
+	VersionPath string
+
+	// Version overrides the version from the build information, e.g. when it
+	// is set at build time via `-ldflags`.
+	Version string
+
+	// Hidden excludes the endpoints from the OpenAPI document.
+	Hidden bool
+
+	// Tags are added to each of the registered operations.
+	Tags []string
+}
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	Status   string `json:"status" enum:"pass,fail" doc:"Whether the check passed"`
+	Error    string `json:"error,omitempty" doc:"Why the check failed"`
+	Duration int64  `json:"durationMs" doc:"How long the check took in milliseconds"`
+}
+
+// HealthStatus is the aggregated result of all checks for an endpoint.
+type HealthStatus struct {
+	Status string                 `json:"status" enum:"pass,fail" doc:"Whether all checks passed"`
+	Checks map[string]CheckResult `json:"checks,omitempty" doc:"Results of the individual checks"`
+}
+
+// BuildInfo describes the running build of the service.
+type BuildInfo struct {
+	Version   string `json:"version" doc:"Service version"`
+	GoVersion string `json:"goVersion" doc:"Go version used to build the service"`
+	Revision  string `json:"revision,omitempty" doc:"Version control revision"`
+	Time      string `json:"time,omitempty" doc:"Version control commit time"`
+	Modified  bool   `json:"modified,omitempty" doc:"Whether the build had uncommitted changes"`
+}
+
+type statusOutput struct {
+	Status int
+	Body   *HealthStatus
+}
+
+type versionOutput struct {
+	Body *BuildInfo
+}
+
+// Register adds the liveness, readiness, and version endpoints to the API.
+func Register(api huma.API, opts Options) {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.LivePath == "" {
+		opts.LivePath = DefaultLivePath
+	}
+	if opts.ReadyPath == "" {
+		opts.ReadyPath = DefaultReadyPath
+	}
+	if opts.VersionPath == "" {
+		opts.VersionPath = DefaultVersionPath
+	}
+
+	if opts.LivePath != "-" {
+		registerChecks(api, &opts, huma.Operation{
+			OperationID: "get-health-live",
+			Summary:     "Get liveness",
+			Description: "Returns whether the service is running.",
+			Path:        opts.LivePath,
+		}, opts.Live)
+	}
+
+	if opts.ReadyPath != "-" {
+		registerChecks(api, &opts, huma.Operation{
+			OperationID: "get-health-ready",
+			Summary:     "Get readiness",
+			Description: "Returns whether the service is ready to handle requests.",
+			Path:        opts.ReadyPath,
+		}, opts.Ready)
+	}
+
+	if opts.VersionPath != "-" {
+		info := buildInfo(opts.Version)
+		huma.Register(api, huma.Operation{
+			OperationID: "get-version",
+			Summary:     "Get version",
+			Description: "Returns build information for the running service.",
+			Method:      http.MethodGet,
+			Path:        opts.VersionPath,
+			Tags:        opts.Tags,
+			Hidden:      opts.Hidden,
+		}, func(ctx context.Context, input *struct{}) (*versionOutput, error) {
+			return &versionOutput{Body: info}, nil
+		})
+	}
+}
+
+// registerChecks registers an operation which runs the checks.
+func registerChecks(api huma.API, opts *Options, op huma.Operation, checks map[string]Checker) {
+	op.Method = http.MethodGet
+	op.Tags = opts.Tags
+	op.Hidden = opts.Hidden
+
+	if !opts.Hidden {
+		// Failed checks return the same body with a different status code.
+		schema := api.OpenAPI().Components.Schemas.Schema(reflect.TypeOf(HealthStatus{}), true, "HealthStatus")
+		op.Responses = map[string]*huma.Response{
+			"200": {
+				Description: "Service is healthy",
+				Content:     map[string]*huma.MediaType{"application/json": {Schema: schema}},
+			},
+			"503": {
+				Description: "Service is unhealthy",
+				Content:     map[string]*huma.MediaType{"application/json": {Schema: schema}},
+			},
+		}
+	}
+
+	timeout := opts.Timeout
+	huma.Register(api, op, func(ctx context.Context, input *struct{}) (*statusOutput, error) {
+		result := Run(ctx, timeout, checks)
+		status := http.StatusOK
+		if result.Status != StatusPass {
+			status = http.StatusServiceUnavailable
+		}
+		return &statusOutput{Status: status, Body: result}, nil
+	})
+}
+
+// Run runs the checks concurrently and returns their aggregated status,
+// which only passes if every check passes. Each check is given up to the
+// timeout to complete.
+func Run(ctx context.Context, timeout time.Duration, checks map[string]Checker) *HealthStatus {
+	result := &HealthStatus{Status: StatusPass}
+	if len(checks) == 0 {
+		return result
+	}
+
+	result.Checks = make(map[string]CheckResult, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Checker) {
+			defer wg.Done()
+			status := runCheck(ctx, timeout, check)
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[name] = status
+			if status.Status != StatusPass {
+				result.Status = StatusFail
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// runCheck runs a single check, failing it if it panics or does not return
+// within the timeout.
+func runCheck(ctx context.Context, timeout time.Duration, check Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- errors.New("check panicked")
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.New("check timed out")
+	}
+
+	status := CheckResult{Status: StatusPass, Duration: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = StatusFail
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// buildInfo returns information about the running build.
+func buildInfo(version string) *BuildInfo {
+	info := &BuildInfo{Version: version}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		if info.Version == "" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.time":
+				info.Time = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	_, api := humatest.New(t)
+
+	dbErr := errors.New("connection refused")
+	var dbDown bool
+	Register(api, Options{
+		Ready: map[string]Checker{
+			"database": func(ctx context.Context) error {
+				if dbDown {
+					return dbErr
+				}
+				return nil
+			},
+			"cache": func(ctx context.Context) error {
+				return nil
+			},
+		},
+		Version: "v1.2.3",
+		Tags:    []string{"Health"},
+	})
+
+	resp := api.Get("/healthz")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"status": "pass"}`, resp.Body.String())
+
+	resp = api.Get("/readyz")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var status HealthStatus
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	assert.Equal(t, StatusPass, status.Status)
+	assert.Equal(t, StatusPass, status.Checks["database"].Status)
+	assert.Equal(t, StatusPass, status.Checks["cache"].Status)
+
+	dbDown = true
+	resp = api.Get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	status = HealthStatus{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	assert.Equal(t, StatusFail, status.Status)
+	assert.Equal(t, CheckResult{Status: StatusFail, Error: "connection refused"}, status.Checks["database"])
+	assert.Equal(t, StatusPass, status.Checks["cache"].Status)
+
+	resp = api.Get("/version")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var info BuildInfo
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+
+	ready := api.OpenAPI().Paths["/readyz"].Get
+	require.NotNil(t, ready)
+	assert.Equal(t, []string{"Health"}, ready.Tags)
+	assert.Equal(t, "#/components/schemas/HealthStatus", ready.Responses["503"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/HealthStatus", ready.Responses["200"].Content["application/json"].Schema.Ref)
+}
+
+func TestHealthHidden(t *testing.T) {
+	_, api := humatest.New(t)
+	Register(api, Options{
+		Hidden:      true,
+		LivePath:    "/internal/live",
+		VersionPath: "-",
+	})
+
+	assert.Equal(t, http.StatusOK, api.Get("/internal/live").Code)
+	assert.Equal(t, http.StatusOK, api.Get("/readyz").Code)
+	assert.Equal(t, http.StatusNotFound, api.Get("/version").Code)
+	assert.Empty(t, api.OpenAPI().Paths)
+}
+
+func TestRun(t *testing.T) {
+	status := Run(context.Background(), 10*time.Millisecond, map[string]Checker{
+		"slow": func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+		"panics": func(ctx context.Context) error {
+			panic("boom")
+		},
+		"ok": func(ctx context.Context) error {
+			return nil
+		},
+	})
+
+	assert.Equal(t, StatusFail, status.Status)
+	assert.Equal(t, "check timed out", status.Checks["slow"].Error)
+	assert.Equal(t, "check panicked", status.Checks["panics"].Error)
+	assert.Equal(t, StatusPass, status.Checks["ok"].Status)
+
+	assert.Equal(t, &HealthStatus{Status: StatusPass}, Run(context.Background(), time.Second, nil))
+}
@@ -11,11 +11,14 @@ import (
 	"bytes"
 	"context"
 	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net"
 	"net/http"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -29,10 +32,19 @@ import (
 var errDeadlineUnsupported = fmt.Errorf("%w", http.ErrNotSupported)
 
 var bodyCallbackType = reflect.TypeOf(func(Context) {})
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
 var cookieType = reflect.TypeOf((*http.Cookie)(nil)).Elem()
 var fmtStringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 var stringType = reflect.TypeOf("")
 
+// defaultMaxBodyBytes is the request body size limit used when neither the
+// operation nor the API config set one.
+const defaultMaxBodyBytes int64 = 1024 * 1024
+
+// defaultBodyReadTimeout is the request body read timeout used when neither
+// the operation nor the API config set one.
+const defaultBodyReadTimeout = 5 * time.Second
+
 // slicesIndex returns the index of the first occurrence of v in s,
 // or -1 if not present.
 func slicesIndex[E comparable](s []E, v E) int {
@@ -68,6 +80,20 @@ func SetReadDeadline(w http.ResponseWriter, deadline time.Time) error {
 	}
 }
 
+// ParseMultipartForm is a utility for adapters to parse a multipart form from
+// a request, limiting the body to the operation's `MaxBodyBytes` so chunked
+// uploads or those without a `Content-Length` can't exceed it. Reading past
+// the limit returns an `*http.MaxBytesError`.
+//
+//	form, err := huma.ParseMultipartForm(op, w, r, MultipartMaxMemory)
+func ParseMultipartForm(op *Operation, w http.ResponseWriter, r *http.Request, maxMemory int64) (*multipart.Form, error) {
+	if op != nil && op.MaxBodyBytes > 0 && r.MultipartForm == nil {
+		r.Body = http.MaxBytesReader(w, r.Body, op.MaxBodyBytes)
+	}
+	err := r.ParseMultipartForm(maxMemory)
+	return r.MultipartForm, err
+}
+
 // StreamResponse is a response that streams data to the client. The body
 // function will be called once the response headers have been written and
 // the body writer is ready to be written to.
@@ -102,6 +128,7 @@ type paramFieldInfo struct {
 	Required   bool
 	Default    string
 	TimeFormat string
+	Explode    bool
 	Schema     *Schema
 }
 
@@ -138,9 +165,10 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 			pfi.Loc = "query"
 			name = q
 			// If `in` is `query` then `explode` defaults to true. Parsing is *much*
-			// easier if we use comma-separated values, so we disable explode.
-			nope := false
-			explode = &nope
+			// easier if we use comma-separated values, so we disable explode
+			// unless the field opts in to repeated values like `?tag=a&tag=b`.
+			pfi.Explode = boolTag(f, "explode") && f.Type.Kind() == reflect.Slice
+			explode = &pfi.Explode
 		} else if h := f.Tag.Get("header"); h != "" {
 			pfi.Loc = "header"
 			name = h
@@ -185,12 +213,13 @@ func findParams(registry Registry, op *Operation, t reflect.Type) *findResult[*p
 		if !boolTag(f, "hidden") {
 			// Document the parameter if not hidden.
 			op.Parameters = append(op.Parameters, &Param{
-				Name:     name,
-				In:       pfi.Loc,
-				Explode:  explode,
-				Required: pfi.Required,
-				Schema:   pfi.Schema,
-				Example:  example,
+				Name:       name,
+				In:         pfi.Loc,
+				Explode:    explode,
+				Required:   pfi.Required,
+				Deprecated: pfi.Schema.Deprecated,
+				Schema:     pfi.Schema,
+				Example:    example,
 			})
 		}
 
@@ -221,6 +250,112 @@ func findDefaults(registry Registry, t reflect.Type) *findResult[any] {
 	}, true)
 }
 
+// discriminatorInfo describes an interface field whose concrete type is
+// selected by the value of a discriminator property in the payload.
+type discriminatorInfo struct {
+	prop   string
+	types  map[string]reflect.Type
+	nested map[string]*findResult[*discriminatorInfo]
+}
+
+// findDiscriminators finds interface fields tagged with `discriminator` which
+// can be decoded into the concrete Go types registered for their mapping.
+// Concrete types are searched too, with `seen` breaking recursive types.
+func findDiscriminators(registry Registry, t reflect.Type, seen map[reflect.Type]*findResult[*discriminatorInfo]) *findResult[*discriminatorInfo] {
+	if r, ok := seen[t]; ok {
+		return r
+	}
+	result := &findResult[*discriminatorInfo]{}
+	seen[t] = result
+	found := findInType(t, nil, func(sf reflect.StructField, i []int) *discriminatorInfo {
+		prop := sf.Tag.Get("discriminator")
+		if prop == "" || sf.Type.Kind() != reflect.Interface {
+			return nil
+		}
+		s := registry.Schema(sf.Type, true, "")
+		if s.Ref != "" {
+			s = registry.SchemaFromRef(s.Ref)
+		}
+		d := &discriminatorInfo{
+			prop:   prop,
+			types:  map[string]reflect.Type{},
+			nested: map[string]*findResult[*discriminatorInfo]{},
+		}
+		for value, ref := range discriminatorFor(registry, sf.Name, s, prop).Mapping {
+			if typ := registry.TypeFromRef(ref); typ != nil {
+				d.types[value] = typ
+				d.nested[value] = findDiscriminators(registry, typ, seen)
+			}
+		}
+		if len(d.types) == 0 {
+			return nil
+		}
+		return d
+	}, true)
+	result.Paths = found.Paths
+	return result
+}
+
+// decode replaces the generic value which was unmarshaled into the interface
+// field `item` with the concrete type selected by the discriminator value.
+func (d *discriminatorInfo) decode(api API, contentType string, item reflect.Value) error {
+	if item.Kind() != reflect.Interface || item.IsNil() || !item.CanSet() {
+		return nil
+	}
+	var value any
+	switch m := item.Elem().Interface().(type) {
+	case map[string]any:
+		value = m[d.prop]
+	case map[any]any:
+		value = m[d.prop]
+	default:
+		return nil
+	}
+	name, _ := value.(string)
+	typ := d.types[name]
+	if typ == nil {
+		return nil
+	}
+
+	// Round-trip the generic value through the request's format to decode it
+	// into the concrete type.
+	if end := strings.IndexRune(contentType, ';'); end != -1 {
+		contentType = contentType[:end]
+	}
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}()
+	if err := api.Marshal(buf, contentType, item.Interface()); err != nil {
+		return err
+	}
+	concrete := reflect.New(typ)
+	if err := api.Unmarshal(contentType, buf.Bytes(), concrete.Interface()); err != nil {
+		return err
+	}
+
+	var err error
+	d.nested[name].Every(concrete.Elem(), func(item reflect.Value, nd *discriminatorInfo) {
+		if derr := nd.decode(api, contentType, item); derr != nil && err == nil {
+			err = derr
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if typ.AssignableTo(item.Type()) {
+		item.Set(concrete.Elem())
+	} else if concrete.Type().AssignableTo(item.Type()) {
+		item.Set(concrete)
+	}
+	return nil
+}
+
 type headerInfo struct {
 	Field      reflect.StructField
 	Name       string
@@ -396,7 +531,10 @@ func _findInType[T comparable](t reflect.Type, path []int, result *findResult[T]
 		if _, ok := visited[t]; ok {
 			return
 		}
+		// Only types on the current path are tracked, which stops recursive
+		// types while still finding fields in every use of a shared type.
 		visited[t] = struct{}{}
+		defer delete(visited, t)
 		for i := 0; i < t.NumField(); i++ {
 			f := t.Field(i)
 			if !f.IsExported() {
@@ -525,6 +663,56 @@ func writeHeader(write func(string, string), info *headerInfo, f reflect.Value)
 	}
 }
 
+// uniqueOperationID ensures the operation's ID is not already used by a
+// different operation, as operation IDs must be unique within the OpenAPI
+// and are commonly used by tooling to generate client method names. Generated
+// IDs which collide, e.g. `get-things-x` for both `/things-x` and `/things/x`,
+// get a numeric suffix while explicitly set duplicates panic.
+func uniqueOperationID(oapi *OpenAPI, op *Operation, generated bool) {
+	key := op.Method + " " + op.Path
+	id := op.OperationID
+	for i := 2; ; i++ {
+		owner, ok := oapi.operationIDs[id]
+		if !ok || owner == key {
+			// Unused, or re-registering the same operation which replaces it.
+			break
+		}
+		if !generated {
+			panic(fmt.Sprintf("duplicate operation ID %q used by %s and %s", id, owner, key))
+		}
+		id = op.OperationID + "-" + strconv.Itoa(i)
+	}
+	op.OperationID = id
+}
+
+// unmarshalGeneric decodes a body for a format which only supports typed
+// values into generic values suitable for validation by first decoding into
+// the body's type, then converting via JSON. Since every field of the typed
+// value is present, fields missing from XML bodies are removed again so they
+// are validated like any other format. For other formats, missing fields are
+// not detected unless they are omitted by `omitempty`.
+func unmarshalGeneric(api API, contentType string, data []byte, t reflect.Type, v *any) error {
+	typed := reflect.New(t)
+	if err := api.Unmarshal(contentType, data, typed.Interface()); err != nil {
+		return err
+	}
+	b, err := json.Marshal(typed.Interface())
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	if isXMLContentType(contentType) {
+		root, err := xmlPresence(data)
+		if err != nil {
+			return err
+		}
+		prunePresent(t, *v, root)
+	}
+	return nil
+}
+
 // Register an operation handler for an API. The handler must be a function that
 // takes a context and a pointer to the input struct and returns a pointer to the
 // output struct and an error. The input struct must be a struct with fields
@@ -554,18 +742,33 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		panic("method and path must be specified in operation")
 	}
 
+	config := &Config{}
+	if oapi.config != nil {
+		config = oapi.config
+	}
+
+	generatedID := op.OperationID == ""
+	if generatedID {
+		// Codegen tools generally require a unique ID for every operation.
+		op.OperationID = GenerateOperationID(op.Method, op.Path, (*O)(nil))
+	}
+	if !op.Hidden {
+		uniqueOperationID(oapi, &op, generatedID)
+	}
+
 	inputType := reflect.TypeOf((*I)(nil)).Elem()
 	if inputType.Kind() != reflect.Struct {
 		panic("input must be a struct")
 	}
+	op.InputType = inputType
 	inputParams := findParams(registry, &op, inputType)
 	inputBodyIndex := -1
 	if f, ok := inputType.FieldByName("Body"); ok {
 		inputBodyIndex = f.Index[0]
 		if op.RequestBody == nil {
 			required := f.Type.Kind() != reflect.Ptr && f.Type.Kind() != reflect.Interface
-			if f.Tag.Get("required") == "true" {
-				required = true
+			if _, ok := f.Tag.Lookup("required"); ok {
+				required = boolTag(f, "required")
 			}
 
 			contentType := "application/json"
@@ -586,13 +789,17 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		}
 
 		if op.BodyReadTimeout == 0 {
-			// 5 second default
-			op.BodyReadTimeout = 5 * time.Second
+			op.BodyReadTimeout = config.BodyReadTimeout
+			if op.BodyReadTimeout == 0 {
+				op.BodyReadTimeout = defaultBodyReadTimeout
+			}
 		}
 
 		if op.MaxBodyBytes == 0 {
-			// 1 MB default
-			op.MaxBodyBytes = 1024 * 1024
+			op.MaxBodyBytes = config.MaxBodyBytes
+			if op.MaxBodyBytes == 0 {
+				op.MaxBodyBytes = defaultMaxBodyBytes
+			}
 		}
 	}
 	rawBodyIndex := -1
@@ -650,13 +857,27 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		inSchema = op.RequestBody.Content["application/json"].Schema
 	}
 
+	var fastBody *fastNode
+	if op.FastBodyDecode && !op.SkipValidateBody {
+		if inputBodyIndex == -1 || inSchema == nil {
+			panic("fast body decoding requires a JSON request body")
+		}
+		plan, err := newFastPlan(registry, inSchema, inputType.Field(inputBodyIndex).Type)
+		if err != nil {
+			panic(fmt.Errorf("fast body decoding is not supported for %s: %w", op.OperationID, err))
+		}
+		fastBody = plan
+	}
+
 	resolvers := findResolvers(resolverType, inputType)
 	defaults := findDefaults(registry, inputType)
+	discriminators := findDiscriminators(registry, inputType, map[reflect.Type]*findResult[*discriminatorInfo]{})
 
 	if op.Responses == nil {
 		op.Responses = map[string]*Response{}
 	}
 	outputType := reflect.TypeOf((*O)(nil)).Elem()
+	op.OutputType = outputType
 	if outputType.Kind() != reflect.Struct {
 		panic("output must be a struct")
 	}
@@ -667,13 +888,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		if f.Type.Kind() != reflect.Int {
 			panic("status field must be an int")
 		}
-		// TODO: enum tag?
-		// TODO: register each of the possible responses with the right model
-		//       and headers down below.
 	}
 	outHeaders := findHeaders(outputType)
 	outBodyIndex := -1
 	outBodyFunc := false
+	outBodyReader := false
+	outBodyContentType := ""
 	if f, ok := outputType.FieldByName("Body"); ok {
 		outBodyIndex = f.Index[0]
 		if f.Type.Kind() == reflect.Func {
@@ -683,6 +903,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				panic("body field must be a function with signature func(huma.Context)")
 			}
 		}
+		if f.Type.Implements(readerType) {
+			outBodyReader = true
+			outBodyContentType = f.Tag.Get("contentType")
+			if outBodyContentType == "" {
+				outBodyContentType = "application/octet-stream"
+			}
+		}
 		status := op.DefaultStatus
 		if status == 0 {
 			status = http.StatusOK
@@ -697,7 +924,18 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		if op.Responses[statusStr].Headers == nil {
 			op.Responses[statusStr].Headers = map[string]*Param{}
 		}
-		if !outBodyFunc {
+		if outBodyReader {
+			if len(op.Responses[statusStr].Content) == 0 {
+				op.Responses[statusStr].Content = map[string]*MediaType{
+					outBodyContentType: {
+						Schema: &Schema{
+							Type:   "string",
+							Format: "binary",
+						},
+					},
+				}
+			}
+		} else if !outBodyFunc {
 			outSchema := SchemaFromField(registry, f, getHint(outputType, f.Name, op.OperationID+"Response"))
 			if op.Responses[statusStr].Content == nil {
 				op.Responses[statusStr].Content = map[string]*MediaType{}
@@ -738,16 +976,28 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			// `.String()` on the value.
 			f.Type = stringType
 		}
+		// We need to generate the schema from the field to get validation info
+		// like min/max and enums. Useful to let the client know possible values.
+		hs := SchemaFromField(registry, f, getHint(outputType, f.Name, op.OperationID+defaultStatusStr+v.Name))
 		op.Responses[defaultStatusStr].Headers[v.Name] = &Header{
-			// We need to generate the schema from the field to get validation info
-			// like min/max and enums. Useful to let the client know possible values.
-			Schema: SchemaFromField(registry, f, getHint(outputType, f.Name, op.OperationID+defaultStatusStr+v.Name)),
+			Deprecated: hs.Deprecated,
+			Schema:     hs,
 		}
 	}
 
 	if len(op.Errors) > 0 && (len(inputParams.Paths) > 0 || inputBodyIndex >= -1) {
 		op.Errors = append(op.Errors, http.StatusUnprocessableEntity)
 	}
+	if len(op.Errors) > 0 && (inputBodyIndex != -1 || rawBodyIndex != -1) {
+		// Document the errors the framework itself may return for request bodies.
+		// Optional bodies can still be unparsable or fail to be read.
+		if op.RequestBody != nil {
+			op.Errors = append(op.Errors, http.StatusBadRequest)
+		}
+		if op.MaxBodyBytes > 0 {
+			op.Errors = append(op.Errors, http.StatusRequestEntityTooLarge)
+		}
+	}
 	if len(op.Errors) > 0 {
 		op.Errors = append(op.Errors, http.StatusInternalServerError)
 	}
@@ -781,13 +1031,39 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		}
 	}
 
+	if outStatusIndex != -1 {
+		if enum := outputType.Field(outStatusIndex).Tag.Get("enum"); enum != "" {
+			// Document each possible status from the status field's `enum` tag,
+			// reusing the headers and body of the default response.
+			def := op.Responses[defaultStatusStr]
+			for _, value := range strings.Split(enum, ",") {
+				code, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil {
+					panic(fmt.Errorf("invalid status enum value %q: %w", value, err))
+				}
+				codeStr := strconv.Itoa(code)
+				if op.Responses[codeStr] != nil {
+					continue
+				}
+				resp := &Response{
+					Description: http.StatusText(code),
+					Headers:     def.Headers,
+				}
+				if code != http.StatusNoContent && code != http.StatusNotModified {
+					resp.Content = def.Content
+				}
+				op.Responses[codeStr] = resp
+			}
+		}
+	}
+
 	if !op.Hidden {
 		oapi.AddOperation(&op)
 	}
 
 	a := api.Adapter()
 
-	a.Handle(&op, api.Middlewares().Handler(func(ctx Context) {
+	a.Handle(&op, api.Middlewares().Handler(op.Middlewares.Handler(func(ctx Context) {
 		var input I
 
 		// Get the validation dependencies from the shared pool.
@@ -803,15 +1079,27 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		errStatus := http.StatusUnprocessableEntity
 
 		var cookies map[string]*http.Cookie
+		var query url.Values
 
 		v := reflect.ValueOf(&input).Elem()
 		inputParams.Every(v, func(f reflect.Value, p *paramFieldInfo) {
 			var value string
+			var exploded []string
 			switch p.Loc {
 			case "path":
 				value = ctx.Param(p.Name)
 			case "query":
-				value = ctx.Query(p.Name)
+				if p.Explode {
+					if query == nil {
+						// Only parse the query once, on-demand.
+						u := ctx.URL()
+						query = u.Query()
+					}
+					exploded = query[p.Name]
+					value = strings.Join(exploded, ",")
+				} else {
+					value = ctx.Query(p.Name)
+				}
 			case "header":
 				value = ctx.Header(p.Name)
 			case "cookie":
@@ -844,7 +1132,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 			if !op.SkipValidateParams && p.Required && value == "" {
 				// Path params are always required.
-				res.Add(pb, "", "required "+p.Loc+" parameter is missing")
+				res.addMessage(pb, "", message(DefaultLanguage, MsgParamRequired, p.Loc), MsgParamRequired, p.Loc)
 				return
 			}
 
@@ -858,7 +1146,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 					v, err := strconv.ParseInt(value, 10, 64)
 					if err != nil {
-						res.Add(pb, value, "invalid integer")
+						res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 						return
 					}
 					f.SetInt(v)
@@ -866,7 +1154,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 					v, err := strconv.ParseUint(value, 10, 64)
 					if err != nil {
-						res.Add(pb, value, "invalid integer")
+						res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 						return
 					}
 					f.SetUint(v)
@@ -874,7 +1162,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				case reflect.Float32, reflect.Float64:
 					v, err := strconv.ParseFloat(value, 64)
 					if err != nil {
-						res.Add(pb, value, "invalid float")
+						res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidFloat], MsgInvalidFloat)
 						return
 					}
 					f.SetFloat(v)
@@ -882,22 +1170,25 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				case reflect.Bool:
 					v, err := strconv.ParseBool(value)
 					if err != nil {
-						res.Add(pb, value, "invalid boolean")
+						res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidBoolean], MsgInvalidBoolean)
 						return
 					}
 					f.SetBool(v)
 					pv = v
 				default:
 					if f.Type().Kind() == reflect.Slice {
+						values := exploded
+						if values == nil {
+							values = strings.Split(value, ",")
+						}
+
 						switch f.Type().Elem().Kind() {
 
 						case reflect.String:
-							values := strings.Split(value, ",")
 							f.Set(reflect.ValueOf(values))
 							pv = values
 
 						case reflect.Int:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int, error) {
 								val, err := strconv.ParseInt(s, 10, strconv.IntSize)
 								if err != nil {
@@ -906,14 +1197,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return int(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Int8:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int8, error) {
 								val, err := strconv.ParseInt(s, 10, 8)
 								if err != nil {
@@ -922,14 +1212,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return int8(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Int16:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int16, error) {
 								val, err := strconv.ParseInt(s, 10, 16)
 								if err != nil {
@@ -938,14 +1227,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return int16(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Int32:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int32, error) {
 								val, err := strconv.ParseInt(s, 10, 32)
 								if err != nil {
@@ -954,14 +1242,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return int32(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Int64:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (int64, error) {
 								val, err := strconv.ParseInt(s, 10, 64)
 								if err != nil {
@@ -970,14 +1257,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return int64(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Uint:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint, error) {
 								val, err := strconv.ParseUint(s, 10, strconv.IntSize)
 								if err != nil {
@@ -986,14 +1272,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return uint(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Uint16:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint16, error) {
 								val, err := strconv.ParseUint(s, 10, 16)
 								if err != nil {
@@ -1002,14 +1287,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return uint16(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Uint32:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint32, error) {
 								val, err := strconv.ParseUint(s, 10, 32)
 								if err != nil {
@@ -1018,14 +1302,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return uint32(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Uint64:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (uint64, error) {
 								val, err := strconv.ParseUint(s, 10, 64)
 								if err != nil {
@@ -1034,14 +1317,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return uint64(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid integer")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidInteger], MsgInvalidInteger)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Float32:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (float32, error) {
 								val, err := strconv.ParseFloat(s, 32)
 								if err != nil {
@@ -1050,14 +1332,13 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return float32(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid floating value")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidFloat], MsgInvalidFloat)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
 							pv = vs
 
 						case reflect.Float64:
-							values := strings.Split(value, ",")
 							vs, err := parseArrElement(values, func(s string) (float64, error) {
 								val, err := strconv.ParseFloat(s, 64)
 								if err != nil {
@@ -1066,7 +1347,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								return float64(val), nil
 							})
 							if err != nil {
-								res.Add(pb, value, "invalid floating value")
+								res.addMessage(pb, value, messages[DefaultLanguage][MsgInvalidFloat], MsgInvalidFloat)
 								return
 							}
 							f.Set(reflect.ValueOf(vs))
@@ -1079,7 +1360,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					if f.Type() == timeType {
 						t, err := time.Parse(p.TimeFormat, value)
 						if err != nil {
-							res.Add(pb, value, "invalid date/time for format "+p.TimeFormat)
+							res.addMessage(pb, value, message(DefaultLanguage, MsgInvalidDateTimeFormat, p.TimeFormat), MsgInvalidDateTimeFormat, p.TimeFormat)
 							return
 						}
 						f.Set(reflect.ValueOf(t))
@@ -1116,11 +1397,29 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 			}
 
 			if rawBodyMultipart {
+				if op.MaxBodyBytes > 0 {
+					// Reject uploads declared too large before parsing anything. Others are
+					// limited by the adapter while parsing.
+					if length, err := strconv.ParseInt(ctx.Header("Content-Length"), 10, 64); err == nil && length > op.MaxBodyBytes {
+						WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", op.MaxBodyBytes), res.Errors...)
+						return
+					}
+				}
+
 				form, err := ctx.GetMultipartForm()
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", op.MaxBodyBytes), res.Errors...)
+					return
+				}
 				if err != nil || form == nil {
+					msg := "cannot read multipart form"
+					if err != nil {
+						msg += ": " + err.Error()
+					}
 					res.Errors = append(res.Errors, &ErrorDetail{
 						Location: "body",
-						Message:  "cannot read multipart form: " + err.Error(),
+						Message:  msg,
 					})
 				} else {
 					f := v.Field(rawBodyIndex)
@@ -1136,11 +1435,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					defer closer.Close()
 				}
 				if op.MaxBodyBytes > 0 {
-					reader = io.LimitReader(reader, op.MaxBodyBytes)
+					// Read one extra byte so a body of exactly the limit is allowed.
+					reader = io.LimitReader(reader, op.MaxBodyBytes+1)
 				}
 				count, err := io.Copy(buf, reader)
 				if op.MaxBodyBytes > 0 {
-					if count == op.MaxBodyBytes {
+					if count > op.MaxBodyBytes {
 						buf.Reset()
 						bufPool.Put(buf)
 						WriteErr(api, ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is too large limit=%d bytes", op.MaxBodyBytes), res.Errors...)
@@ -1173,6 +1473,26 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						WriteErr(api, ctx, http.StatusBadRequest, "request body is required", res.Errors...)
 						return
 					}
+
+					if inputBodyIndex != -1 {
+						// The optional body was omitted entirely, but any defaults should
+						// still be applied so the handler never sees unset defaulted fields.
+						defaults.Every(v, func(item reflect.Value, def any) {
+							if item.IsZero() {
+								item.Set(reflect.Indirect(reflect.ValueOf(def)))
+							}
+						})
+					}
+				} else if fastBody != nil && isFastJSON(ctx.Header("Content-Type")) && fastDecode(fastBody, body, v.Field(inputBodyIndex)) {
+					// The body was decoded and validated in a single pass.
+					defaults.Every(v, func(item reflect.Value, def any) {
+						if item.IsZero() {
+							item.Set(reflect.Indirect(reflect.ValueOf(def)))
+						}
+					})
+
+					buf.Reset()
+					bufPool.Put(buf)
 				} else {
 					parseErrCount := 0
 					if inputBodyIndex != -1 && !op.SkipValidateBody {
@@ -1180,7 +1500,11 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 						// or equivalent, which can be easily validated. Then, convert to the
 						// expected struct type to call the handler.
 						var parsed any
-						if err := api.Unmarshal(ctx.Header("Content-Type"), body, &parsed); err != nil {
+						err := api.Unmarshal(ctx.Header("Content-Type"), body, &parsed)
+						if errors.Is(err, ErrTypedOnly) {
+							err = unmarshalGeneric(api, ctx.Header("Content-Type"), body, v.Field(inputBodyIndex).Type(), &parsed)
+						}
+						if err != nil {
 							errStatus = http.StatusBadRequest
 							if errors.Is(err, ErrUnknownContentType) {
 								errStatus = http.StatusUnsupportedMediaType
@@ -1188,7 +1512,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 							res.Errors = append(res.Errors, &ErrorDetail{
 								Location: "body",
 								Message:  err.Error(),
-								Value:    body,
+								Value:    string(body),
 							})
 							parseErrCount++
 						} else {
@@ -1220,6 +1544,18 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 								})
 							}
 						} else {
+							if parseErrCount == 0 {
+								// Decode discriminated unions into their concrete types.
+								discriminators.EveryPB(pb, v, func(item reflect.Value, d *discriminatorInfo) {
+									if err := d.decode(api, ctx.Header("Content-Type"), item); err != nil {
+										res.Errors = append(res.Errors, &ErrorDetail{
+											Location: pb.String(),
+											Message:  err.Error(),
+										})
+									}
+								})
+							}
+
 							// Set defaults for any fields that were not in the input.
 							defaults.Every(v, func(item reflect.Value, def any) {
 								if item.IsZero() {
@@ -1259,7 +1595,11 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 					break
 				}
 			}
-			WriteErr(api, ctx, errStatus, "validation failed", res.Errors...)
+			lang, msg := localize(ctx.Header("Accept-Language"), MsgValidationFailed, res.Errors)
+			if lang != "" {
+				ctx.SetHeader("Content-Language", lang)
+			}
+			WriteErr(api, ctx, errStatus, msg, res.Errors...)
 			return
 		}
 
@@ -1272,6 +1612,12 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				err = NewError(http.StatusInternalServerError, err.Error())
 			}
 
+			if status == http.StatusNotModified {
+				// A 304 must not include a body.
+				ctx.SetStatus(status)
+				return
+			}
+
 			ct, _ := api.Negotiate(ctx.Header("Accept"))
 			if ctf, ok := err.(ContentTypeFilter); ok {
 				ct = ctf.ContentType(ct)
@@ -1301,7 +1647,10 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 
 		status := op.DefaultStatus
 		if outStatusIndex != -1 {
-			status = int(vo.Field(outStatusIndex).Int())
+			if s := int(vo.Field(outStatusIndex).Int()); s != 0 {
+				// Fall back to the default status when the handler did not set one.
+				status = s
+			}
 		}
 
 		if outBodyIndex != -1 {
@@ -1319,6 +1668,28 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 				return
 			}
 
+			if outBodyReader {
+				// Typed nil pointers, like a nil `*os.File`, are not nil once
+				// stored in the interface, so check the value too.
+				if body == nil || (reflect.ValueOf(body).Kind() == reflect.Pointer && reflect.ValueOf(body).IsNil()) {
+					ctx.SetStatus(status)
+					return
+				}
+				if closer, ok := body.(io.Closer); ok {
+					defer closer.Close()
+				}
+				if ct == "" {
+					ctx.SetHeader("Content-Type", outBodyContentType)
+				}
+				ctx.SetStatus(status)
+				if _, err := io.Copy(ctx.BodyWriter(), body.(io.Reader)); err != nil {
+					// Status code was already sent, so just report the error like a
+					// failure to marshal the response.
+					panic(fmt.Errorf("error writing response body for %s %s %d: %w", ctx.Operation().Method, ctx.Operation().Path, status, err))
+				}
+				return
+			}
+
 			// Only write a content type if one wasn't already written by the
 			// response headers handled above.
 			if ct == "" {
@@ -1338,7 +1709,7 @@ func Register[I, O any](api API, op Operation, handler func(context.Context, *I)
 		} else {
 			ctx.SetStatus(status)
 		}
-	}))
+	})))
 }
 
 // AutoRegister auto-detects operation registration methods and registers them
@@ -1386,7 +1757,7 @@ var reRemoveIDs = regexp.MustCompile(`\{([^}]+)\}`)
 //
 // Examples:
 //
-//   - GET /things` -> `list-things
+//   - GET /things -> list-things
 //   - GET /things/{thing-id} -> get-things-by-thing-id
 //   - PUT /things/{thingId}/favorite -> put-things-by-thing-id-favorite
 //
@@ -1408,7 +1779,7 @@ var GenerateOperationID = func(method, path string, response any) string {
 //
 // Examples:
 //
-//   - GET /things` -> `List things`
+//   - GET /things -> `List things`
 //   - GET /things/{thing-id} -> `Get things by thing id`
 //   - PUT /things/{thingId}/favorite -> `Put things by thing id favorite`
 //
@@ -1425,14 +1796,23 @@ var GenerateSummary = func(method, path string, response any) string {
 	return strings.ToUpper(phrase[:1]) + phrase[1:]
 }
 
-func convenience[I, O any](api API, method, path string, handler func(context.Context, *I) (*O, error)) {
+func convenience[I, O any](api API, method, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
 	var o *O
-	Register(api, Operation{
-		OperationID: GenerateOperationID(method, path, o),
+	id := GenerateOperationID(method, path, o)
+	op := Operation{
+		OperationID: id,
 		Summary:     GenerateSummary(method, path, o),
 		Method:      method,
 		Path:        path,
-	}, handler)
+	}
+	for _, oh := range operationHandlers {
+		oh(&op)
+	}
+	if op.OperationID == id {
+		// Let `Register` generate the ID so collisions get resolved.
+		op.OperationID = ""
+	}
+	Register(api, op, handler)
 }
 
 // Get HTTP operation handler for an API. The handler must be a function that
@@ -1451,9 +1831,16 @@ func convenience[I, O any](api API, method, path string, handler func(context.Co
 //		return resp, nil
 //	})
 //
+// Operation handlers can be passed to customize the generated operation,
+// for example to add tags or a description:
+//
+//	huma.Get(api, "/things", handler, func(o *huma.Operation) {
+//		o.Tags = []string{"Things"}
+//	})
+//
 // This is a convenience wrapper around `huma.Register`.
-func Get[I, O any](api API, path string, handler func(context.Context, *I) (*O, error)) {
-	convenience(api, http.MethodGet, path, handler)
+func Get[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodGet, path, handler, operationHandlers...)
 }
 
 // Post HTTP operation handler for an API. The handler must be a function that
@@ -1473,8 +1860,8 @@ func Get[I, O any](api API, path string, handler func(context.Context, *I) (*O,
 //	})
 //
 // This is a convenience wrapper around `huma.Register`.
-func Post[I, O any](api API, path string, handler func(context.Context, *I) (*O, error)) {
-	convenience(api, http.MethodPost, path, handler)
+func Post[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodPost, path, handler, operationHandlers...)
 }
 
 // Put HTTP operation handler for an API. The handler must be a function that
@@ -1494,8 +1881,8 @@ func Post[I, O any](api API, path string, handler func(context.Context, *I) (*O,
 //	})
 //
 // This is a convenience wrapper around `huma.Register`.
-func Put[I, O any](api API, path string, handler func(context.Context, *I) (*O, error)) {
-	convenience(api, http.MethodPut, path, handler)
+func Put[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodPut, path, handler, operationHandlers...)
 }
 
 // Patch HTTP operation handler for an API. The handler must be a function that
@@ -1515,8 +1902,8 @@ func Put[I, O any](api API, path string, handler func(context.Context, *I) (*O,
 //	})
 //
 // This is a convenience wrapper around `huma.Register`.
-func Patch[I, O any](api API, path string, handler func(context.Context, *I) (*O, error)) {
-	convenience(api, http.MethodPatch, path, handler)
+func Patch[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodPatch, path, handler, operationHandlers...)
 }
 
 // Delete HTTP operation handler for an API. The handler must be a function that
@@ -1534,6 +1921,6 @@ func Patch[I, O any](api API, path string, handler func(context.Context, *I) (*O
 //	})
 //
 // This is a convenience wrapper around `huma.Register`.
-func Delete[I, O any](api API, path string, handler func(context.Context, *I) (*O, error)) {
-	convenience(api, http.MethodDelete, path, handler)
+func Delete[I, O any](api API, path string, handler func(context.Context, *I) (*O, error), operationHandlers ...func(o *Operation)) {
+	convenience(api, http.MethodDelete, path, handler, operationHandlers...)
 }
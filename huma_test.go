@@ -1,8 +1,10 @@
 package huma_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"io"
 	"mime/multipart"
@@ -10,8 +12,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -120,6 +124,43 @@ func TestFeatures(t *testing.T) {
 				"Cookie": "foo=bar",
 			},
 		},
+		{
+			Name: "middleware-operation",
+			Register: func(t *testing.T, api huma.API) {
+				api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+					// API middleware runs first.
+					ctx.AppendHeader("X-Order", "api")
+					next(ctx)
+				})
+				huma.Register(api, huma.Operation{
+					OperationID: "middleware-operation",
+					Method:      http.MethodGet,
+					Path:        "/middleware",
+					Tags:        []string{"admin"},
+					Middlewares: huma.Middlewares{
+						func(ctx huma.Context, next func(huma.Context)) {
+							ctx.AppendHeader("X-Order", "operation")
+							assert.Equal(t, "middleware-operation", ctx.Operation().OperationID)
+							if ctx.Operation().Tags[0] == "admin" && ctx.Header("Authorization") == "" {
+								huma.WriteErr(api, ctx, http.StatusUnauthorized, "missing credentials")
+								return
+							}
+							next(ctx)
+						},
+					},
+				}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+					// This should never be called because of the middleware.
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/middleware",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, resp.Code)
+				assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+				assert.Equal(t, []string{"api", "operation"}, resp.Header().Values("X-Order"))
+			},
+		},
 		{
 			Name: "params",
 			Register: func(t *testing.T, api huma.API) {
@@ -199,6 +240,119 @@ func TestFeatures(t *testing.T) {
 				"cookie": "one=foo; two=123; three=bar",
 			},
 		},
+		{
+			Name: "params-explode",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/explode",
+				}, func(ctx context.Context, input *struct {
+					Tags    []string `query:"tag" explode:"true"`
+					IDs     []int    `query:"id" explode:"true" minItems:"2"`
+					Default []string `query:"def" explode:"true" default:"a,b"`
+					Comma   []string `query:"comma"`
+				}) (*struct{}, error) {
+					assert.Equal(t, []string{"a,b", "c"}, input.Tags)
+					assert.Equal(t, []int{1, 2}, input.IDs)
+					assert.Equal(t, []string{"a", "b"}, input.Default)
+					assert.Equal(t, []string{"x", "y"}, input.Comma)
+					return nil, nil
+				})
+
+				params := api.OpenAPI().Paths["/explode"].Get.Parameters
+				assert.True(t, *params[0].Explode)
+				assert.False(t, *params[3].Explode)
+			},
+			Method: http.MethodGet,
+			URL:    "/explode?tag=a%2Cb&tag=c&id=1&id=2&comma=x,y",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
+		{
+			Name: "params-deprecated",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/deprecated",
+				}, func(ctx context.Context, input *struct {
+					Old string `query:"old" deprecated:"true"`
+					New string `query:"new"`
+				}) (*struct {
+					OldHeader string `header:"X-Old" deprecated:"true"`
+				}, error) {
+					return &struct {
+						OldHeader string `header:"X-Old" deprecated:"true"`
+					}{OldHeader: input.Old}, nil
+				})
+
+				op := api.OpenAPI().Paths["/deprecated"].Get
+				assert.True(t, op.Parameters[0].Deprecated)
+				assert.True(t, op.Parameters[0].Schema.Deprecated)
+				assert.False(t, op.Parameters[1].Deprecated)
+				assert.True(t, op.Responses["204"].Headers["X-Old"].Deprecated)
+			},
+			Method: http.MethodGet,
+			URL:    "/deprecated?old=foo",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+				assert.Equal(t, "foo", resp.Header().Get("X-Old"))
+			},
+		},
+		{
+			Name: "request-body-errors-documented",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body-errors",
+					Errors: []int{http.StatusNotFound},
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name string `json:"name"`
+					}
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+
+				responses := api.OpenAPI().Paths["/body-errors"].Put.Responses
+				for _, code := range []string{"400", "404", "413", "422", "500"} {
+					assert.Contains(t, responses, code)
+					assert.NotNil(t, responses[code].Content["application/problem+json"].Schema, code)
+				}
+			},
+			Method: http.MethodPut,
+			URL:    "/body-errors",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+				assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+			},
+		},
+		{
+			Name: "request-body-optional-errors-documented",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body-errors",
+					Errors: []int{http.StatusNotFound},
+				}, func(ctx context.Context, input *struct {
+					Body *struct {
+						Name string `json:"name"`
+					}
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+
+				op := api.OpenAPI().Paths["/body-errors"].Put
+				assert.False(t, op.RequestBody.Required)
+				assert.Contains(t, op.Responses, "400")
+			},
+			Method: http.MethodPut,
+			URL:    "/body-errors",
+			Body:   "{",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, resp.Code)
+			},
+		},
 		{
 			Name: "params-error",
 			Register: func(t *testing.T, api huma.API) {
@@ -373,6 +527,29 @@ func TestFeatures(t *testing.T) {
 			URL:    "/body",
 			Body:   `{"items": [{"id": 1}]}`,
 		},
+		{
+			Name: "request-body-defaults-empty",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name  string `json:"name,omitempty" default:"Huma"`
+						Count int    `json:"count,omitempty" default:"5"`
+					} `required:"false"`
+				}) (*struct{}, error) {
+					assert.Equal(t, "Huma", input.Body.Name)
+					assert.Equal(t, 5, input.Body.Count)
+					return nil, nil
+				})
+			},
+			Method: http.MethodPut,
+			URL:    "/body",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
 		{
 			Name: "request-body-required",
 			Register: func(t *testing.T, api huma.API) {
@@ -413,6 +590,51 @@ func TestFeatures(t *testing.T) {
 				assert.Equal(t, http.StatusBadRequest, resp.Code)
 			},
 		},
+		{
+			Name: "request-body-hidden-field",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/body",
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name     string `json:"name"`
+						Internal string `json:"internal,omitempty" hidden:"true"`
+					}
+				}) (*struct{}, error) {
+					assert.Equal(t, "secret", input.Body.Internal)
+					return nil, nil
+				})
+
+				b, _ := json.Marshal(api.OpenAPI())
+				assert.NotContains(t, string(b), "internal")
+			},
+			Method: http.MethodPut,
+			URL:    "/body",
+			Body:   `{"name": "foo", "internal": "secret"}`,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+			},
+		},
+		{
+			Name: "operation-hidden",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/hidden",
+					Hidden: true,
+				}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+					return nil, nil
+				})
+
+				assert.Nil(t, api.OpenAPI().Paths["/hidden"])
+			},
+			Method: http.MethodGet,
+			URL:    "/hidden",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
 		{
 			Name: "request-body-too-large",
 			Register: func(t *testing.T, api huma.API) {
@@ -435,6 +657,28 @@ func TestFeatures(t *testing.T) {
 				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
 			},
 		},
+		{
+			Name: "request-body-exact-limit",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodPut,
+					Path:         "/body",
+					MaxBodyBytes: 15,
+				}, func(ctx context.Context, input *struct {
+					Body struct {
+						Name string `json:"name"`
+					}
+				}) (*struct{}, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodPut,
+			URL:    "/body",
+			Body:   `{"name":"abcd"}`,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, resp.Code)
+			},
+		},
 		{
 			Name: "request-body-bad-json",
 			Register: func(t *testing.T, api huma.API) {
@@ -597,6 +841,65 @@ Content-Type: text/plain
 Content of example2.txt.
 --AnotherBoundary--`,
 		},
+		{
+			Name: "request-body-multipart-too-large",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodPost,
+					Path:         "/upload",
+					MaxBodyBytes: 10,
+				}, func(ctx context.Context, input *struct {
+					RawBody multipart.Form
+				}) (*struct{}, error) {
+					t.Fatal("handler should not be called")
+					return nil, nil
+				})
+			},
+			Method: http.MethodPost,
+			URL:    "/upload",
+			Headers: map[string]string{
+				"Content-Type":   "multipart/form-data; boundary=SimpleBoundary",
+				"Content-Length": "103",
+			},
+			Body: `--SimpleBoundary
+Content-Disposition: form-data; name="file"; filename="test.txt"
+Content-Type: text/plain
+
+Hello, World!
+--SimpleBoundary--`,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+			},
+		},
+		{
+			Name: "request-body-multipart-too-large-undeclared",
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method:       http.MethodPost,
+					Path:         "/upload",
+					MaxBodyBytes: 10,
+				}, func(ctx context.Context, input *struct {
+					RawBody multipart.Form
+				}) (*struct{}, error) {
+					t.Fatal("handler should not be called")
+					return nil, nil
+				})
+			},
+			Method: http.MethodPost,
+			URL:    "/upload",
+			Headers: map[string]string{
+				"Content-Type": "multipart/form-data; boundary=SimpleBoundary",
+			},
+			Body: `--SimpleBoundary
+Content-Disposition: form-data; name="file"; filename="test.txt"
+Content-Type: text/plain
+
+Hello, World!
+--SimpleBoundary--`,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+			},
+		},
 		{
 			Name: "request-body-multipart-wrong-content-type",
 			Register: func(t *testing.T, api huma.API) {
@@ -886,6 +1189,86 @@ Content of example2.txt.
 				assert.Equal(t, `hello`, resp.Body.String())
 			},
 		},
+		{
+			Name: "response-reader",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Body io.Reader `contentType:"text/csv"`
+				}
+
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/reader",
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					return &Resp{Body: strings.NewReader("a,b\n1,2\n")}, nil
+				})
+
+				// Ensure the OpenAPI spec is correct.
+				content := api.OpenAPI().Paths["/reader"].Get.Responses["200"].Content
+				assert.Len(t, content, 1)
+				assert.Equal(t, "binary", content["text/csv"].Schema.Format)
+			},
+			Method: http.MethodGet,
+			URL:    "/reader",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, "text/csv", resp.Header().Get("Content-Type"))
+				assert.Equal(t, "a,b\n1,2\n", resp.Body.String())
+			},
+		},
+		{
+			Name: "response-reader-typed-nil",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Body io.Reader
+				}
+
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/reader",
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					var b *bytes.Buffer
+					return &Resp{Body: b}, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/reader",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				// Written like a nil body instead of copying from the nil pointer.
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Empty(t, resp.Header().Get("Content-Type"))
+				assert.Empty(t, resp.Body.String())
+			},
+		},
+		{
+			Name: "response-reader-error",
+			Register: func(t *testing.T, api huma.API) {
+				api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+					defer func() {
+						err := recover()
+						require.NotNil(t, err)
+						assert.ErrorIs(t, err.(error), io.ErrUnexpectedEOF)
+					}()
+					next(ctx)
+				})
+
+				type Resp struct {
+					Body io.Reader
+				}
+
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/reader",
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					return &Resp{Body: iotest.ErrReader(io.ErrUnexpectedEOF)}, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/reader",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
 		{
 			Name: "response-transform-error",
 			Transformers: []huma.Transformer{
@@ -924,6 +1307,71 @@ Content of example2.txt.
 				assert.Equal(t, `error transforming response`, resp.Body.String())
 			},
 		},
+		{
+			Name: "response-transformer-chain",
+			Transformers: []huma.Transformer{
+				func(ctx huma.Context, status string, v any) (any, error) {
+					if status != "200" {
+						return v, nil
+					}
+					return map[string]any{"data": v, "operation": ctx.Operation().OperationID}, nil
+				},
+				func(ctx huma.Context, status string, v any) (any, error) {
+					ctx.SetHeader("X-Transformed", status)
+					if m, ok := v.(map[string]any); ok {
+						m["meta"] = "second"
+					}
+					return v, nil
+				},
+			},
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					OperationID: "get-envelope",
+					Method:      http.MethodGet,
+					Path:        "/envelope/{id}",
+				}, func(ctx context.Context, input *struct {
+					ID string `path:"id"`
+				}) (*struct{ Body []string }, error) {
+					if input.ID == "missing" {
+						return nil, huma.Error404NotFound("not found")
+					}
+					return &struct{ Body []string }{[]string{input.ID}}, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/envelope/abc",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, "200", resp.Result().Header.Get("X-Transformed"))
+				assert.JSONEq(t, `{"data": ["abc"], "operation": "get-envelope", "meta": "second"}`, resp.Body.String())
+			},
+		},
+		{
+			Name: "response-transformer-chain-error",
+			Transformers: []huma.Transformer{
+				func(ctx huma.Context, status string, v any) (any, error) {
+					ctx.SetHeader("X-Transformed", status)
+					return v, nil
+				},
+			},
+			Register: func(t *testing.T, api huma.API) {
+				huma.Register(api, huma.Operation{
+					Method: http.MethodGet,
+					Path:   "/envelope",
+				}, func(ctx context.Context, input *struct {
+					Count int `query:"count"`
+				}) (*struct{ Body string }, error) {
+					return nil, nil
+				})
+			},
+			Method: http.MethodGet,
+			URL:    "/envelope?count=abc",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				// Headers set by transformers must be sent with the status.
+				assert.Equal(t, "422", resp.Result().Header.Get("X-Transformed"))
+			},
+		},
 		{
 			Name: "response-marshal-error",
 			Register: func(t *testing.T, api huma.API) {
@@ -971,6 +1419,39 @@ Content of example2.txt.
 				assert.Equal(t, 256, resp.Code)
 			},
 		},
+		{
+			Name: "dynamic-status-enum",
+			Register: func(t *testing.T, api huma.API) {
+				type Resp struct {
+					Status   int    `enum:"200,201,304"`
+					Location string `header:"Location"`
+					Body     struct {
+						ID string `json:"id"`
+					}
+				}
+
+				huma.Register(api, huma.Operation{
+					Method: http.MethodPut,
+					Path:   "/status-enum",
+				}, func(ctx context.Context, input *struct{}) (*Resp, error) {
+					// Status is left unset, so the default status is used.
+					return &Resp{}, nil
+				})
+
+				responses := api.OpenAPI().Paths["/status-enum"].Put.Responses
+				assert.Equal(t, "Created", responses["201"].Description)
+				assert.Contains(t, responses["201"].Headers, "Location")
+				assert.NotNil(t, responses["201"].Content["application/json"].Schema)
+				assert.Contains(t, responses["304"].Headers, "Location")
+				assert.Nil(t, responses["304"].Content)
+				assert.Contains(t, responses, "default")
+			},
+			Method: http.MethodPut,
+			URL:    "/status-enum",
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+			},
+		},
 		{
 			// Simulate a request with a body that came from another call, which
 			// includes the `$schema` field. It should be allowed to be passed
@@ -1126,6 +1607,7 @@ func TestOpenAPI(t *testing.T) {
 	})
 
 	for _, url := range []string{
+		"/openapi",
 		"/openapi.json",
 		"/openapi-3.0.json",
 		"/openapi.yaml",
@@ -1139,6 +1621,22 @@ func TestOpenAPI(t *testing.T) {
 
 		assert.Equal(t, 200, w.Code, w.Body.String())
 	}
+
+	for accept, expected := range map[string]string{
+		"":                                  "application/vnd.oai.openapi+json",
+		"application/json":                  "application/vnd.oai.openapi+json",
+		"application/yaml":                  "application/vnd.oai.openapi+yaml",
+		"text/html, application/yaml;q=0.9": "application/vnd.oai.openapi+yaml",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/openapi", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, 200, w.Code, w.Body.String())
+		assert.Equal(t, expected, w.Header().Get("Content-Type"), accept)
+		assert.Contains(t, w.Body.String(), "Features Test API")
+	}
 }
 
 type IntNot3 int
@@ -1332,6 +1830,63 @@ func TestNestedResolverWithPath(t *testing.T) {
 	assert.Contains(t, w.Body.String(), `"location":"body.field1.foo[0].field2"`)
 }
 
+type SharedResolverAddress struct {
+	Zip  string `json:"zip"`
+	Kind string `json:"kind,omitempty" default:"home"`
+}
+
+func (a *SharedResolverAddress) Resolve(ctx huma.Context, prefix *huma.PathBuffer) []error {
+	if a.Zip == "bad" {
+		return []error{&huma.ErrorDetail{
+			Location: prefix.With("zip"),
+			Message:  "invalid zip",
+			Value:    a.Zip,
+		}}
+	}
+	return nil
+}
+
+type SharedResolverItem struct {
+	Address SharedResolverAddress `json:"address"`
+}
+
+func TestSharedTypeResolversAndDefaults(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/test",
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Billing  SharedResolverItem `json:"billing"`
+			Shipping SharedResolverItem `json:"shipping"`
+		}
+	}) (*struct {
+		Body []string
+	}, error) {
+		return &struct{ Body []string }{[]string{
+			input.Body.Billing.Address.Kind,
+			input.Body.Shipping.Address.Kind,
+		}}, nil
+	})
+
+	// Resolvers & defaults run for every field using the same type, not just
+	// the first one.
+	resp := api.Put("/test", map[string]any{
+		"billing":  map[string]any{"address": map[string]any{"zip": "bad"}},
+		"shipping": map[string]any{"address": map[string]any{"zip": "bad"}},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"location":"body.billing.address.zip"`)
+	assert.Contains(t, resp.Body.String(), `"location":"body.shipping.address.zip"`)
+
+	resp = api.Put("/test", map[string]any{
+		"billing":  map[string]any{"address": map[string]any{"zip": "12345"}},
+		"shipping": map[string]any{"address": map[string]any{"zip": "12345"}},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["home", "home"]`, resp.Body.String())
+}
+
 type ResolverCustomStatus struct{}
 
 func (r *ResolverCustomStatus) Resolve(ctx huma.Context) []error {
@@ -1404,6 +1959,83 @@ func TestParamPointerPanics(t *testing.T) {
 	})
 }
 
+func TestDuplicateOperationIDPanics(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+
+	huma.Register(app, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, handler)
+
+	assert.PanicsWithValue(t, `duplicate operation ID "get-thing" used by GET /things/{id} and GET /other`, func() {
+		huma.Register(app, huma.Operation{
+			OperationID: "get-thing",
+			Method:      http.MethodGet,
+			Path:        "/other",
+		}, handler)
+	})
+}
+
+func TestGeneratedOperationID(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register(app, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/things",
+	}, func(ctx context.Context, input *struct{}) (*struct{ Body []string }, error) {
+		return nil, nil
+	})
+
+	huma.Register(app, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/things/{thing-id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"thing-id"`
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	assert.Equal(t, "list-things", app.OpenAPI().Paths["/things"].Get.OperationID)
+	assert.Equal(t, "put-things-by-thing-id", app.OpenAPI().Paths["/things/{thing-id}"].Put.OperationID)
+}
+
+func TestGeneratedOperationIDCollision(t *testing.T) {
+	_, app := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	handler := func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	}
+
+	huma.Get(app, "/things-x", handler)
+	huma.Get(app, "/things/x", handler)
+	huma.Register(app, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/things/x/",
+	}, handler)
+
+	// Re-registering an operation keeps its ID.
+	huma.Get(app, "/things/x", handler)
+
+	assert.Equal(t, "get-things-x", app.OpenAPI().Paths["/things-x"].Get.OperationID)
+	assert.Equal(t, "get-things-x-2", app.OpenAPI().Paths["/things/x"].Get.OperationID)
+	assert.Equal(t, "get-things-x-3", app.OpenAPI().Paths["/things/x/"].Get.OperationID)
+
+	// Explicitly set IDs are never changed.
+	assert.PanicsWithValue(t, `duplicate operation ID "get-things-x" used by GET /things-x and GET /other`, func() {
+		huma.Get(app, "/other", handler, func(o *huma.Operation) {
+			o.OperationID = "get-things-x"
+		})
+	})
+}
+
 func TestPointerDefaultPanics(t *testing.T) {
 	// For now, we don't support these, so we panic rather than have subtle
 	// bugs that are hard to track down.
@@ -1460,6 +2092,17 @@ func TestConvenienceMethods(t *testing.T) {
 		return nil, nil
 	})
 	assert.Equal(t, "delete-things-by-thing-id", api.OpenAPI().Paths[path].Delete.OperationID)
+
+	// Operations can be customized after the defaults are set.
+	path = "/custom/{thing-id}"
+	huma.Delete(api, path, func(ctx context.Context, input *Input) (*struct{}, error) {
+		return nil, nil
+	}, func(o *huma.Operation) {
+		o.OperationID = "custom-delete"
+		o.Tags = []string{"Things"}
+	})
+	assert.Equal(t, "custom-delete", api.OpenAPI().Paths[path].Delete.OperationID)
+	assert.Equal(t, []string{"Things"}, api.OpenAPI().Paths[path].Delete.Tags)
 }
 
 // func BenchmarkSecondDecode(b *testing.B) {
@@ -1595,3 +2238,294 @@ func BenchmarkHandlerFunc(b *testing.B) {
 		}
 	})
 }
+
+func TestConfigBodyLimits(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.MaxBodyBytes = 4
+	config.BodyReadTimeout = 2 * time.Second
+	_, api := humatest.New(t, config)
+	huma.Put(api, "/body", func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	op := api.OpenAPI().Paths["/body"].Put
+	assert.Equal(t, int64(4), op.MaxBodyBytes)
+	assert.Equal(t, 2*time.Second, op.BodyReadTimeout)
+
+	resp := api.Put("/body", map[string]any{"name": "too long"})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+	// Without config the built-in defaults are used.
+	_, api = humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Put(api, "/body", func(ctx context.Context, input *struct {
+		Body struct{}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	op = api.OpenAPI().Paths["/body"].Put
+	assert.Equal(t, int64(1024*1024), op.MaxBodyBytes)
+	assert.Equal(t, 5*time.Second, op.BodyReadTimeout)
+}
+
+func TestConfigSchemaOptions(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.AdditionalProperties = true
+	config.SynthesizeExamples = true
+	_, api := humatest.New(t, config)
+	huma.Put(api, "/body", func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name" minLength:"3"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	s := api.OpenAPI().Paths["/body"].Put.RequestBody.Content["application/json"].Schema
+	if s.Ref != "" {
+		s = api.OpenAPI().Components.Schemas.SchemaFromRef(s.Ref)
+	}
+	assert.Equal(t, true, s.AdditionalProperties)
+	assert.NotEmpty(t, s.Properties["name"].Examples)
+
+	resp := api.Put("/body", map[string]any{"name": "abc", "extra": true})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+}
+
+func TestConfigurableRegistryOptionsSurviveRegister(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	registry := api.OpenAPI().Components.Schemas
+	registry.(huma.ConfigurableRegistry).SchemaOptions().AdditionalProperties = true
+
+	huma.Put(api, "/body", func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	assert.True(t, registry.(huma.ConfigurableRegistry).SchemaOptions().AdditionalProperties)
+
+	resp := api.Put("/body", map[string]any{"name": "abc", "extra": true})
+	assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+}
+
+func TestConfigSchemaOptionsBeforeRegister(t *testing.T) {
+	type Early struct {
+		Name string `json:"name"`
+	}
+
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.AdditionalProperties = true
+	_, api := humatest.New(t, config)
+
+	s := huma.SchemaFromType(api.OpenAPI().Components.Schemas, reflect.TypeOf(Early{}))
+	if s.Ref != "" {
+		s = api.OpenAPI().Components.Schemas.SchemaFromRef(s.Ref)
+	}
+	assert.Equal(t, true, s.AdditionalProperties)
+}
+
+func TestMultipartChunkedTooLarge(t *testing.T) {
+	router, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Register(api, huma.Operation{
+		Method:       http.MethodPost,
+		Path:         "/upload",
+		MaxBodyBytes: 1024,
+	}, func(ctx context.Context, input *struct {
+		RawBody multipart.Form
+	}) (*struct{}, error) {
+		t.Error("handler should not be called")
+		return nil, nil
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Stream the body so it is sent with chunked encoding and no length.
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, _ := mw.CreateFormFile("file", "big.txt")
+		io.WriteString(part, strings.Repeat("a", 64*1024))
+		mw.Close()
+		pw.Close()
+	}()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/upload", pr)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestOperationTypes(t *testing.T) {
+	type Input struct {
+		ID string `path:"id"`
+	}
+	type Output struct {
+		Body string
+	}
+
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	huma.Get(api, "/things/{id}", func(ctx context.Context, input *Input) (*Output, error) {
+		return nil, nil
+	})
+
+	op := api.OpenAPI().Paths["/things/{id}"].Get
+	assert.Equal(t, "Input", op.InputType.Name())
+	assert.Equal(t, "Output", op.OutputType.Name())
+}
+
+type XMLThing struct {
+	XMLName xml.Name `json:"-" xml:"thing"`
+	ID      string   `json:"id" xml:"id,attr"`
+	Count   int      `json:"count" minimum:"1"`
+	Tags    []string `json:"tags,omitempty" xml:"tags>tag"`
+}
+
+func TestXML(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Formats["application/xml"] = huma.DefaultXMLFormat
+	config.Formats["xml"] = huma.DefaultXMLFormat
+	_, api := humatest.New(t, config)
+
+	type ThingIO struct {
+		Body XMLThing
+	}
+
+	huma.Put(api, "/things", func(ctx context.Context, input *ThingIO) (*ThingIO, error) {
+		return input, nil
+	})
+
+	resp := api.Put("/things",
+		"Content-Type: application/xml",
+		"Accept: application/xml",
+		strings.NewReader(`<thing id="abc"><Count>2</Count><tags><tag>a</tag><tag>b</tag></tags></thing>`))
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Equal(t, "application/xml", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `<thing id="abc"><Count>2</Count><tags><tag>a</tag><tag>b</tag></tags></thing>`, resp.Body.String())
+
+	// Validation applies to XML bodies too.
+	resp = api.Put("/things",
+		"Content-Type: application/xml",
+		"Accept: application/xml",
+		strings.NewReader(`<thing id="abc"><Count>0</Count></thing>`))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), "<ErrorModel>")
+	assert.Contains(t, resp.Body.String(), "<errors><error><message>expected number &gt;= 1</message><location>body.count</location></error></errors>")
+
+	// The schema documents the XML representation.
+	schema, _ := json.Marshal(api.OpenAPI().Components.Schemas.Map()["XMLThing"])
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"xml": {"name": "thing"},
+		"properties": {
+			"$schema": {"type": "string", "format": "uri", "readOnly": true, "description": "A URL to the JSON Schema for this object.", "examples": ["https://example.com/schemas/XMLThing.json"]},
+			"id": {"type": "string", "xml": {"attribute": true}},
+			"count": {"type": "integer", "format": "int64", "minimum": 1},
+			"tags": {"type": "array", "items": {"type": "string", "xml": {"name": "tag"}}, "xml": {"wrapped": true}}
+		},
+		"required": ["id", "count"]
+	}`, string(schema))
+}
+
+type XMLChild struct {
+	Value int `json:"value" xml:"value"`
+}
+
+type XMLOptional struct {
+	XMLName  xml.Name   `json:"-" xml:"optional"`
+	Name     string     `json:"name" xml:"name"`
+	Nick     string     `json:"nick" xml:"nick" required:"false" minLength:"2"`
+	Size     int        `json:"size" xml:"size,attr" required:"false" minimum:"1"`
+	Child    *XMLChild  `json:"child,omitempty" xml:"child"`
+	Children []XMLChild `json:"children,omitempty" xml:"children>child"`
+}
+
+func TestXMLPresence(t *testing.T) {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Formats["application/xml"] = huma.DefaultXMLFormat
+	config.Formats["xml"] = huma.DefaultXMLFormat
+	_, api := humatest.New(t, config)
+
+	huma.Put(api, "/optional", func(ctx context.Context, input *struct{ Body XMLOptional }) (*struct{}, error) {
+		return nil, nil
+	})
+
+	for _, item := range []struct {
+		body  string
+		error string
+	}{
+		// Optional fields which are missing are not validated.
+		{`<optional><name>x</name></optional>`, ""},
+		{`<optional size="2"><name>x</name><nick>xy</nick><child><value>1</value></child></optional>`, ""},
+		{`<optional><name>x</name><children><child><value>1</value></child></children></optional>`, ""},
+
+		// Required fields must be present, even when their zero value is valid.
+		{`<optional></optional>`, "expected required property name to be present"},
+		{`<optional><name>x</name><child></child></optional>`, "expected required property value to be present"},
+		{`<optional><name>x</name><children><child><value>1</value></child><child/></children></optional>`, "body.children[1]"},
+
+		// Present optional fields are validated, including zero values.
+		{`<optional><name>x</name><nick></nick></optional>`, `"location":"body.nick"`},
+		{`<optional size="0"><name>x</name></optional>`, `"location":"body.size"`},
+	} {
+		t.Run(item.body, func(t *testing.T) {
+			resp := api.Put("/optional", "Content-Type: application/xml", strings.NewReader(item.body))
+			if item.error == "" {
+				assert.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+			} else {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Contains(t, resp.Body.String(), item.error)
+			}
+		})
+	}
+}
+
+func TestDiscriminatorDecode(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	api.OpenAPI().Components.Schemas.RegisterTypeAlias(reflect.TypeOf((*DiscriminatorPet)(nil)).Elem(), reflect.TypeOf(DiscriminatorPetSchema{}))
+
+	var pets []DiscriminatorPet
+	huma.Put(api, "/pets", func(ctx context.Context, input *struct {
+		Body struct {
+			Pet    DiscriminatorPet `json:"pet" discriminator:"kind"`
+			Others []struct {
+				Pet DiscriminatorPet `json:"pet" discriminator:"kind"`
+			} `json:"others,omitempty"`
+		}
+	}) (*struct{}, error) {
+		pets = append(pets[:0], input.Body.Pet)
+		for _, other := range input.Body.Others {
+			pets = append(pets, other.Pet)
+		}
+		return nil, nil
+	})
+
+	resp := api.Put("/pets", map[string]any{
+		"pet":    map[string]any{"kind": "cat", "meow": true},
+		"others": []any{map[string]any{"pet": map[string]any{"kind": "dog", "bark": true}}},
+	})
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	assert.Equal(t, []DiscriminatorPet{
+		DiscriminatorCat{Kind: "cat", Meow: true},
+		DiscriminatorDog{Kind: "dog", Bark: true},
+	}, pets)
+
+	// Other formats are decoded the same way.
+	buf := &bytes.Buffer{}
+	require.NoError(t, huma.DefaultCBORFormat.Marshal(buf, map[string]any{
+		"pet": map[string]any{"kind": "dog", "bark": true},
+	}))
+	resp = api.Put("/pets", "Content-Type: application/cbor", buf)
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	assert.Equal(t, []DiscriminatorPet{DiscriminatorDog{Kind: "dog", Bark: true}}, pets)
+}
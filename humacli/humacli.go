@@ -115,6 +115,9 @@ func (c *cli[Options]) Run() {
 					i, _ = flags.GetInt64(opt.name)
 				}
 				fv = reflect.ValueOf(i).Convert(deref(opt.typ))
+			case reflect.Float32, reflect.Float64:
+				f, _ := flags.GetFloat64(opt.name)
+				fv = reflect.ValueOf(f).Convert(deref(opt.typ))
 			case reflect.Bool:
 				b, _ := flags.GetBool(opt.name)
 				fv = reflect.ValueOf(b)
@@ -184,7 +187,10 @@ func (c *cli[O]) setupOptions(t reflect.Type, path []int) {
 			name = casing.Kebab(field.Name)
 		}
 
-		envName := "SERVICE_" + casing.Snake(name, strings.ToUpper)
+		envName := field.Tag.Get("env")
+		if envName == "" {
+			envName = "SERVICE_" + casing.Snake(name, strings.ToUpper)
+		}
 		defaultValue := field.Tag.Get("default")
 		if v, ok := os.LookupEnv(envName); ok {
 			// Env vars will override the default value, which is used to document
@@ -215,6 +221,15 @@ func (c *cli[O]) setupOptions(t reflect.Type, path []int) {
 			} else {
 				flags.Int64P(name, field.Tag.Get("short"), def, field.Tag.Get("doc"))
 			}
+		case reflect.Float32, reflect.Float64:
+			var def float64
+			if defaultValue != "" {
+				def, err = strconv.ParseFloat(defaultValue, 64)
+				if err != nil {
+					panic(err)
+				}
+			}
+			flags.Float64P(name, field.Tag.Get("short"), def, field.Tag.Get("doc"))
 		case reflect.Bool:
 			var def bool
 			if defaultValue != "" {
@@ -119,6 +119,31 @@ func TestCLIEnv(t *testing.T) {
 	cli.Run()
 }
 
+func TestCLIEnvName(t *testing.T) {
+	type Options struct {
+		Port  int     `env:"PORT" default:"8888"`
+		Ratio float64 `default:"0.5"`
+	}
+
+	os.Setenv("PORT", "8001")
+	os.Setenv("SERVICE_RATIO", "0.25")
+	defer func() {
+		os.Unsetenv("PORT")
+		os.Unsetenv("SERVICE_RATIO")
+	}()
+
+	cli := humacli.New(func(hooks humacli.Hooks, options *Options) {
+		assert.Equal(t, 8001, options.Port)
+		assert.InDelta(t, 0.25, options.Ratio, 0.0001)
+		hooks.OnStart(func() {
+			// Do nothing
+		})
+	})
+
+	cli.Root().SetArgs([]string{})
+	cli.Run()
+}
+
 func TestCLIAdvanced(t *testing.T) {
 	type DebugOption struct {
 		Debug bool `doc:"Enable debug mode." default:"false"`
@@ -243,6 +268,10 @@ func TestCLIBadDefaults(t *testing.T) {
 		Debug int `default:"notanint"`
 	}
 
+	type OptionsFloat struct {
+		Ratio float64 `default:"notafloat"`
+	}
+
 	assert.Panics(t, func() {
 		humacli.New(func(hooks humacli.Hooks, options *OptionsBool) {})
 	})
@@ -250,4 +279,8 @@ func TestCLIBadDefaults(t *testing.T) {
 	assert.Panics(t, func() {
 		humacli.New(func(hooks humacli.Hooks, options *OptionsInt) {})
 	})
+
+	assert.Panics(t, func() {
+		humacli.New(func(hooks humacli.Hooks, options *OptionsFloat) {})
+	})
 }
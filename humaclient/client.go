@@ -0,0 +1,383 @@
+// Package humaclient provides a typed HTTP client for Huma APIs which reuses
+// the exact input & output structs registered on the server, along with a
+// code generator which emits a client with one method per operation. Since
+// the client and server share types, they cannot drift apart.
+//
+// Requests are built from the same `path`, `query`, `header`, and `cookie`
+// field tags and `Body` field the server uses to parse them, and responses
+// are parsed into the output struct's `Status`, `header`, and `Body` fields.
+package humaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	cookieType = reflect.TypeOf(http.Cookie{})
+	bytesType  = reflect.TypeOf([]byte{})
+)
+
+// Client sends requests to a Huma API.
+type Client struct {
+	// BaseURL is the API's base URL, e.g. `https://api.example.com/v1`.
+	BaseURL string
+
+	// HTTPClient sends the requests. Defaults to `http.DefaultClient`.
+	HTTPClient *http.Client
+
+	// Header is added to every request, e.g. for authorization.
+	Header http.Header
+}
+
+// New creates a new client for the API at the given base URL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Do sends a request for an operation and returns the parsed response. The
+// request is built from the input struct's parameter fields & body, and the
+// response is parsed into the output struct. Responses with a status code of
+// 400 or above are returned as a `*huma.ErrorModel` error.
+//
+//	resp, err := humaclient.Do[GetThingInput, GetThingOutput](ctx, client,
+//		http.MethodGet, "/things/{thing-id}", &GetThingInput{ID: "abc123"})
+func Do[I, O any](ctx context.Context, c *Client, method, path string, input *I) (*O, error) {
+	req, err := NewRequest(ctx, c, method, path, input)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var output O
+	if err := ParseResponse(resp, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// NewRequest creates an HTTP request for an operation from the input struct.
+// Query, header, and cookie parameters are left out when they are nil pointers
+// or empty slices. Other values are always sent, so e.g. an explicit `false`
+// can override a parameter's default.
+func NewRequest[I any](ctx context.Context, c *Client, method, path string, input *I) (*http.Request, error) {
+	query := url.Values{}
+	header := http.Header{}
+	cookies := []*http.Cookie{}
+	var body io.Reader
+	contentType := ""
+
+	if input != nil {
+		v := reflect.ValueOf(input).Elem()
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("input must be a struct")
+		}
+
+		var err error
+		eachField(v, func(f reflect.StructField, fv reflect.Value) {
+			if err != nil {
+				return
+			}
+			switch {
+			case f.Name == "Body":
+				if (fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface) && fv.IsNil() {
+					return
+				}
+				contentType = f.Tag.Get("contentType")
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				var b []byte
+				if b, err = json.Marshal(fv.Interface()); err == nil {
+					body = bytes.NewReader(b)
+				}
+			case f.Name == "RawBody" && f.Type == bytesType:
+				if fv.Len() == 0 {
+					return
+				}
+				contentType = f.Tag.Get("contentType")
+				if contentType == "" {
+					contentType = "application/octet-stream"
+				}
+				body = bytes.NewReader(fv.Bytes())
+			case f.Tag.Get("path") != "":
+				name := f.Tag.Get("path")
+				placeholder := "{" + name + "}"
+				if !strings.Contains(path, placeholder) {
+					err = fmt.Errorf("path parameter %s not found in %s", name, path)
+					return
+				}
+				path = strings.ReplaceAll(path, placeholder, url.PathEscape(formatParam(f, fv, time.RFC3339Nano)))
+			case f.Tag.Get("query") != "":
+				var ok bool
+				if fv, ok = paramValue(fv); !ok {
+					return
+				}
+				name := strings.Split(f.Tag.Get("query"), ",")[0]
+				if fv.Kind() == reflect.Slice && f.Tag.Get("explode") == "true" {
+					for i := 0; i < fv.Len(); i++ {
+						query.Add(name, formatValue(fv.Index(i), timeFormat(f, time.RFC3339Nano)))
+					}
+					return
+				}
+				query.Set(name, formatParam(f, fv, time.RFC3339Nano))
+			case f.Tag.Get("header") != "":
+				var ok bool
+				if fv, ok = paramValue(fv); !ok {
+					return
+				}
+				header.Set(f.Tag.Get("header"), formatParam(f, fv, http.TimeFormat))
+			case f.Tag.Get("cookie") != "":
+				var ok bool
+				if fv, ok = paramValue(fv); !ok {
+					return
+				}
+				if fv.Type() == cookieType {
+					if fv.IsZero() {
+						return
+					}
+					cookie := fv.Interface().(http.Cookie)
+					cookies = append(cookies, &cookie)
+					return
+				}
+				cookies = append(cookies, &http.Cookie{Name: f.Tag.Get("cookie"), Value: formatParam(f, fv, time.RFC3339Nano)})
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, values := range c.Header {
+		for _, value := range values {
+			req.Header.Add(k, value)
+		}
+	}
+	for k, values := range header {
+		req.Header[k] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	return req, nil
+}
+
+// ParseResponse parses an HTTP response into the output struct. Responses
+// with a status code of 400 or above are returned as a `*huma.ErrorModel`.
+func ParseResponse[O any](resp *http.Response, output *O) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		model := &huma.ErrorModel{}
+		if len(data) == 0 || json.Unmarshal(data, model) != nil {
+			model.Detail = strings.TrimSpace(string(data))
+		}
+		if model.Status == 0 {
+			model.Status = resp.StatusCode
+		}
+		if model.Title == "" {
+			model.Title = http.StatusText(resp.StatusCode)
+		}
+		return model
+	}
+
+	v := reflect.ValueOf(output).Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("output must be a struct")
+	}
+
+	eachField(v, func(f reflect.StructField, fv reflect.Value) {
+		if err != nil {
+			return
+		}
+		switch {
+		case f.Name == "Status" && fv.Kind() == reflect.Int:
+			fv.SetInt(int64(resp.StatusCode))
+		case f.Name == "Body":
+			if len(data) == 0 {
+				return
+			}
+			if f.Type == bytesType {
+				fv.SetBytes(data)
+				return
+			}
+			if err = json.Unmarshal(data, fv.Addr().Interface()); err != nil {
+				err = fmt.Errorf("unable to parse response body: %w", err)
+			}
+		case f.Tag.Get("header") != "":
+			value := resp.Header.Get(f.Tag.Get("header"))
+			if value == "" {
+				return
+			}
+			if err = parseValue(fv, value, timeFormat(f, http.TimeFormat)); err != nil {
+				err = fmt.Errorf("unable to parse header %s: %w", f.Tag.Get("header"), err)
+			}
+		}
+	})
+	return err
+}
+
+// eachField calls the callback for each field of a struct, including those of
+// embedded structs.
+func eachField(v reflect.Value, cb func(f reflect.StructField, fv reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			eachField(v.Field(i), cb)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		cb(f, v.Field(i))
+	}
+}
+
+// timeFormat returns the field's `timeFormat` tag or the given default.
+func timeFormat(f reflect.StructField, def string) string {
+	if tf := f.Tag.Get("timeFormat"); tf != "" {
+		return tf
+	}
+	return def
+}
+
+// paramValue returns the value to send for a parameter, dereferencing
+// pointers, or false if the parameter is unset.
+func paramValue(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.Len() == 0 {
+		return v, false
+	}
+	return v, true
+}
+
+// formatParam formats a parameter field's value, joining slices with commas.
+func formatParam(f reflect.StructField, v reflect.Value, defaultTimeFormat string) string {
+	format := timeFormat(f, defaultTimeFormat)
+	if v.Kind() == reflect.Slice {
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = formatValue(v.Index(i), format)
+		}
+		return strings.Join(values, ",")
+	}
+	return formatValue(v, format)
+}
+
+// formatValue formats a single parameter value.
+func formatValue(v reflect.Value, timeFormat string) string {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(timeFormat)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parseValue parses a response header value into a field.
+func parseValue(v reflect.Value, value, timeFormat string) error {
+	if v.Type() == timeType {
+		t, err := time.Parse(timeFormat, value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(value, ",")
+		s := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := parseValue(s.Index(i), strings.TrimSpace(part), timeFormat); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
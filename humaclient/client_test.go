@@ -0,0 +1,167 @@
+package humaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Thing struct {
+	ID   string   `json:"id" doc:"Thing ID"`
+	Name string   `json:"name" minLength:"1"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+type PutThingInput struct {
+	ID      string    `path:"thing-id"`
+	Tags    []string  `query:"tags"`
+	Limit   int       `query:"limit"`
+	Flags   []string  `query:"flag" explode:"true"`
+	Since   time.Time `query:"since"`
+	TraceID string    `header:"X-Trace-Id"`
+	Session string    `cookie:"session"`
+	Body    Thing
+}
+
+type PutThingOutput struct {
+	Status   int
+	ETag     string    `header:"ETag"`
+	Modified time.Time `header:"Last-Modified"`
+	Body     Thing
+}
+
+type GetRawOutput struct {
+	Body []byte
+}
+
+func setup(t *testing.T) (*Client, func()) {
+	router, api := humatest.New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{thing-id}",
+		Summary:     "Create or update a thing",
+	}, func(ctx context.Context, input *PutThingInput) (*PutThingOutput, error) {
+		assert.Equal(t, []string{"a", "b"}, input.Tags)
+		assert.Equal(t, 5, input.Limit)
+		assert.Equal(t, []string{"x,y", "z"}, input.Flags)
+		assert.True(t, input.Since.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+		assert.Equal(t, "trace", input.TraceID)
+		assert.Equal(t, "secret", input.Session)
+
+		resp := &PutThingOutput{
+			Status:   http.StatusCreated,
+			ETag:     "abc",
+			Modified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Body:     input.Body,
+		}
+		resp.Body.ID = input.ID
+		return resp, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-raw",
+		Method:      http.MethodGet,
+		Path:        "/raw",
+	}, func(ctx context.Context, input *struct{}) (*GetRawOutput, error) {
+		return &GetRawOutput{Body: []byte("raw data")}, nil
+	})
+
+	server := httptest.NewServer(router)
+	return New(server.URL + "/"), server.Close
+}
+
+func TestDo(t *testing.T) {
+	client, done := setup(t)
+	defer done()
+
+	resp, err := Do[PutThingInput, PutThingOutput](context.Background(), client, http.MethodPut, "/things/{thing-id}", &PutThingInput{
+		ID:      "a b",
+		Tags:    []string{"a", "b"},
+		Limit:   5,
+		Flags:   []string{"x,y", "z"},
+		Since:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		TraceID: "trace",
+		Session: "secret",
+		Body:    Thing{Name: "Thing"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.Status)
+	assert.Equal(t, "abc", resp.ETag)
+	assert.True(t, resp.Modified.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "a b", resp.Body.ID)
+	assert.Equal(t, "Thing", resp.Body.Name)
+
+	raw, err := Do[struct{}, GetRawOutput](context.Background(), client, http.MethodGet, "/raw", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "raw data", string(raw.Body))
+}
+
+func TestDoError(t *testing.T) {
+	client, done := setup(t)
+	defer done()
+
+	// Fails validation because of the empty name.
+	_, err := Do[PutThingInput, PutThingOutput](context.Background(), client, http.MethodPut, "/things/{thing-id}", &PutThingInput{ID: "abc"})
+	require.Error(t, err)
+
+	var model *huma.ErrorModel
+	require.ErrorAs(t, err, &model)
+	assert.Equal(t, http.StatusUnprocessableEntity, model.GetStatus())
+	assert.NotEmpty(t, model.Errors)
+
+	_, err = Do[struct{}, GetRawOutput](context.Background(), client, http.MethodGet, "/missing", nil)
+	require.ErrorAs(t, err, &model)
+	assert.Equal(t, http.StatusNotFound, model.Status)
+	assert.Equal(t, "Not Found", model.Title)
+}
+
+func TestNewRequest(t *testing.T) {
+	client := New("https://example.com")
+	client.Header = http.Header{"Authorization": {"Bearer abc"}}
+
+	req, err := NewRequest(context.Background(), client, http.MethodPut, "/things/{thing-id}", &PutThingInput{ID: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/things/abc?limit=0&since=0001-01-01T00%3A00%3A00Z", req.URL.String())
+	assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "application/json", req.Header.Get("Accept"))
+	assert.Empty(t, req.Header.Get("X-Trace-Id"))
+
+	_, err = NewRequest(context.Background(), client, http.MethodPut, "/things", &PutThingInput{ID: "abc"})
+	assert.ErrorContains(t, err, "path parameter thing-id not found")
+}
+
+func TestNewRequestZeroParams(t *testing.T) {
+	type Input struct {
+		Enabled bool     `query:"enabled" default:"true"`
+		Count   int      `query:"count"`
+		Name    string   `header:"X-Name"`
+		Limit   *int     `query:"limit"`
+		Tags    []string `query:"tags"`
+		Session *string  `cookie:"session"`
+	}
+
+	client := New("https://example.com")
+	req, err := NewRequest(context.Background(), client, http.MethodGet, "/things", &Input{})
+	require.NoError(t, err)
+	assert.Equal(t, "count=0&enabled=false", req.URL.RawQuery)
+	assert.Equal(t, []string{""}, req.Header.Values("X-Name"))
+	assert.Empty(t, req.Cookies())
+
+	limit := 0
+	session := "abc"
+	req, err = NewRequest(context.Background(), client, http.MethodGet, "/things", &Input{Enabled: true, Limit: &limit, Session: &session})
+	require.NoError(t, err)
+	assert.Equal(t, "count=0&enabled=true&limit=0", req.URL.RawQuery)
+	require.Len(t, req.Cookies(), 1)
+	assert.Equal(t, "abc", req.Cookies()[0].Value)
+}
@@ -0,0 +1,130 @@
+package humaclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGo(t *testing.T) {
+	_, api := humatest.New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{thing-id}",
+		Summary:     "Create or update a thing",
+	}, func(ctx context.Context, input *PutThingInput) (*PutThingOutput, error) {
+		return nil, nil
+	})
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-api-raw",
+		Method:      http.MethodGet,
+		Path:        "/raw",
+		Summary:     "API raw data",
+		Deprecated:  true,
+	}, func(ctx context.Context, input *struct{}) (*GetRawOutput, error) {
+		return nil, nil
+	})
+
+	src, err := GenerateGo(api, GoOptions{Package: "things"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Code generated by humaclient. DO NOT EDIT.
+
+// Package things provides a client for Test API.
+package things
+
+import (
+	"context"
+
+	"github.com/danielgtaylor/huma/v2/humaclient"
+)
+
+// Client for Test API.
+type Client struct {
+	*humaclient.Client
+}
+
+// New creates a client for the API at the given base URL.
+func New(baseURL string) *Client {
+	return &Client{humaclient.New(baseURL)}
+}
+
+// GetAPIRaw API raw data.
+//
+// Deprecated: this operation is deprecated by the API.
+func (c *Client) GetAPIRaw(ctx context.Context, input *struct{}) (*humaclient.GetRawOutput, error) {
+	return humaclient.Do[struct{}, humaclient.GetRawOutput](ctx, c.Client, "GET", "/raw", input)
+}
+
+// PutThing create or update a thing.
+func (c *Client) PutThing(ctx context.Context, input *humaclient.PutThingInput) (*humaclient.PutThingOutput, error) {
+	return humaclient.Do[humaclient.PutThingInput, humaclient.PutThingOutput](ctx, c.Client, "PUT", "/things/{thing-id}", input)
+}
+`, string(src))
+}
+
+func TestGenerateGoUnnamed(t *testing.T) {
+	_, api := humatest.New(t)
+
+	huma.Get(api, "/anon", func(ctx context.Context, input *struct {
+		ID string `query:"id"`
+	}) (*GetRawOutput, error) {
+		return nil, nil
+	})
+
+	_, err := GenerateGo(api, GoOptions{})
+	assert.ErrorContains(t, err, "unnamed type")
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	_, api := humatest.New(t)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{thing-id}",
+		Summary:     "Create or update a thing",
+	}, func(ctx context.Context, input *PutThingInput) (*PutThingOutput, error) {
+		return nil, nil
+	})
+
+	src, err := GenerateTypeScript(api)
+	require.NoError(t, err)
+	ts := string(src)
+
+	assert.Contains(t, ts, `export interface Thing {
+  /** Thing ID */
+  "id": string;
+  "name": string;
+  "tags"?: string[];
+}`)
+	assert.Contains(t, ts, `  /**
+   * Create or update a thing
+   */
+  async putThing(params: { "thing-id": string; "tags"?: string[]; "limit"?: number; "flag"?: string[]; "since"?: string; "X-Trace-Id"?: string }, body: Thing): Promise<Response<Thing>> {
+    return this.request<Thing>("PUT", "/things/{thing-id}", {
+      path: { "thing-id": params["thing-id"] },
+      query: { "tags": params["tags"], "limit": params["limit"], "flag": params["flag"], "since": params["since"] },
+      header: { "X-Trace-Id": params["X-Trace-Id"] },
+    }, body, ["flag"]);
+  }`)
+	assert.Contains(t, ts, "export class APIError extends Error")
+	assert.Contains(t, ts, "query.append(name, String(item));")
+}
+
+func TestGenerateTypeScriptNoSchemas(t *testing.T) {
+	_, api := humatest.New(t)
+	api.OpenAPI().Components.Schemas = nil
+
+	src, err := GenerateTypeScript(api)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "export class Client")
+}
@@ -0,0 +1,292 @@
+package humaclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/casing"
+)
+
+// tsRuntime is the shared request logic for generated TypeScript clients.
+const tsRuntime = `export interface ClientOptions {
+  baseURL: string;
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+export interface Response<T> {
+  status: number;
+  headers: Headers;
+  body: T;
+}
+
+export class APIError extends Error {
+  constructor(public status: number, public body: any) {
+    super(body?.detail ?? body?.title ?? ` + "`HTTP ${status}`" + `);
+  }
+}
+
+export class Client {
+  constructor(private options: ClientOptions) {}
+
+  private async request<T>(
+    method: string,
+    path: string,
+    params: Record<string, Record<string, unknown>>,
+    body?: unknown,
+    explode: string[] = [],
+  ): Promise<Response<T>> {
+    for (const [name, value] of Object.entries(params.path)) {
+      path = path.replace("{" + name + "}", encodeURIComponent(String(value)));
+    }
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(params.query)) {
+      if (value === undefined) {
+        continue;
+      }
+      if (Array.isArray(value) && explode.includes(name)) {
+        for (const item of value) {
+          query.append(name, String(item));
+        }
+      } else {
+        query.set(name, Array.isArray(value) ? value.join(",") : String(value));
+      }
+    }
+    const headers: Record<string, string> = { Accept: "application/json", ...this.options.headers };
+    for (const [name, value] of Object.entries(params.header)) {
+      if (value !== undefined) {
+        headers[name] = Array.isArray(value) ? value.join(",") : String(value);
+      }
+    }
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const qs = query.toString();
+    const resp = await (this.options.fetch ?? fetch)(this.options.baseURL + path + (qs ? "?" + qs : ""), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const text = await resp.text();
+    const parsed = text ? JSON.parse(text) : undefined;
+    if (resp.status >= 400) {
+      throw new APIError(resp.status, parsed);
+    }
+    return { status: resp.status, headers: resp.headers, body: parsed };
+  }
+`
+
+// GenerateTypeScript generates the source of a TypeScript client with an
+// interface for each schema in the API's registry and one method per
+// registered operation. The client uses the standard `fetch` API.
+func GenerateTypeScript(api huma.API) ([]byte, error) {
+	oapi := api.OpenAPI()
+	var schemas map[string]*huma.Schema
+	if oapi.Components != nil && oapi.Components.Schemas != nil {
+		schemas = oapi.Components.Schemas.Map()
+	}
+
+	src := &bytes.Buffer{}
+	src.WriteString("// Code generated by humaclient. DO NOT EDIT.\n\n")
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := schemas[name]
+		if s.Description != "" {
+			fmt.Fprintf(src, "/** %s */\n", strings.ReplaceAll(s.Description, "*/", "* /"))
+		}
+		if s.Type == huma.TypeObject && len(s.Properties) > 0 {
+			fmt.Fprintf(src, "export interface %s %s\n\n", name, tsType(s, ""))
+		} else {
+			fmt.Fprintf(src, "export type %s = %s;\n\n", name, tsType(s, ""))
+		}
+	}
+
+	src.WriteString(tsRuntime)
+
+	for _, op := range operations(api) {
+		name := casing.LowerCamel(op.OperationID, casing.Initialism)
+
+		params := map[string][]string{"path": {}, "query": {}, "header": {}}
+		fields := []string{}
+		explode := []string{}
+		for _, p := range op.Parameters {
+			if _, ok := params[p.In]; !ok {
+				// Cookies are managed by the browser.
+				continue
+			}
+			params[p.In] = append(params[p.In], fmt.Sprintf("%q: params[%q]", p.Name, p.Name))
+			if p.In == "query" && p.Explode != nil && *p.Explode {
+				// Like the Go client, each item is sent as a separate value.
+				explode = append(explode, strconv.Quote(p.Name))
+			}
+			optional := "?"
+			if p.Required {
+				optional = ""
+			}
+			fields = append(fields, fmt.Sprintf("%q%s: %s", p.Name, optional, tsType(p.Schema, "  ")))
+		}
+
+		args := []string{}
+		if len(fields) > 0 {
+			args = append(args, "params: { "+strings.Join(fields, "; ")+" }")
+		}
+		bodyArg := "undefined"
+		if op.RequestBody != nil {
+			if mt := op.RequestBody.Content["application/json"]; mt != nil && mt.Schema != nil {
+				optional := "?"
+				if op.RequestBody.Required {
+					optional = ""
+				}
+				args = append(args, "body"+optional+": "+tsType(mt.Schema, "  "))
+				bodyArg = "body"
+			}
+		}
+
+		result := "void"
+		if resp := successResponse(op); resp != nil {
+			if mt := resp.Content["application/json"]; mt != nil && mt.Schema != nil {
+				result = tsType(mt.Schema, "  ")
+			}
+		}
+
+		fmt.Fprintf(src, "\n")
+		if op.Summary != "" || op.Deprecated {
+			fmt.Fprintf(src, "  /**\n")
+			if op.Summary != "" {
+				fmt.Fprintf(src, "   * %s\n", strings.ReplaceAll(op.Summary, "*/", "* /"))
+			}
+			if op.Deprecated {
+				fmt.Fprintf(src, "   * @deprecated\n")
+			}
+			fmt.Fprintf(src, "   */\n")
+		}
+		fmt.Fprintf(src, "  async %s(%s): Promise<Response<%s>> {\n", name, strings.Join(args, ", "), result)
+		fmt.Fprintf(src, "    return this.request<%s>(%q, %q, {\n", result, op.Method, op.Path)
+		for _, in := range []string{"path", "query", "header"} {
+			fmt.Fprintf(src, "      %s: { %s },\n", in, strings.Join(params[in], ", "))
+		}
+		if len(explode) > 0 {
+			fmt.Fprintf(src, "    }, %s, [%s]);\n  }\n", bodyArg, strings.Join(explode, ", "))
+		} else {
+			fmt.Fprintf(src, "    }, %s);\n  }\n", bodyArg)
+		}
+	}
+
+	src.WriteString("}\n")
+	return src.Bytes(), nil
+}
+
+// successResponse returns the operation's first documented 2xx response.
+func successResponse(op *huma.Operation) *huma.Response {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) == 0 {
+		return nil
+	}
+	return op.Responses[codes[0]]
+}
+
+// tsType returns a TypeScript type expression for a JSON schema.
+func tsType(s *huma.Schema, indent string) string {
+	if s == nil {
+		return "unknown"
+	}
+
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+
+	t := tsBaseType(s, indent)
+	if s.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+func tsBaseType(s *huma.Schema, indent string) string {
+	if len(s.Enum) > 0 {
+		values := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			b, _ := json.Marshal(v)
+			values = append(values, string(b))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	if variants := append(append([]*huma.Schema{}, s.OneOf...), s.AnyOf...); len(variants) > 0 {
+		types := make([]string, 0, len(variants))
+		for _, v := range variants {
+			types = append(types, tsType(v, indent))
+		}
+		return strings.Join(types, " | ")
+	}
+
+	switch s.Type {
+	case huma.TypeBoolean:
+		return "boolean"
+	case huma.TypeInteger, huma.TypeNumber:
+		return "number"
+	case huma.TypeString:
+		return "string"
+	case huma.TypeArray:
+		item := tsType(s.Items, indent)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case huma.TypeObject:
+		if len(s.Properties) == 0 {
+			if ap, ok := s.AdditionalProperties.(*huma.Schema); ok {
+				return "Record<string, " + tsType(ap, indent) + ">"
+			}
+			return "Record<string, unknown>"
+		}
+
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b := &strings.Builder{}
+		b.WriteString("{\n")
+		for _, name := range names {
+			prop := s.Properties[name]
+			if prop.Description != "" {
+				fmt.Fprintf(b, "%s  /** %s */\n", indent, strings.ReplaceAll(prop.Description, "*/", "* /"))
+			}
+			optional := "?"
+			for _, r := range s.Required {
+				if r == name {
+					optional = ""
+					break
+				}
+			}
+			if prop.ReadOnly {
+				fmt.Fprintf(b, "%s  readonly ", indent)
+			} else {
+				fmt.Fprintf(b, "%s  ", indent)
+			}
+			fmt.Fprintf(b, "%s%s: %s;\n", strconv.Quote(name), optional, tsType(prop, indent+"  "))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,323 @@
+// Package humagen generates Go code containing static schemas for your input,
+// output, and model structs, meant to be run via `go generate`. The generated
+// `RegisterSchemas` function registers each schema with
+// `huma.RegisterGeneratedSchema` so that registering operations no longer
+// needs to reflect on every struct field & tag at startup, which helps cold
+// start times in serverless deployments. Only schemas are generated: request
+// decoding and validation work the same as without generated code.
+//
+// Create a generator program next to your models, which is excluded from the
+// normal build:
+//
+//	//go:build ignore
+//
+//	package main
+//
+//	func main() {
+//		src, err := humagen.Generate("models", models.Thing{}, models.ThingList{})
+//		if err != nil {
+//			panic(err)
+//		}
+//		if err := os.WriteFile("schemas_gen.go", src, 0o644); err != nil {
+//			panic(err)
+//		}
+//	}
+//
+// Then add a directive to the models package and run `go generate ./...`
+// whenever the structs change:
+//
+//	//go:generate go run gen.go
+//
+// Finally, register the schemas with the API's registry before registering
+// any operations:
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	models.RegisterSchemas(config.Components.Schemas)
+package humagen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/internal/gocode"
+)
+
+const humaPath = "github.com/danielgtaylor/huma/v2"
+
+var schemaType = reflect.TypeOf(huma.Schema{})
+
+// Generate returns the source of a Go file for the package `pkg` which
+// registers a generated schema for each of the given struct values. The
+// structs must all be defined in that package. Nested structs are referenced
+// through the registry as usual, so include them to generate their schemas
+// too.
+func Generate(pkg string, values ...any) ([]byte, error) {
+	// A new registry has no generated schemas, so the current struct
+	// definitions are always used.
+	g := &generator{
+		registry: huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer),
+		imports:  gocode.NewImports("RegisterSchemas"),
+	}
+	g.imports.Add("reflect", "reflect")
+	g.imports.Add(humaPath, "huma")
+
+	body := &bytes.Buffer{}
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+			return nil, fmt.Errorf("%T is not a named struct", v)
+		}
+		if g.imports.Local == "" {
+			g.imports.Local = t.PkgPath()
+		}
+		if t.PkgPath() != g.imports.Local {
+			return nil, fmt.Errorf("type %s is not in package %s", t, g.imports.Local)
+		}
+
+		s := huma.SchemaFromType(g.registry, t)
+		if s == nil || s.Ref != "" {
+			return nil, fmt.Errorf("type %s does not have a generated object schema", t)
+		}
+		expr, err := g.value(reflect.ValueOf(s), true)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", t, err)
+		}
+		names, hiddenNames := propertyNames(t, s)
+		props, err := g.value(reflect.ValueOf(names), true)
+		if err != nil {
+			return nil, err
+		}
+		hidden, err := g.value(reflect.ValueOf(hiddenNames), true)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(body, "\thuma.RegisterGeneratedSchema(r, reflect.TypeOf(%s{}), %s, %s, func(r huma.Registry) *huma.Schema {\n\t\treturn %s\n\t})\n", t.Name(), props, hidden, expr)
+	}
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by humagen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(src, "package %s\n\n%s\n// RegisterSchemas registers the generated schemas with the registry, so they\n// are used instead of reflecting on the structs.\n", pkg, g.imports.Decl())
+	fmt.Fprintf(src, "func RegisterSchemas(r huma.Registry) {\n")
+	src.Write(body.Bytes())
+	fmt.Fprintf(src, "}\n")
+
+	return format.Source(src.Bytes())
+}
+
+// propertyNames returns the schema's property names in struct field order,
+// including the fields of embedded structs after the struct's own fields, as
+// well as the names of fields tagged `hidden:"true"` which are left out of the
+// schema but still accepted in requests.
+func propertyNames(t reflect.Type, s *huma.Schema) ([]string, []string) {
+	names := []string{}
+	var hidden []string
+	seen := map[string]bool{}
+	var walk func(t reflect.Type, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		var embedded []reflect.Type
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if f.Anonymous && name == "" {
+				ft := f.Type
+				for ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					embedded = append(embedded, ft)
+					continue
+				}
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if !f.IsExported() || seen[name] || name == "-" {
+				continue
+			}
+			if f.Tag.Get("hidden") == "true" {
+				seen[name] = true
+				hidden = append(hidden, name)
+				continue
+			}
+			if s.Properties[name] == nil {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+		for _, e := range embedded {
+			walk(e, visited)
+		}
+	}
+	walk(t, map[reflect.Type]bool{})
+	return names, hidden
+}
+
+// generator writes Go expressions for schema values.
+type generator struct {
+	registry huma.Registry
+	imports  *gocode.Imports
+}
+
+// value returns a Go expression for the value. When `typed` is false the
+// expression is assigned to an interface, so basic values other than
+// strings, bools, and ints need an explicit conversion to keep their type.
+func (g *generator) value(v reflect.Value, typed bool) (string, error) {
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		return g.value(v.Elem(), false)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		if v.Elem().Kind() == reflect.Struct {
+			expr, err := g.value(v.Elem(), true)
+			return "&" + expr, err
+		}
+		elem, err := g.imports.TypeExpr(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		expr, err := g.value(v.Elem(), true)
+		return "&[]" + elem + "{" + expr + "}[0]", err
+	case reflect.Struct:
+		return g.structLit(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		typ, err := g.imports.TypeExpr(t)
+		if err != nil {
+			return "", err
+		}
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := g.value(v.Index(i), true)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return typ + "{" + strings.Join(items, ", ") + "}", nil
+	case reflect.Map:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		typ, err := g.imports.TypeExpr(t)
+		if err != nil {
+			return "", err
+		}
+		keys := v.MapKeys()
+		entries := make([]string, 0, len(keys))
+		for _, k := range keys {
+			key, err := g.value(k, true)
+			if err != nil {
+				return "", err
+			}
+			value, err := g.value(v.MapIndex(k), true)
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, key+": "+value)
+		}
+		sort.Strings(entries)
+		if len(entries) == 0 {
+			return typ + "{}", nil
+		}
+		return typ + "{\n" + strings.Join(entries, ",\n") + ",\n}", nil
+	}
+
+	var lit string
+	switch v.Kind() {
+	case reflect.Bool:
+		lit = strconv.FormatBool(v.Bool())
+	case reflect.String:
+		lit = strconv.Quote(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lit = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		lit = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		lit = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		if strings.ContainsAny(lit, "NI") {
+			return "", fmt.Errorf("unsupported value %s", lit)
+		}
+	default:
+		return "", fmt.Errorf("unsupported value of type %s", t)
+	}
+
+	if typed || t == reflect.TypeOf("") || t == reflect.TypeOf(true) || t == reflect.TypeOf(0) {
+		return lit, nil
+	}
+	typ, err := g.imports.TypeExpr(t)
+	if err != nil {
+		return "", err
+	}
+	return typ + "(" + lit + ")", nil
+}
+
+// structLit returns a composite literal with the struct's non-zero fields.
+// References to other schemas are resolved through the registry at runtime
+// so that the referenced types are also registered.
+func (g *generator) structLit(v reflect.Value) (string, error) {
+	t := v.Type()
+	typ, err := g.imports.TypeExpr(t)
+	if err != nil {
+		return "", err
+	}
+
+	fields := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		if !f.IsExported() {
+			if t == schemaType {
+				// Precomputed values are recreated at runtime.
+				continue
+			}
+			return "", fmt.Errorf("unsupported unexported field %s.%s", t, f.Name)
+		}
+
+		if t == schemaType && f.Name == "Ref" {
+			if rt := g.registry.TypeFromRef(fv.String()); rt != nil {
+				ref, err := g.imports.TypeExpr(rt)
+				if err != nil {
+					return "", fmt.Errorf("reference %s: %w", fv.String(), err)
+				}
+				fields = append(fields, fmt.Sprintf("Ref: r.Schema(reflect.TypeOf((*%s)(nil)).Elem(), true, \"\").Ref", ref))
+				continue
+			}
+		}
+
+		expr, err := g.value(fv, true)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, f.Name+": "+expr)
+	}
+
+	if len(fields) == 0 {
+		return typ + "{}", nil
+	}
+	return typ + "{\n" + strings.Join(fields, ",\n") + ",\n}", nil
+}
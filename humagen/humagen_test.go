@@ -0,0 +1,95 @@
+package humagen
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humagen/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	src, err := Generate("models", models.Thing{}, models.Tag{})
+	require.NoError(t, err)
+
+	existing, err := os.ReadFile("internal/models/schemas_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(existing), string(src), "run `go generate ./...` to update")
+}
+
+// schemas generates the registry contents for a type, optionally using the
+// generated schemas.
+func schemas(t *testing.T, generated bool, typ reflect.Type) (huma.Registry, *huma.Schema, string) {
+	t.Helper()
+	registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	if generated {
+		models.RegisterSchemas(registry)
+	}
+	s := registry.Schema(typ, false, "")
+	b, err := json.Marshal(registry)
+	require.NoError(t, err)
+	return registry, s, string(b)
+}
+
+func TestGeneratedMatchesReflection(t *testing.T) {
+	typ := reflect.TypeOf(models.Thing{})
+	genRegistry, genSchema, generated := schemas(t, true, typ)
+	refRegistry, refSchema, reflected := schemas(t, false, typ)
+
+	assert.JSONEq(t, reflected, generated)
+	assert.Contains(t, generated, `"ErrorDetail"`)
+
+	// Validation behaves the same, including the order of errors.
+	var input any
+	require.NoError(t, json.Unmarshal([]byte(`{"count": 500, "ratio": 0.7, "tags": [{"name": "Bad", "color": "pink"}], "aliases": ["a", "a"], "secret": "x", "extra": true}`), &input))
+
+	validate := func(registry huma.Registry, s *huma.Schema) []error {
+		pb := huma.NewPathBuffer([]byte{}, 0)
+		res := &huma.ValidateResult{}
+		huma.Validate(registry, s, pb, huma.ModeWriteToServer, input, res)
+		return res.Errors
+	}
+
+	expected := validate(refRegistry, refSchema)
+	require.NotEmpty(t, expected)
+	assert.Equal(t, expected, validate(genRegistry, genSchema))
+}
+
+type Other struct {
+	Value string `json:"value"`
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate("models", "not a struct")
+	assert.ErrorContains(t, err, "not a named struct")
+
+	_, err = Generate("models", models.Tag{}, Other{})
+	assert.ErrorContains(t, err, "is not in package")
+
+	_, err = Generate("humagen", struct{ Value string }{})
+	assert.ErrorContains(t, err, "not a named struct")
+
+	_, err = Generate("humagen", Anonymous{})
+	assert.ErrorContains(t, err, "unnamed type")
+}
+
+type Anonymous struct {
+	Nested struct {
+		Value string `json:"value"`
+	} `json:"nested"`
+}
+
+func TestGeneratePackageImports(t *testing.T) {
+	src, err := Generate("humagen", Other{}, WithModels{})
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "\t\"github.com/danielgtaylor/huma/v2/humagen/internal/models\"\n")
+	assert.Contains(t, string(src), `r.Schema(reflect.TypeOf((*models.Tag)(nil)).Elem(), true, "").Ref`)
+}
+
+type WithModels struct {
+	Tag models.Tag `json:"tag"`
+}
@@ -0,0 +1,20 @@
+//go:build ignore
+
+package main
+
+import (
+	"os"
+
+	"github.com/danielgtaylor/huma/v2/humagen"
+	"github.com/danielgtaylor/huma/v2/humagen/internal/models"
+)
+
+func main() {
+	src, err := humagen.Generate("models", models.Thing{}, models.Tag{})
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile("schemas_gen.go", src, 0o644); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,40 @@
+// Package models contains example structs with generated schemas, used to
+// test the generator.
+package models
+
+import (
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+//go:generate go run gen.go
+
+// Timestamps are embedded into other models.
+type Timestamps struct {
+	Created time.Time  `json:"created" readOnly:"true"`
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// Tag is referenced by other models.
+type Tag struct {
+	Name  string `json:"name" minLength:"1" maxLength:"20" pattern:"^[a-z]+$"`
+	Color string `json:"color,omitempty" enum:"red,green,blue" default:"red"`
+}
+
+// Thing is an example model using most of the supported validation tags.
+type Thing struct {
+	ID       string              `json:"id" doc:"Unique identifier" example:"abc123"`
+	Count    int64               `json:"count" minimum:"0" maximum:"100" default:"1"`
+	Ratio    *float64            `json:"ratio,omitempty" exclusiveMinimum:"0" multipleOf:"0.5"`
+	Enabled  bool                `json:"enabled" required:"false"`
+	Tags     []Tag               `json:"tags" maxItems:"5"`
+	Aliases  []string            `json:"aliases,omitempty" uniqueItems:"true"`
+	Labels   map[string]string   `json:"labels,omitempty"`
+	Priority int                 `json:"priority,omitempty" enum:"1,2,3"`
+	Related  *Thing              `json:"related,omitempty"`
+	Problems []*huma.ErrorDetail `json:"problems,omitempty"`
+	Internal string              `json:"-"`
+	Secret   string              `json:"secret,omitempty" hidden:"true"`
+	Timestamps
+}
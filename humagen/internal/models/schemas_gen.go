@@ -0,0 +1,109 @@
+// Code generated by humagen. DO NOT EDIT.
+
+package models
+
+import (
+	"reflect"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// RegisterSchemas registers the generated schemas with the registry, so they
+// are used instead of reflecting on the structs.
+func RegisterSchemas(r huma.Registry) {
+	huma.RegisterGeneratedSchema(r, reflect.TypeOf(Thing{}), []string{"id", "count", "ratio", "enabled", "tags", "aliases", "labels", "priority", "related", "problems", "created", "updated"}, []string{"secret"}, func(r huma.Registry) *huma.Schema {
+		return &huma.Schema{
+			Type:                 "object",
+			AdditionalProperties: false,
+			Properties: map[string]*huma.Schema{
+				"aliases": &huma.Schema{
+					Type: "array",
+					Items: &huma.Schema{
+						Type: "string",
+					},
+					UniqueItems: true,
+				},
+				"count": &huma.Schema{
+					Type:    "integer",
+					Format:  "int64",
+					Default: int64(1),
+					Minimum: &[]float64{0}[0],
+					Maximum: &[]float64{100}[0],
+				},
+				"created": &huma.Schema{
+					Type:     "string",
+					Format:   "date-time",
+					ReadOnly: true,
+				},
+				"enabled": &huma.Schema{
+					Type: "boolean",
+				},
+				"id": &huma.Schema{
+					Type:        "string",
+					Description: "Unique identifier",
+					Examples:    []any{"abc123"},
+				},
+				"labels": &huma.Schema{
+					Type: "object",
+					AdditionalProperties: &huma.Schema{
+						Type: "string",
+					},
+				},
+				"priority": &huma.Schema{
+					Type:   "integer",
+					Format: "int64",
+					Enum:   []any{float64(1), float64(2), float64(3)},
+				},
+				"problems": &huma.Schema{
+					Type: "array",
+					Items: &huma.Schema{
+						Ref: r.Schema(reflect.TypeOf((*huma.ErrorDetail)(nil)).Elem(), true, "").Ref,
+					},
+				},
+				"ratio": &huma.Schema{
+					Type:             "number",
+					Format:           "double",
+					ExclusiveMinimum: &[]float64{0}[0],
+					MultipleOf:       &[]float64{0.5}[0],
+				},
+				"related": &huma.Schema{
+					Ref: r.Schema(reflect.TypeOf((*Thing)(nil)).Elem(), true, "").Ref,
+				},
+				"tags": &huma.Schema{
+					Type: "array",
+					Items: &huma.Schema{
+						Ref: r.Schema(reflect.TypeOf((*Tag)(nil)).Elem(), true, "").Ref,
+					},
+					MaxItems: &[]int{5}[0],
+				},
+				"updated": &huma.Schema{
+					Type:   "string",
+					Format: "date-time",
+				},
+			},
+			Required:          []string{"id", "count", "tags", "created"},
+			DependentRequired: map[string][]string{},
+		}
+	})
+	huma.RegisterGeneratedSchema(r, reflect.TypeOf(Tag{}), []string{"name", "color"}, nil, func(r huma.Registry) *huma.Schema {
+		return &huma.Schema{
+			Type:                 "object",
+			AdditionalProperties: false,
+			Properties: map[string]*huma.Schema{
+				"color": &huma.Schema{
+					Type:    "string",
+					Default: "red",
+					Enum:    []any{"red", "green", "blue"},
+				},
+				"name": &huma.Schema{
+					Type:      "string",
+					MinLength: &[]int{1}[0],
+					MaxLength: &[]int{20}[0],
+					Pattern:   "^[a-z]+$",
+				},
+			},
+			Required:          []string{"name"},
+			DependentRequired: map[string][]string{},
+		}
+	})
+}
@@ -0,0 +1,186 @@
+// Package humaotel provides OpenTelemetry tracing & metrics instrumentation
+// for Huma APIs.
+//
+// A server span is created for every request, named after the operation ID
+// and continuing any trace propagated via the incoming request headers. The
+// request duration and body sizes are recorded as histograms. Attributes
+// follow the OpenTelemetry HTTP semantic conventions.
+package humaotel
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer & meter.
+const ScopeName = "github.com/danielgtaylor/huma/v2/humaotel"
+
+// Options configures the instrumentation. Any unset providers default to the
+// global ones registered with the `otel` package.
+type Options struct {
+	// TracerProvider is used to create spans for each request.
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider is used to record request metrics.
+	MeterProvider metric.MeterProvider
+
+	// Propagator is used to extract the trace context from request headers.
+	Propagator propagation.TextMapPropagator
+}
+
+// New creates a middleware which traces & records metrics for every request.
+// It should be added before other middleware so that their time is included.
+//
+//	api.UseMiddleware(humaotel.New(humaotel.Options{}))
+func New(opts Options) func(ctx huma.Context, next func(huma.Context)) {
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = otel.GetTracerProvider()
+	}
+	if opts.MeterProvider == nil {
+		opts.MeterProvider = otel.GetMeterProvider()
+	}
+	if opts.Propagator == nil {
+		opts.Propagator = otel.GetTextMapPropagator()
+	}
+
+	tracer := opts.TracerProvider.Tracer(ScopeName)
+	meter := opts.MeterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of HTTP server requests."),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	requestSize, err := meter.Int64Histogram("http.server.request.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server request bodies."),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	responseSize, err := meter.Int64Histogram("http.server.response.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server response bodies."),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		start := time.Now()
+
+		name := ctx.Method()
+		route := ""
+		if op := ctx.Operation(); op != nil {
+			route = op.Path
+			name = op.OperationID
+			if name == "" {
+				name = ctx.Method() + " " + op.Path
+			}
+		}
+
+		host := ctx.Host()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		attrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(ctx.Method()),
+			semconv.HTTPRoute(route),
+		}
+
+		parent := opts.Propagator.Extract(ctx.Context(), headerCarrier{ctx})
+		spanCtx, span := tracer.Start(parent, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(
+				semconv.ServerAddress(host),
+				semconv.URLPath(ctx.URL().Path),
+				semconv.UserAgentOriginal(ctx.Header("User-Agent")),
+			),
+		)
+		defer span.End()
+
+		w := &writer{Wrapper: middleware.Wrap(huma.WithContext(ctx, spanCtx))}
+		next(w)
+
+		status := w.status
+		if status == 0 {
+			// Nothing explicitly set the status, so the server sends 200 OK.
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(status))
+		set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+		duration.Record(spanCtx, time.Since(start).Seconds(), set)
+		if length, err := strconv.ParseInt(ctx.Header("Content-Length"), 10, 64); err == nil {
+			requestSize.Record(spanCtx, length, set)
+		}
+		responseSize.Record(spanCtx, w.bytes, set)
+	}
+}
+
+// headerCarrier adapts request headers for trace context propagation.
+type headerCarrier struct {
+	ctx huma.Context
+}
+
+func (c headerCarrier) Get(key string) string {
+	return c.ctx.Header(key)
+}
+
+func (c headerCarrier) Set(key, value string) {
+	// Incoming requests are read-only.
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := []string{}
+	c.ctx.EachHeader(func(name, value string) {
+		keys = append(keys, name)
+	})
+	return keys
+}
+
+// writer wraps a `huma.Context` to record the response status and the number
+// of body bytes written.
+type writer struct {
+	middleware.Wrapper
+	status int
+	bytes  int64
+}
+
+func (w *writer) SetStatus(code int) {
+	w.status = code
+	w.Unwrap().SetStatus(code)
+}
+
+func (w *writer) BodyWriter() io.Writer {
+	return w
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.Unwrap().BodyWriter().Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
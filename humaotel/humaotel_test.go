@@ -0,0 +1,263 @@
+package humaotel
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingTracer records ended spans, building on the no-op implementation
+// so the tests don't depend on the OpenTelemetry SDK.
+type recordingTracer struct {
+	tracenoop.Tracer
+	mu    sync.Mutex
+	ended []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+	traceID := parent.TraceID()
+	if !traceID.IsValid() {
+		rand.Read(traceID[:])
+	}
+	var spanID trace.SpanID
+	rand.Read(spanID[:])
+
+	s := &recordedSpan{
+		tracer: t,
+		name:   name,
+		kind:   cfg.SpanKind(),
+		parent: parent,
+		attrs:  cfg.Attributes(),
+		sc:     trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}),
+	}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// Ended returns the spans which have ended so far.
+func (t *recordingTracer) Ended() []*recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*recordedSpan(nil), t.ended...)
+}
+
+type recordingTracerProvider struct {
+	tracenoop.TracerProvider
+	tracer *recordingTracer
+}
+
+func (p recordingTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return p.tracer
+}
+
+type recordedSpan struct {
+	tracenoop.Span
+	tracer *recordingTracer
+	name   string
+	kind   trace.SpanKind
+	parent trace.SpanContext
+	sc     trace.SpanContext
+	attrs  []attribute.KeyValue
+	status codes.Code
+}
+
+func (s *recordedSpan) SpanContext() trace.SpanContext { return s.sc }
+func (s *recordedSpan) IsRecording() bool              { return true }
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) {
+	s.status = code
+}
+
+func (s *recordedSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attrs = append(s.attrs, kv...)
+}
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.ended = append(s.tracer.ended, s)
+}
+
+func newTracerProvider() (trace.TracerProvider, *recordingTracer) {
+	t := &recordingTracer{}
+	return recordingTracerProvider{tracer: t}, t
+}
+
+// measurement is a single recorded histogram value.
+type measurement struct {
+	value float64
+	attrs attribute.Set
+}
+
+// recordingMeter records histogram measurements by instrument name.
+type recordingMeter struct {
+	metricnoop.Meter
+	scope        string
+	mu           sync.Mutex
+	measurements map[string][]measurement
+}
+
+func (m *recordingMeter) record(name string, value float64, opts []metric.RecordOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements[name] = append(m.measurements[name], measurement{value, metric.NewRecordConfig(opts).Attributes()})
+}
+
+func (m *recordingMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return float64Histogram{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Int64Histogram(name string, _ ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return int64Histogram{meter: m, name: name}, nil
+}
+
+type float64Histogram struct {
+	metricnoop.Float64Histogram
+	meter *recordingMeter
+	name  string
+}
+
+func (h float64Histogram) Record(_ context.Context, value float64, opts ...metric.RecordOption) {
+	h.meter.record(h.name, value, opts)
+}
+
+type int64Histogram struct {
+	metricnoop.Int64Histogram
+	meter *recordingMeter
+	name  string
+}
+
+func (h int64Histogram) Record(_ context.Context, value int64, opts ...metric.RecordOption) {
+	h.meter.record(h.name, float64(value), opts)
+}
+
+type recordingMeterProvider struct {
+	metricnoop.MeterProvider
+	meter *recordingMeter
+}
+
+func (p recordingMeterProvider) Meter(name string, _ ...metric.MeterOption) metric.Meter {
+	p.meter.scope = name
+	return p.meter
+}
+
+func newMeterProvider() (metric.MeterProvider, *recordingMeter) {
+	m := &recordingMeter{measurements: map[string][]measurement{}}
+	return recordingMeterProvider{meter: m}, m
+}
+
+func TestInstrumentation(t *testing.T) {
+	tp, spans := newTracerProvider()
+	mp, meter := newMeterProvider()
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{
+		TracerProvider: tp,
+		MeterProvider:  mp,
+		Propagator:     propagation.TraceContext{},
+	}))
+
+	var handlerSpan trace.SpanContext
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID   string `path:"id"`
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*struct{ Body string }, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		if input.ID == "broken" {
+			return nil, huma.Error500InternalServerError("broken")
+		}
+		return &struct{ Body string }{"hello"}, nil
+	})
+
+	resp := api.Put("/things/abc",
+		"Host: example.com:8080",
+		"Content-Length: 15",
+		"Traceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		strings.NewReader(`{"name":"abc"}`+"\n"),
+	)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "put-thing", span.name)
+	assert.Equal(t, trace.SpanKindServer, span.kind)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.sc.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", span.parent.SpanID().String())
+	assert.Equal(t, span.sc.SpanID(), handlerSpan.SpanID())
+
+	attrs := attribute.NewSet(span.attrs...)
+	for key, expected := range map[attribute.Key]any{
+		"http.request.method":       "PUT",
+		"http.route":                "/things/{id}",
+		"server.address":            "example.com",
+		"url.path":                  "/things/abc",
+		"http.response.status_code": int64(200),
+	} {
+		v, ok := attrs.Value(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, expected, v.AsInterface(), key)
+	}
+
+	resp = api.Put("/things/broken", map[string]any{"name": "abc"})
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	ended = spans.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, codes.Error, ended[1].status)
+	assert.False(t, ended[1].parent.IsValid())
+
+	assert.Equal(t, ScopeName, meter.scope)
+	assert.Len(t, meter.measurements["http.server.request.duration"], 2)
+
+	sizes := meter.measurements["http.server.response.body.size"]
+	require.Len(t, sizes, 2)
+	for _, m := range sizes {
+		status, _ := m.attrs.Value("http.response.status_code")
+		if status.AsInt64() == 200 {
+			assert.EqualValues(t, len(`"hello"`+"\n"), m.value)
+		}
+	}
+
+	requestSizes := meter.measurements["http.server.request.body.size"]
+	require.Len(t, requestSizes, 1)
+	assert.EqualValues(t, 15, requestSizes[0].value)
+}
+
+func TestSpanNameFallback(t *testing.T) {
+	tp, spans := newTracerProvider()
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{
+		TracerProvider: tp,
+	}))
+
+	api.Adapter().Handle(&huma.Operation{Method: http.MethodGet, Path: "/raw"}, api.Middlewares().Handler(func(ctx huma.Context) {
+		ctx.BodyWriter().Write([]byte("raw"))
+	}))
+
+	resp := api.Get("/raw")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	require.Len(t, spans.Ended(), 1)
+	assert.Equal(t, "GET /raw", spans.Ended()[0].name)
+}
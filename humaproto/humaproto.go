@@ -0,0 +1,310 @@
+// Package humaproto describes messages generated by `protoc-gen-go` so they
+// can be used directly as request & response bodies, which makes it possible
+// to expose a documented REST facade in front of gRPC services without
+// duplicating the models.
+//
+// Schemas are built from the message descriptors rather than the Go structs,
+// so they match the canonical protobuf JSON mapping used by `protojson`:
+// oneofs become `oneOf` constraints, 64-bit integers are strings, enums use
+// their value names, and well-known types like `google.protobuf.Timestamp`,
+// `google.protobuf.Duration`, and `google.protobuf.Struct` are described by
+// their JSON representation.
+//
+//	config := huma.DefaultConfig("My API", "1.0.0")
+//	config.Formats["application/json"] = humaproto.Format(humaproto.Options{}, huma.DefaultJSONFormat)
+//	config.Formats["json"] = config.Formats["application/json"]
+//
+//	humaproto.Register(config.Components.Schemas, humaproto.Options{}, &pb.Thing{})
+package humaproto
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/danielgtaylor/huma/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// Options configures how messages are described & serialized. The same
+// options should be passed to both `Register` and `Format`.
+type Options struct {
+	// UseProtoNames uses the field names from the `.proto` file, e.g.
+	// `display_name`, rather than the lowerCamelCase JSON names, e.g.
+	// `displayName`.
+	UseProtoNames bool
+}
+
+// Register registers schemas for the given messages and all the messages they
+// reference with the registry, which are then used whenever the message types
+// are found in operation inputs & outputs. Call it before registering any
+// operations.
+func Register(r huma.Registry, opts Options, msgs ...proto.Message) {
+	seen := map[protoreflect.FullName]bool{}
+	for _, msg := range msgs {
+		register(r, opts, msg.ProtoReflect(), seen)
+	}
+}
+
+func register(r huma.Registry, opts Options, m protoreflect.Message, seen map[protoreflect.FullName]bool) {
+	md := m.Descriptor()
+	if seen[md.FullName()] || wellKnown(md) != nil {
+		return
+	}
+	seen[md.FullName()] = true
+
+	fields := md.Fields()
+	names := make([]string, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		names = append(names, opts.name(fd))
+		if sub := fieldMessage(m, fd); sub != nil {
+			register(r, opts, sub, seen)
+		}
+	}
+
+	huma.RegisterGeneratedSchema(r, reflect.TypeOf(m.Interface()), names, nil, func(r huma.Registry) *huma.Schema {
+		return opts.messageSchema(r, m)
+	})
+}
+
+// fieldMessage returns a new message of the field's message type, or nil if
+// the field does not contain messages.
+func fieldMessage(m protoreflect.Message, fd protoreflect.FieldDescriptor) protoreflect.Message {
+	switch {
+	case fd.IsMap():
+		if fd.MapValue().Message() != nil {
+			return m.NewField(fd).Map().NewValue().Message()
+		}
+	case fd.IsList():
+		if fd.Message() != nil {
+			return m.NewField(fd).List().NewElement().Message()
+		}
+	case fd.Message() != nil:
+		return m.NewField(fd).Message()
+	}
+	return nil
+}
+
+func (o Options) name(fd protoreflect.FieldDescriptor) string {
+	if o.UseProtoNames {
+		return string(fd.Name())
+	}
+	return fd.JSONName()
+}
+
+// messageSchema returns the object schema for a message.
+func (o Options) messageSchema(r huma.Registry, m protoreflect.Message) *huma.Schema {
+	md := m.Descriptor()
+	s := &huma.Schema{
+		Type:                 huma.TypeObject,
+		Properties:           map[string]*huma.Schema{},
+		AdditionalProperties: false,
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := o.name(fd)
+		s.Properties[name] = o.fieldSchema(r, m, fd)
+		if fd.Cardinality() == protoreflect.Required {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	oneofs := md.Oneofs()
+	groups := []*huma.Schema{}
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if od.IsSynthetic() {
+			// Proto3 `optional` fields are only oneofs internally.
+			continue
+		}
+		groups = append(groups, o.oneofSchema(od))
+	}
+	if len(groups) == 1 {
+		s.OneOf = groups[0].OneOf
+	} else if len(groups) > 1 {
+		s.AllOf = groups
+	}
+
+	return s
+}
+
+// oneofSchema returns a schema allowing at most one of the oneof's fields to
+// be set. Exactly one of the alternatives matches: only one of the fields is
+// present, or none of them are.
+func (o Options) oneofSchema(od protoreflect.OneofDescriptor) *huma.Schema {
+	fields := od.Fields()
+	present := make([]*huma.Schema, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		name := o.name(fields.Get(i))
+		present = append(present, &huma.Schema{
+			Type:       huma.TypeObject,
+			Properties: map[string]*huma.Schema{name: {}},
+			Required:   []string{name},
+		})
+	}
+	return &huma.Schema{
+		OneOf: append(present, &huma.Schema{
+			Not: &huma.Schema{AnyOf: present},
+		}),
+	}
+}
+
+// fieldSchema returns the schema for a field, including lists & maps.
+func (o Options) fieldSchema(r huma.Registry, m protoreflect.Message, fd protoreflect.FieldDescriptor) *huma.Schema {
+	switch {
+	case fd.IsMap():
+		// Map keys are always strings in JSON.
+		return &huma.Schema{
+			Type:                 huma.TypeObject,
+			AdditionalProperties: o.valueSchema(r, fieldMessage(m, fd), fd.MapValue()),
+		}
+	case fd.IsList():
+		return &huma.Schema{
+			Type:  huma.TypeArray,
+			Items: o.valueSchema(r, fieldMessage(m, fd), fd),
+		}
+	}
+	return o.valueSchema(r, fieldMessage(m, fd), fd)
+}
+
+// valueSchema returns the schema for a single value of a field. The message
+// is a new message of the field's type for message fields.
+func (o Options) valueSchema(r huma.Registry, m protoreflect.Message, fd protoreflect.FieldDescriptor) *huma.Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &huma.Schema{Type: huma.TypeBoolean}
+	case protoreflect.StringKind:
+		return &huma.Schema{Type: huma.TypeString}
+	case protoreflect.BytesKind:
+		return &huma.Schema{Type: huma.TypeString, ContentEncoding: "base64"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &huma.Schema{Type: huma.TypeInteger, Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		minZero := 0.0
+		return &huma.Schema{Type: huma.TypeInteger, Format: "int32", Minimum: &minZero}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// 64-bit integers are strings in JSON to prevent losing precision.
+		return &huma.Schema{Type: huma.TypeString, Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &huma.Schema{Type: huma.TypeString, Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &huma.Schema{Type: huma.TypeNumber, Format: "float"}
+	case protoreflect.DoubleKind:
+		return &huma.Schema{Type: huma.TypeNumber, Format: "double"}
+	case protoreflect.EnumKind:
+		ed := fd.Enum()
+		if ed.FullName() == "google.protobuf.NullValue" {
+			return &huma.Schema{Enum: []any{nil}}
+		}
+		s := &huma.Schema{Type: huma.TypeString}
+		values := ed.Values()
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+		return s
+	}
+
+	if s := wellKnown(m.Descriptor()); s != nil {
+		return s
+	}
+	return r.Schema(reflect.TypeOf(m.Interface()), true, "")
+}
+
+// wellKnown returns the schema for the JSON representation of a well-known
+// type, or nil if the message is not one.
+func wellKnown(md protoreflect.MessageDescriptor) *huma.Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &huma.Schema{Type: huma.TypeString, Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &huma.Schema{Type: huma.TypeString, Pattern: `^-?[0-9]+(\.[0-9]{1,9})?s$`, Examples: []any{"1.5s"}}
+	case "google.protobuf.FieldMask":
+		return &huma.Schema{Type: huma.TypeString, Examples: []any{"name,description"}}
+	case "google.protobuf.Struct":
+		return &huma.Schema{Type: huma.TypeObject, AdditionalProperties: true}
+	case "google.protobuf.Value":
+		return &huma.Schema{}
+	case "google.protobuf.ListValue":
+		return &huma.Schema{Type: huma.TypeArray, Items: &huma.Schema{}}
+	case "google.protobuf.Empty":
+		return &huma.Schema{Type: huma.TypeObject, Properties: map[string]*huma.Schema{}, AdditionalProperties: false}
+	case "google.protobuf.Any":
+		return &huma.Schema{
+			Type: huma.TypeObject,
+			Properties: map[string]*huma.Schema{
+				"@type": {Type: huma.TypeString, Format: "uri-reference"},
+			},
+			Required:             []string{"@type"},
+			AdditionalProperties: true,
+		}
+	case "google.protobuf.BoolValue":
+		return &huma.Schema{Type: huma.TypeBoolean, Nullable: true}
+	case "google.protobuf.StringValue":
+		return &huma.Schema{Type: huma.TypeString, Nullable: true}
+	case "google.protobuf.BytesValue":
+		return &huma.Schema{Type: huma.TypeString, ContentEncoding: "base64", Nullable: true}
+	case "google.protobuf.Int32Value":
+		return &huma.Schema{Type: huma.TypeInteger, Format: "int32", Nullable: true}
+	case "google.protobuf.UInt32Value":
+		minZero := 0.0
+		return &huma.Schema{Type: huma.TypeInteger, Format: "int32", Minimum: &minZero, Nullable: true}
+	case "google.protobuf.Int64Value":
+		return &huma.Schema{Type: huma.TypeString, Format: "int64", Nullable: true}
+	case "google.protobuf.UInt64Value":
+		return &huma.Schema{Type: huma.TypeString, Format: "uint64", Nullable: true}
+	case "google.protobuf.FloatValue":
+		return &huma.Schema{Type: huma.TypeNumber, Format: "float", Nullable: true}
+	case "google.protobuf.DoubleValue":
+		return &huma.Schema{Type: huma.TypeNumber, Format: "double", Nullable: true}
+	}
+	return nil
+}
+
+// Format returns a format which uses `protojson` to marshal & unmarshal
+// messages and the fallback format for all other values, like error models
+// and the generic values used for validation.
+func Format(opts Options, fallback huma.Format) huma.Format {
+	marshal := protojson.MarshalOptions{UseProtoNames: opts.UseProtoNames}
+	return huma.Format{
+		Marshal: func(w io.Writer, v any) error {
+			msg, ok := v.(proto.Message)
+			if !ok {
+				return fallback.Marshal(w, v)
+			}
+			b, err := marshal.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(append(b, '\n'))
+			return err
+		},
+		Unmarshal: func(data []byte, v any) error {
+			if msg := target(v); msg != nil {
+				return protojson.Unmarshal(data, msg)
+			}
+			return fallback.Unmarshal(data, v)
+		},
+	}
+}
+
+// target returns the message to unmarshal into for a pointer to a message or
+// a pointer to a message pointer, which is allocated if needed.
+func target(v any) proto.Message {
+	if msg, ok := v.(proto.Message); ok {
+		return msg
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Pointer || !rv.Elem().Type().Implements(messageType) {
+		return nil
+	}
+	if rv.Elem().IsNil() {
+		rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+	}
+	return rv.Elem().Interface().(proto.Message)
+}
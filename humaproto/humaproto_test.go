@@ -0,0 +1,157 @@
+package humaproto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humaproto/internal/testpb"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ThingInput struct {
+	ID   string `path:"id"`
+	Body *testpb.Thing
+}
+
+type ThingOutput struct {
+	Body *testpb.Thing
+}
+
+func newAPI(t *testing.T, opts Options) humatest.TestAPI {
+	config := huma.DefaultConfig("Test API", "1.0.0")
+	config.Formats["application/json"] = Format(opts, huma.DefaultJSONFormat)
+	config.Formats["json"] = config.Formats["application/json"]
+	Register(config.Components.Schemas, opts, &testpb.Thing{})
+	_, api := humatest.New(t, config)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "put-thing",
+		Method:      http.MethodPut,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *ThingInput) (*ThingOutput, error) {
+		input.Body.Id = input.ID
+		input.Body.CreatedAt = timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		return &ThingOutput{Body: input.Body}, nil
+	})
+
+	return api
+}
+
+func TestSchema(t *testing.T) {
+	api := newAPI(t, Options{})
+
+	b, _ := json.Marshal(api.OpenAPI().Components.Schemas.Map()["Thing"])
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "string"},
+			"displayName": {"type": "string"},
+			"count": {"type": "integer", "format": "int32"},
+			"size": {"type": "string", "format": "int64"},
+			"score": {"type": "number", "format": "double"},
+			"enabled": {"type": "boolean"},
+			"data": {"type": "string", "contentEncoding": "base64"},
+			"color": {"type": "string", "enum": ["COLOR_UNSPECIFIED", "COLOR_RED", "COLOR_BLUE"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"counts": {"type": "object", "additionalProperties": {"type": "integer", "format": "int32"}},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"ttl": {"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]{1,9})?s$", "examples": ["1.5s"]},
+			"metadata": {"type": "object", "additionalProperties": true},
+			"nickname": {"type": ["string", "null"]},
+			"owner": {"$ref": "#/components/schemas/Thing_Owner"},
+			"editors": {"type": "array", "items": {"$ref": "#/components/schemas/Thing_Owner"}},
+			"url": {"type": "string"},
+			"uploader": {"$ref": "#/components/schemas/Thing_Owner"},
+			"note": {"type": "string"}
+		},
+		"oneOf": [
+			{"type": "object", "properties": {"url": {}}, "required": ["url"]},
+			{"type": "object", "properties": {"uploader": {}}, "required": ["uploader"]},
+			{"not": {"anyOf": [
+				{"type": "object", "properties": {"url": {}}, "required": ["url"]},
+				{"type": "object", "properties": {"uploader": {}}, "required": ["uploader"]}
+			]}}
+		]
+	}`, string(b))
+
+	b, _ = json.Marshal(api.OpenAPI().Components.Schemas.Map()["Thing_Owner"])
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string"},
+			"email": {"type": "string"}
+		}
+	}`, string(b))
+}
+
+func TestRoundTrip(t *testing.T) {
+	api := newAPI(t, Options{})
+
+	resp := api.Put("/things/abc", map[string]any{
+		"displayName": "Thing",
+		"size":        "12345678901234",
+		"color":       "COLOR_RED",
+		"counts":      map[string]any{"a": 1},
+		"ttl":         "1.5s",
+		"metadata":    map[string]any{"foo": []any{"bar"}},
+		"nickname":    "thingy",
+		"uploader":    map[string]any{"name": "Alice"},
+	})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Empty(t, resp.Header().Get("Link"))
+	assert.JSONEq(t, `{
+		"id": "abc",
+		"displayName": "Thing",
+		"size": "12345678901234",
+		"color": "COLOR_RED",
+		"counts": {"a": 1},
+		"createdAt": "2024-01-02T03:04:05Z",
+		"ttl": "1.500s",
+		"metadata": {"foo": ["bar"]},
+		"nickname": "thingy",
+		"uploader": {"name": "Alice"}
+	}`, resp.Body.String())
+}
+
+func TestValidation(t *testing.T) {
+	api := newAPI(t, Options{})
+
+	for name, body := range map[string]string{
+		"oneof":   `{"url": "https://example.com", "uploader": {"name": "Alice"}}`,
+		"int64":   `{"size": 5}`,
+		"enum":    `{"color": "COLOR_GREEN"}`,
+		"unknown": `{"unknown": true}`,
+		"nested":  `{"owner": {"name": 5}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := api.Put("/things/abc", strings.NewReader(body))
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.Code, resp.Body.String())
+		})
+	}
+}
+
+func TestProtoNames(t *testing.T) {
+	api := newAPI(t, Options{UseProtoNames: true})
+
+	schema := api.OpenAPI().Components.Schemas.Map()["Thing"]
+	assert.Contains(t, schema.Properties, "display_name")
+	assert.NotContains(t, schema.Properties, "displayName")
+
+	resp := api.Put("/things/abc", map[string]any{"display_name": "Thing"})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, "Thing", body["display_name"])
+	assert.Contains(t, body, "created_at")
+}
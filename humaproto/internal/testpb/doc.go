@@ -0,0 +1,5 @@
+// Package testpb contains protobuf messages used to test the `humaproto`
+// package.
+package testpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative thing.proto
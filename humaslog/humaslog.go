@@ -0,0 +1,108 @@
+//go:build go1.21
+
+// Package humaslog provides structured request logging for Huma APIs using the
+// standard library's `log/slog` package.
+//
+// Each request is logged once it completes, including the method, operation
+// path template, operation ID, response status, number of body bytes written,
+// and latency. Handlers can attach request-scoped attributes via `With`.
+package humaslog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/middleware"
+)
+
+type contextKey struct{}
+
+// entry holds request-scoped attributes added by handlers.
+type entry struct {
+	attrs []slog.Attr
+}
+
+// With adds attributes to the log entry for the current request. It is a
+// no-op if the context is not from a request handled by the logging
+// middleware.
+//
+//	humaslog.With(ctx, slog.String("user", user.ID))
+func With(ctx context.Context, attrs ...slog.Attr) {
+	if e, ok := ctx.Value(contextKey{}).(*entry); ok {
+		e.attrs = append(e.attrs, attrs...)
+	}
+}
+
+// New creates a middleware which logs every request to the given logger, or
+// `slog.Default()` if nil. Requests which result in a server error are
+// logged at the error level, while all others are logged at the info level.
+//
+//	api.UseMiddleware(humaslog.New(logger))
+func New(logger *slog.Logger) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+
+		start := time.Now()
+		e := &entry{}
+		w := &writer{Wrapper: middleware.Wrap(huma.WithValue(ctx, contextKey{}, e))}
+		next(w)
+
+		status := w.status
+		if status == 0 {
+			// Nothing explicitly set the status, so the server sends 200 OK.
+			status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		attrs := make([]slog.Attr, 0, 6+len(e.attrs))
+		attrs = append(attrs, slog.String("method", ctx.Method()))
+		if op := ctx.Operation(); op != nil {
+			attrs = append(attrs,
+				slog.String("path", op.Path),
+				slog.String("operation", op.OperationID),
+			)
+		}
+		attrs = append(attrs,
+			slog.Int("status", status),
+			slog.Int64("bytes", w.bytes),
+			slog.Duration("duration", time.Since(start)),
+		)
+		attrs = append(attrs, e.attrs...)
+
+		l.LogAttrs(ctx.Context(), level, "request", attrs...)
+	}
+}
+
+// writer wraps a `huma.Context` to record the response status and the number
+// of body bytes written.
+type writer struct {
+	middleware.Wrapper
+	status int
+	bytes  int64
+}
+
+func (w *writer) SetStatus(code int) {
+	w.status = code
+	w.Unwrap().SetStatus(code)
+}
+
+func (w *writer) BodyWriter() io.Writer {
+	return w
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.Unwrap().BodyWriter().Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
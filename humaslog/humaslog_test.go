@@ -0,0 +1,105 @@
+//go:build go1.21
+
+package humaslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(logger))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-thing",
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+	}, func(ctx context.Context, input *struct {
+		ID string `path:"id"`
+	}) (*struct{ Body string }, error) {
+		With(ctx, slog.String("user", "alice"))
+		if input.ID == "missing" {
+			return nil, huma.Error404NotFound("not found")
+		}
+		if input.ID == "broken" {
+			return nil, huma.Error500InternalServerError("broken")
+		}
+		return &struct{ Body string }{"hello"}, nil
+	})
+
+	logged := func() map[string]any {
+		t.Helper()
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		buf.Reset()
+		return entry
+	}
+
+	resp := api.Get("/things/abc")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	entry := logged()
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "request", entry["msg"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/things/{id}", entry["path"])
+	assert.Equal(t, "get-thing", entry["operation"])
+	assert.EqualValues(t, 200, entry["status"])
+	assert.EqualValues(t, resp.Body.Len(), entry["bytes"])
+	assert.Contains(t, entry, "duration")
+	assert.Equal(t, "alice", entry["user"])
+
+	resp = api.Get("/things/missing")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	entry = logged()
+	assert.Equal(t, "INFO", entry["level"])
+	assert.EqualValues(t, 404, entry["status"])
+
+	resp = api.Get("/things/broken")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	entry = logged()
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.EqualValues(t, 500, entry["status"])
+}
+
+func TestLoggingStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, nil))
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(logger))
+
+	huma.Get(api, "/stream", func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				w := ctx.BodyWriter()
+				w.Write([]byte("hello"))
+				w.(http.Flusher).Flush()
+			},
+		}, nil
+	})
+
+	resp := api.Get("/stream")
+	assert.True(t, resp.Flushed)
+	assert.True(t, strings.Contains(buf.String(), "status=200"), buf.String())
+	assert.True(t, strings.Contains(buf.String(), "bytes=5"), buf.String())
+}
+
+func TestWithOutsideRequest(t *testing.T) {
+	assert.NotPanics(t, func() {
+		With(context.Background(), slog.String("foo", "bar"))
+	})
+}
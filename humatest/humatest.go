@@ -43,7 +43,8 @@ type TestAPI interface {
 	// Do a request against the API. Args, if provided, should be string headers
 	// like `Content-Type: application/json`, an `io.Reader` for the request
 	// body, or a slice/map/struct which will be serialized to JSON and sent
-	// as the request body. Anything else will panic.
+	// as the request body. Anything else will panic. Headers passed more than
+	// once are sent with each value.
 	Do(method, path string, args ...any) *httptest.ResponseRecorder
 
 	// Get performs a GET request against the API. Args, if provided, should be
@@ -141,13 +142,24 @@ func (a *testAPI) Do(method, path string, args ...any) *httptest.ResponseRecorde
 	if isJSON {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	seen := map[string]bool{}
 	for _, arg := range args {
 		if s, ok := arg.(string); ok {
-			parts := strings.Split(s, ":")
-			req.Header.Set(parts[0], strings.TrimSpace(strings.Join(parts[1:], ":")))
+			name, value, _ := strings.Cut(s, ":")
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			value = strings.TrimSpace(value)
+
+			// The first value replaces any default, like the JSON content type,
+			// while repeated headers are all sent.
+			if seen[name] {
+				req.Header.Add(name, value)
+			} else {
+				req.Header.Set(name, value)
+			}
+			seen[name] = true
 
-			if strings.ToLower(parts[0]) == "host" {
-				req.Host = strings.TrimSpace(parts[1])
+			if name == "Host" {
+				req.Host = value
 			}
 		}
 	}
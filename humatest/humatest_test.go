@@ -70,6 +70,35 @@ func TestHumaTestUtils(t *testing.T) {
 	})
 }
 
+func TestHumaTestHeaders(t *testing.T) {
+	_, api := New(t)
+
+	called := false
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		assert.Equal(t, "example.com:8080", ctx.Host())
+		values := []string{}
+		ctx.EachHeader(func(name, value string) {
+			if name == "X-Multi" {
+				values = append(values, value)
+			}
+		})
+		assert.Equal(t, []string{"a", "b:c"}, values)
+		called = true
+		next(ctx)
+	})
+
+	huma.Register(api, huma.Operation{
+		Method: http.MethodGet,
+		Path:   "/headers",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	w := api.Get("/headers", "Host: example.com:8080", "x-multi: a", "X-Multi: b:c")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.True(t, called)
+}
+
 func TestContext(t *testing.T) {
 	op := &huma.Operation{}
 	r, _ := http.NewRequest(http.MethodGet, "/", nil)
@@ -0,0 +1,143 @@
+// Package gocode contains helpers shared by the code generators, like
+// `humaclient` and `humagen`, for writing Go source which refers to types
+// from other packages.
+package gocode
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Imports tracks the packages referenced by generated code along with a
+// unique name for each.
+type Imports struct {
+	// Local is the path of the package the code is generated for. Its types
+	// are referenced without a package name.
+	Local string
+
+	paths map[string]string
+	used  map[string]bool
+}
+
+// NewImports creates an import set which avoids the given reserved names,
+// e.g. the generated package's own name or identifiers it declares. Packages
+// the generated code always refers to should be added with `Add` instead, so
+// their types map to the same name.
+func NewImports(reserved ...string) *Imports {
+	s := &Imports{paths: map[string]string{}, used: map[string]bool{}}
+	for _, name := range reserved {
+		s.used[name] = true
+	}
+	return s
+}
+
+// Add adds a package using the given name.
+func (s *Imports) Add(pkgPath, name string) {
+	s.used[name] = true
+	s.paths[pkgPath] = name
+}
+
+// Name returns the name used to refer to the package with the given path,
+// adding it if needed.
+func (s *Imports) Name(pkgPath string) string {
+	if n, ok := s.paths[pkgPath]; ok {
+		return n
+	}
+	base := strings.NewReplacer("-", "", ".", "").Replace(baseName(pkgPath))
+	n := base
+	for i := 2; s.used[n]; i++ {
+		n = base + strconv.Itoa(i)
+	}
+	s.Add(pkgPath, n)
+	return n
+}
+
+// Sorted returns the paths of the imported packages in sorted order.
+func (s *Imports) Sorted() []string {
+	paths := make([]string, 0, len(s.paths))
+	for p := range s.paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Decl returns the import declaration for the packages, with the standard
+// library in its own group like `goimports` writes it.
+func (s *Imports) Decl() string {
+	var std, other strings.Builder
+	for _, p := range s.Sorted() {
+		b := &other
+		if !strings.Contains(strings.Split(p, "/")[0], ".") {
+			b = &std
+		}
+		b.WriteByte('\t')
+		if n := s.paths[p]; n != baseName(p) {
+			b.WriteString(n + " ")
+		}
+		b.WriteString(strconv.Quote(p) + "\n")
+	}
+	if std.Len() > 0 && other.Len() > 0 {
+		std.WriteByte('\n')
+	}
+	return "import (\n" + std.String() + other.String() + ")\n"
+}
+
+// baseName returns the last element of the package path, skipping a major
+// version suffix like `/v2`.
+func baseName(pkgPath string) string {
+	base := path.Base(pkgPath)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = path.Base(path.Dir(pkgPath))
+	}
+	return base
+}
+
+// TypeExpr returns a Go expression for the type, adding any imports needed.
+func (s *Imports) TypeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			// Built-in type like `string`.
+			return t.Name(), nil
+		}
+		if strings.ContainsAny(t.Name(), "[]") {
+			return "", fmt.Errorf("generic type %s is not supported", t.Name())
+		}
+		if t.PkgPath() == s.Local {
+			return t.Name(), nil
+		}
+		if t.PkgPath() == "main" || strings.HasSuffix(t.PkgPath(), "/main") {
+			return "", fmt.Errorf("type %s is in package main which cannot be imported", t.Name())
+		}
+		return s.Name(t.PkgPath()) + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := s.TypeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		elem, err := s.TypeExpr(t.Elem())
+		return "[]" + elem, err
+	case reflect.Map:
+		key, err := s.TypeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := s.TypeExpr(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case reflect.Struct:
+		if t.NumField() == 0 {
+			return "struct{}", nil
+		}
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any", nil
+		}
+	}
+	return "", fmt.Errorf("unnamed type %s is not supported, use a named type", t)
+}
@@ -0,0 +1,61 @@
+package gocode
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type local struct{}
+
+func TestTypeExpr(t *testing.T) {
+	s := NewImports("http")
+	s.Local = reflect.TypeOf(local{}).PkgPath()
+
+	for _, item := range []struct {
+		value any
+		expr  string
+	}{
+		{"", "string"},
+		{local{}, "local"},
+		{&time.Time{}, "*time.Time"},
+		{[]http.Header{}, "[]http2.Header"},
+		{map[string]any{}, "map[string]any"},
+		{struct{}{}, "struct{}"},
+	} {
+		expr, err := s.TypeExpr(reflect.TypeOf(item.value))
+		require.NoError(t, err)
+		assert.Equal(t, item.expr, expr)
+	}
+	assert.Equal(t, []string{"net/http", "time"}, s.Sorted())
+	assert.Equal(t, "http2", s.Name("net/http"))
+
+	_, err := s.TypeExpr(reflect.TypeOf(struct{ Value string }{}))
+	assert.ErrorContains(t, err, "unnamed type")
+}
+
+func TestDecl(t *testing.T) {
+	s := NewImports("client")
+	s.Add("context", "context")
+	s.Add("github.com/danielgtaylor/huma/v2/humaclient", "humaclient")
+
+	// Packages which were added map to their name instead of a new one.
+	assert.Equal(t, "humaclient", s.Name("github.com/danielgtaylor/huma/v2/humaclient"))
+	assert.Equal(t, "huma", s.Name("github.com/danielgtaylor/huma/v2"))
+	assert.Equal(t, "client2", s.Name("example.com/client"))
+	assert.Equal(t, "http", s.Name("net/http"))
+
+	assert.Equal(t, `import (
+	"context"
+	"net/http"
+
+	client2 "example.com/client"
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humaclient"
+)
+`, s.Decl())
+}
@@ -0,0 +1,198 @@
+package huma
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2/negotiation"
+)
+
+// DefaultLanguage is the language of the built-in validation messages. It is
+// used when a client's `Accept-Language` header does not match any registered
+// language.
+const DefaultLanguage = "en"
+
+// Validation message keys, used to register translations or override the
+// default phrasing of validation errors with `RegisterMessages`. Messages are
+// `fmt` format strings, and the comment for each key describes its arguments.
+const (
+	MsgValidationFailed      = "validation-failed"        // none
+	MsgNotMatch              = "not-match"                // none
+	MsgOneOfMultiple         = "one-of-multiple"          // none
+	MsgOneOfNone             = "one-of-none"              // none
+	MsgAnyOfNone             = "any-of-none"              // none
+	MsgDiscriminator         = "discriminator"            // property name, allowed values
+	MsgExpectedBoolean       = "expected-boolean"         // none
+	MsgExpectedNumber        = "expected-number"          // none
+	MsgExpectedInteger       = "expected-integer"         // none
+	MsgExpectedString        = "expected-string"          // none
+	MsgExpectedArray         = "expected-array"           // none
+	MsgExpectedObject        = "expected-object"          // none
+	MsgMinimum               = "minimum"                  // minimum
+	MsgExclusiveMinimum      = "exclusive-minimum"        // exclusive minimum
+	MsgMaximum               = "maximum"                  // maximum
+	MsgExclusiveMaximum      = "exclusive-maximum"        // exclusive maximum
+	MsgMultipleOf            = "multiple-of"              // multiple
+	MsgMinLength             = "min-length"               // minimum length
+	MsgMaxLength             = "max-length"               // maximum length
+	MsgPattern               = "pattern"                  // pattern
+	MsgPatternDescription    = "pattern-description"      // pattern description
+	MsgFormat                = "format"                   // format name, format validator message
+	MsgBase64                = "base64"                   // none
+	MsgEnum                  = "enum"                     // allowed values
+	MsgMinItems              = "min-items"                // minimum items
+	MsgMaxItems              = "max-items"                // maximum items
+	MsgUniqueItems           = "unique-items"             // none
+	MsgMinProperties         = "min-properties"           // minimum properties
+	MsgMaxProperties         = "max-properties"           // maximum properties
+	MsgWriteOnly             = "write-only"               // none
+	MsgRequired              = "required"                 // property name
+	MsgDependentRequired     = "dependent-required"       // dependent property name, property name
+	MsgUnexpectedProperty    = "unexpected-property"      // none
+	MsgParamRequired         = "param-required"           // parameter location, e.g. `query`
+	MsgInvalidInteger        = "invalid-integer"          // none
+	MsgInvalidFloat          = "invalid-float"            // none
+	MsgInvalidBoolean        = "invalid-boolean"          // none
+	MsgInvalidDateTimeFormat = "invalid-date-time-format" // time format
+)
+
+var messages = map[string]map[string]string{
+	DefaultLanguage: {
+		MsgValidationFailed:      "validation failed",
+		MsgNotMatch:              "expected value to not match schema",
+		MsgOneOfMultiple:         "expected value to match exactly one schema but matched multiple",
+		MsgOneOfNone:             "expected value to match exactly one schema but matched none",
+		MsgAnyOfNone:             "expected value to match at least one schema but matched none",
+		MsgDiscriminator:         "expected property %s to be one of \"%s\"",
+		MsgExpectedBoolean:       "expected boolean",
+		MsgExpectedNumber:        "expected number",
+		MsgExpectedInteger:       "expected integer",
+		MsgExpectedString:        "expected string",
+		MsgExpectedArray:         "expected array",
+		MsgExpectedObject:        "expected object",
+		MsgMinimum:               "expected number >= %v",
+		MsgExclusiveMinimum:      "expected number > %v",
+		MsgMaximum:               "expected number <= %v",
+		MsgExclusiveMaximum:      "expected number < %v",
+		MsgMultipleOf:            "expected number to be a multiple of %v",
+		MsgMinLength:             "expected length >= %d",
+		MsgMaxLength:             "expected length <= %d",
+		MsgPattern:               "expected string to match pattern %s",
+		MsgPatternDescription:    "expected string to be %s",
+		MsgFormat:                "%[2]s",
+		MsgBase64:                "expected string to be base64 encoded",
+		MsgEnum:                  "expected value to be one of \"%s\"",
+		MsgMinItems:              "expected array length >= %d",
+		MsgMaxItems:              "expected array length <= %d",
+		MsgUniqueItems:           "expected array items to be unique",
+		MsgMinProperties:         "expected object with at least %d properties",
+		MsgMaxProperties:         "expected object with at most %d properties",
+		MsgWriteOnly:             "write only property is non-zero",
+		MsgRequired:              "expected required property %s to be present",
+		MsgDependentRequired:     "expected property %s to be present when %s is present",
+		MsgUnexpectedProperty:    "unexpected property",
+		MsgParamRequired:         "required %s parameter is missing",
+		MsgInvalidInteger:        "invalid integer",
+		MsgInvalidFloat:          "invalid float",
+		MsgInvalidBoolean:        "invalid boolean",
+		MsgInvalidDateTimeFormat: "invalid date/time for format %s",
+	},
+}
+
+// messageLanguages lists the registered languages, default first, for
+// negotiation with the client's `Accept-Language` header.
+var messageLanguages = []string{DefaultLanguage}
+
+// localized is set once any messages are registered, enabling translation
+// of validation errors when writing responses.
+var localized bool
+
+// RegisterMessages registers validation messages for a language, which are
+// used for validation errors when it is the best match for the client's
+// `Accept-Language` header. Messages are `fmt` format strings keyed by the
+// `Msg...` constants, and keys without a message use the default language.
+// Use explicit argument indexes like `%[2]s` to reorder or omit arguments.
+// Registering messages for `huma.DefaultLanguage` overrides the default
+// phrasing. Messages are not safe to register concurrently with validation,
+// so this should be done at startup before registering operations.
+//
+//	huma.RegisterMessages("de", map[string]string{
+//		huma.MsgValidationFailed: "Validierung fehlgeschlagen",
+//		huma.MsgExpectedInteger:  "Ganzzahl erwartet",
+//		huma.MsgMinLength:        "Länge muss mindestens %d sein",
+//	})
+func RegisterMessages(lang string, msgs map[string]string) {
+	catalog := messages[lang]
+	if catalog == nil {
+		catalog = map[string]string{}
+		messages[lang] = catalog
+		messageLanguages = append(messageLanguages, lang)
+	}
+	for key, msg := range msgs {
+		catalog[key] = msg
+	}
+	localized = true
+}
+
+// message formats the message for a key in the given language, falling back
+// to the default language.
+func message(lang, key string, args ...any) string {
+	format, ok := messages[lang][key]
+	if !ok {
+		format = messages[DefaultLanguage][key]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// addMessage adds an error with a message which can be translated into the
+// client's language later.
+func (r *ValidateResult) addMessage(path *PathBuffer, v any, msg, key string, args ...any) {
+	r.Errors = append(r.Errors, &ErrorDetail{
+		Message:  msg,
+		Location: path.String(),
+		Value:    v,
+		key:      key,
+		args:     args,
+	})
+}
+
+// joinValues formats allowed values like enums for messages.
+func joinValues(values []any) string {
+	return strings.Join(mapTo(values, func(v any) string {
+		return fmt.Sprintf("%v", v)
+	}), ", ")
+}
+
+// discriminatorValues returns the sorted discriminator property values.
+func discriminatorValues(d *Discriminator) string {
+	values := make([]string, 0, len(d.Mapping))
+	for value := range d.Mapping {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
+
+// localize translates the messages of validation errors into the best
+// language for the client's `Accept-Language` header and returns the
+// language along with the translated message for the key. Errors without a
+// message key, e.g. from resolvers, are left unchanged.
+func localize(header string, key string, errs []error) (string, string) {
+	if !localized {
+		return "", message(DefaultLanguage, key)
+	}
+	lang := negotiation.SelectLanguage(header, messageLanguages)
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	for _, err := range errs {
+		if detail, ok := err.(*ErrorDetail); ok && detail.key != "" {
+			detail.Message = message(lang, detail.key, detail.args...)
+		}
+	}
+	return lang, message(lang, key)
+}
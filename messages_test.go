@@ -0,0 +1,94 @@
+package huma_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+)
+
+func TestLocalizedMessages(t *testing.T) {
+	huma.RegisterMessages("de", map[string]string{
+		huma.MsgValidationFailed: "Validierung fehlgeschlagen",
+		huma.MsgInvalidInteger:   "ungültige Ganzzahl",
+		huma.MsgMinLength:        "Länge muss mindestens %d sein",
+		huma.MsgEnum:             "erwartet einen Wert aus \"%s\"",
+		huma.MsgRequired:         "Eigenschaft %s fehlt",
+	})
+
+	_, api := humatest.New(t)
+	huma.Register(api, huma.Operation{
+		Method: http.MethodPut,
+		Path:   "/things",
+	}, func(ctx context.Context, input *struct {
+		Count int `query:"count"`
+		Body  struct {
+			Name  string `json:"name" minLength:"3"`
+			Color string `json:"color,omitempty" enum:"red,blue"`
+			Email string `json:"email,omitempty" format:"email"`
+			Size  int    `json:"size" required:"true"`
+		}
+	}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	body := `{"name": "ab", "color": "green", "email": "nope"}`
+
+	for _, tc := range []struct {
+		lang            string
+		contentLanguage string
+		expected        []string
+	}{
+		{
+			lang:            "de-DE, en;q=0.5",
+			contentLanguage: "de",
+			expected: []string{
+				"Validierung fehlgeschlagen",
+				"ungültige Ganzzahl",
+				"Länge muss mindestens 3 sein",
+				`erwartet einen Wert aus "red, blue"`,
+				"Eigenschaft size fehlt",
+				// Untranslated messages use the default language.
+				"expected string to be RFC 5322 email",
+			},
+		},
+		{
+			lang:            "",
+			contentLanguage: "en",
+			expected: []string{
+				"validation failed",
+				"invalid integer",
+				"expected length >= 3",
+				`expected value to be one of "red, blue"`,
+				"expected required property size to be present",
+			},
+		},
+	} {
+		t.Run(tc.contentLanguage, func(t *testing.T) {
+			headers := []any{"Content-Type: application/json", strings.NewReader(body)}
+			if tc.lang != "" {
+				headers = append(headers, "Accept-Language: "+tc.lang)
+			}
+			resp := api.Put("/things?count=abc", headers...)
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			assert.Equal(t, tc.contentLanguage, resp.Header().Get("Content-Language"))
+
+			var decoded huma.ErrorModel
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+			messages := []string{decoded.Detail}
+			for _, detail := range decoded.Errors {
+				messages = append(messages, detail.Message)
+			}
+			for _, msg := range tc.expected {
+				assert.Contains(t, strings.Join(messages, "\n"), msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package middleware provides helpers for writing Huma middleware which wraps
+// the request context to observe or change the response.
+package middleware
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type humaContext = huma.Context
+
+// Wrapper wraps a `huma.Context`, passing every call through to it. Embed it
+// in a middleware's own context type and override only the methods needed.
+//
+// It also implements `http.Flusher`, `http.Hijacker`, and `SetWriteDeadline`
+// by forwarding them to the wrapped context's body writer, so a type which
+// embeds it can return itself from `BodyWriter` without hiding those from
+// handlers such as server sent events or websockets.
+//
+//	type counter struct {
+//		middleware.Wrapper
+//		bytes int64
+//	}
+//
+//	func (c *counter) BodyWriter() io.Writer {
+//		return c
+//	}
+//
+//	func (c *counter) Write(p []byte) (int, error) {
+//		n, err := c.Unwrap().BodyWriter().Write(p)
+//		c.bytes += int64(n)
+//		return n, err
+//	}
+type Wrapper struct {
+	humaContext
+}
+
+// Wrap returns a wrapper which passes every call through to the context.
+func Wrap(ctx huma.Context) Wrapper {
+	return Wrapper{ctx}
+}
+
+// Unwrap returns the wrapped context.
+func (w Wrapper) Unwrap() huma.Context {
+	return w.humaContext
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (w Wrapper) Flush() {
+	if f, ok := w.humaContext.BodyWriter().(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over the underlying connection if the writer supports it,
+// otherwise it returns `http.ErrNotSupported`.
+func (w Wrapper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.humaContext.BodyWriter().(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// SetWriteDeadline passes the deadline through to the underlying writer,
+// otherwise it returns `http.ErrNotSupported`.
+func (w Wrapper) SetWriteDeadline(t time.Time) error {
+	if d, ok := w.humaContext.BodyWriter().(interface{ SetWriteDeadline(time.Time) error }); ok {
+		return d.SetWriteDeadline(t)
+	}
+	return http.ErrNotSupported
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hijackWriter is a response writer which supports all optional interfaces.
+type hijackWriter struct {
+	*httptest.ResponseRecorder
+	deadline time.Time
+	hijacked bool
+}
+
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func (w *hijackWriter) SetWriteDeadline(t time.Time) error {
+	w.deadline = t
+	return nil
+}
+
+func TestWrapper(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	w := &hijackWriter{ResponseRecorder: httptest.NewRecorder()}
+	ctx := humatest.NewContext(&huma.Operation{}, req, w)
+
+	wrapped := Wrap(ctx)
+	assert.Equal(t, ctx, wrapped.Unwrap())
+	assert.Equal(t, ctx.Operation(), wrapped.Operation())
+
+	wrapped.Flush()
+	assert.True(t, w.Flushed)
+
+	_, _, err := wrapped.Hijack()
+	require.NoError(t, err)
+	assert.True(t, w.hijacked)
+
+	deadline := time.Now()
+	require.NoError(t, wrapped.SetWriteDeadline(deadline))
+	assert.Equal(t, deadline, w.deadline)
+
+	// Writers without support for the optional interfaces return errors.
+	wrapped = Wrap(humatest.NewContext(&huma.Operation{}, req, httptest.NewRecorder()))
+	wrapped.Flush()
+	_, _, err = wrapped.Hijack()
+	assert.ErrorIs(t, err, http.ErrNotSupported)
+	assert.ErrorIs(t, wrapped.SetWriteDeadline(deadline), http.ErrNotSupported)
+}
@@ -0,0 +1,208 @@
+// Package mock provides a middleware which responds to requests with example
+// responses generated from each operation's documented response schema.
+// This lets client developers work against an API contract before the
+// backend logic exists.
+//
+// Responses are built from schema `examples`, `default`, and `enum` values,
+// falling back to synthesized values which respect each schema's constraints.
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// New creates a middleware which writes an example response for the
+// operation's default success status instead of calling the handler. Add it
+// to the whole API to mock every operation, e.g. behind a command line flag:
+//
+//	if options.Mock {
+//		api.UseMiddleware(mock.New(api))
+//	}
+//
+// Or add it to a single operation, in which case no handler is needed:
+//
+//	huma.Register[struct{}, ThingOutput](api, huma.Operation{
+//		Method:      http.MethodGet,
+//		Path:        "/things/{id}",
+//		Middlewares: huma.Middlewares{mock.New(api)},
+//	}, nil)
+func New(api huma.API) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		if op == nil {
+			next(ctx)
+			return
+		}
+		Write(api, ctx, op)
+	}
+}
+
+// Write an example response for the given operation to the context. The
+// status is the operation's default status. Documented response headers are
+// set from their example or default values, and the body is generated from
+// the response schema and marshaled using content negotiation.
+func Write(api huma.API, ctx huma.Context, op *huma.Operation) {
+	status := op.DefaultStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	statusStr := strconv.Itoa(status)
+
+	resp := op.Responses[statusStr]
+	if resp == nil {
+		ctx.SetStatus(status)
+		return
+	}
+
+	registry := api.OpenAPI().Components.Schemas
+	for name, header := range resp.Headers {
+		if header == nil || header.Schema == nil {
+			continue
+		}
+		var v any
+		if len(header.Schema.Examples) > 0 {
+			v = header.Schema.Examples[0]
+		} else if header.Schema.Default != nil {
+			v = header.Schema.Default
+		} else {
+			continue
+		}
+		if s, ok := v.(string); ok {
+			ctx.SetHeader(name, s)
+		} else if b, err := json.Marshal(v); err == nil {
+			ctx.SetHeader(name, string(b))
+		}
+	}
+
+	var schema *huma.Schema
+	if mt := resp.Content["application/json"]; mt != nil {
+		schema = mt.Schema
+	} else {
+		for _, mt := range resp.Content {
+			if mt != nil && mt.Schema != nil {
+				schema = mt.Schema
+				break
+			}
+		}
+	}
+
+	if schema == nil {
+		ctx.SetStatus(status)
+		return
+	}
+
+	ct, err := api.Negotiate(ctx.Header("Accept"))
+	if err != nil {
+		huma.WriteErr(api, ctx, http.StatusNotAcceptable, "unable to marshal response", err)
+		return
+	}
+
+	body, err := api.Transform(ctx, statusStr, Value(registry, schema))
+	if err != nil {
+		huma.WriteErr(api, ctx, http.StatusInternalServerError, "error transforming response", err)
+		return
+	}
+
+	ctx.SetHeader("Content-Type", ct)
+	ctx.SetStatus(status)
+	api.Marshal(ctx.BodyWriter(), ct, body)
+}
+
+// Value generates an example value for a schema. Schema `examples`, then
+// `default`, then the first `enum` value are used if present, otherwise a
+// value is synthesized via `huma.SynthesizeExample` or built from the
+// schema's type. Write-only object properties and the
+// `$schema` link property are omitted. Recursive schemas are expanded once,
+// with optional recursive properties left out.
+func Value(registry huma.Registry, s *huma.Schema) any {
+	return value(registry, s, map[string]bool{})
+}
+
+func value(registry huma.Registry, s *huma.Schema, seen map[string]bool) any {
+	if s == nil {
+		return nil
+	}
+
+	if s.Ref != "" {
+		if seen[s.Ref] {
+			// Stop expanding recursive schemas.
+			return nil
+		}
+		ref := s.Ref
+		if s = registry.SchemaFromRef(ref); s == nil {
+			return nil
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+	}
+
+	if len(s.Examples) > 0 {
+		return s.Examples[0]
+	}
+
+	if s.Default != nil {
+		return s.Default
+	}
+
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	if len(s.OneOf) > 0 {
+		return value(registry, s.OneOf[0], seen)
+	}
+
+	if len(s.AnyOf) > 0 {
+		return value(registry, s.AnyOf[0], seen)
+	}
+
+	if v := huma.SynthesizeExample(s); v != nil {
+		return v
+	}
+
+	switch s.Type {
+	case huma.TypeObject:
+		obj := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			if prop == nil || prop.WriteOnly || name == "$schema" {
+				// The `$schema` link is only added for real response types.
+				continue
+			}
+			v := value(registry, prop, seen)
+			if v == nil && !required(s, name) {
+				continue
+			}
+			obj[name] = v
+		}
+		return obj
+	case huma.TypeArray:
+		if v := value(registry, s.Items, seen); v != nil {
+			return []any{v}
+		}
+		return []any{}
+	case huma.TypeString:
+		return "string"
+	case huma.TypeInteger:
+		return 0
+	case huma.TypeNumber:
+		return 0.0
+	case huma.TypeBoolean:
+		return true
+	}
+
+	return nil
+}
+
+// required returns whether the object schema requires the named property.
+func required(s *huma.Schema, name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Owner struct {
+	Name   string `json:"name" example:"Alice"`
+	Secret string `json:"secret" writeOnly:"true"`
+}
+
+type Thing struct {
+	ID       string    `json:"id" example:"abc123"`
+	Kind     string    `json:"kind" enum:"small,large"`
+	Count    int       `json:"count" default:"5"`
+	Price    float64   `json:"price"`
+	Active   bool      `json:"active"`
+	Tags     []string  `json:"tags"`
+	Owner    *Owner    `json:"owner"`
+	Parent   *Thing    `json:"parent,omitempty"`
+	Modified time.Time `json:"modified"`
+}
+
+type ThingOutput struct {
+	ETag string `header:"ETag" example:"\"abc\""`
+	Body Thing
+}
+
+func TestMockOperation(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+
+	huma.Register[struct{}, ThingOutput](api, huma.Operation{
+		Method:      http.MethodGet,
+		Path:        "/things/{id}",
+		Middlewares: huma.Middlewares{New(api)},
+	}, nil)
+
+	resp := api.Get("/things/foo")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `"abc"`, resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Header().Get("Content-Type"), "json")
+	assert.JSONEq(t, `{
+		"id": "abc123",
+		"kind": "small",
+		"count": 5,
+		"price": 1,
+		"active": true,
+		"tags": ["string"],
+		"owner": {"name": "Alice"},
+		"modified": "2024-01-01T12:00:00Z"
+	}`, resp.Body.String())
+}
+
+func TestMockAPI(t *testing.T) {
+	_, api := humatest.New(t, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(New(api))
+
+	huma.Post(api, "/things", func(ctx context.Context, input *struct {
+		Body Thing
+	}) (*struct{}, error) {
+		t.Fatal("handler should not be called")
+		return nil, nil
+	})
+
+	huma.Get(api, "/things", func(ctx context.Context, input *struct{}) (*struct {
+		Body []string `example:"a"`
+	}, error) {
+		t.Fatal("handler should not be called")
+		return nil, nil
+	})
+
+	resp := api.Post("/things")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	resp = api.Get("/things")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `["a"]`, resp.Body.String())
+}
@@ -58,62 +58,127 @@ func SelectQValueFast(header string, allowed []string) string {
 	best := ""
 	bestQ := 0.0
 
-	name := ""
-	start := -1
-	end := 0
-
-	for pos, char := range header {
-		// Format is like "a; q=0.5, b;q=1.0,c; q=0.3"
-		if char == ';' {
-			name = header[start : end+1]
-			start = -1
+	for len(header) > 0 {
+		// Format is like "a; q=0.5, b;q=1.0,c; q=0.3", so split off the next
+		// comma-separated entry and then its name from any params.
+		entry := header
+		header = ""
+		if i := strings.IndexByte(entry, ','); i != -1 {
+			entry, header = entry[:i], entry[i+1:]
+		}
+
+		name := entry
+		params := ""
+		if i := strings.IndexByte(entry, ';'); i != -1 {
+			name, params = entry[:i], entry[i+1:]
+		}
+		name = strings.Trim(name, " \t")
+
+		found := false
+		for _, n := range allowed {
+			if n == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			// Skip formats we don't support.
 			continue
 		}
 
-		if char == ',' || pos == len(header)-1 {
-			q := 1.0
-			if pos == len(header)-1 {
-				// This is the end, so it must be the name.
-				// Example: "application/yaml"
-				name = header[start : pos+1]
-			} else if name == "" {
-				// No name yet means we did not encounter a `;`.
-				// Example: "a, b, c"
-				name = header[start:pos]
-			} else {
-				if parsed, _ := strconv.ParseFloat(header[start+2:end+1], 64); parsed > 0 {
+		// Default weight to 1 if no value is passed. Other params, like a
+		// charset, may come before or after the weight.
+		q := 1.0
+		for len(params) > 0 {
+			param := params
+			params = ""
+			if i := strings.IndexByte(param, ';'); i != -1 {
+				param, params = param[:i], param[i+1:]
+			}
+			param = strings.Trim(param, " \t")
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
 					q = parsed
 				}
 			}
-			start = -1
+		}
 
-			found := false
-			for _, n := range allowed {
-				if n == name {
-					found = true
-					break
-				}
-			}
+		if q <= 0 {
+			// Explicitly not acceptable.
+			continue
+		}
 
-			if !found {
-				// Skip formats we don't support.
-				continue
-			}
+		if q > bestQ || (q == bestQ && name == allowed[0]) {
+			bestQ = q
+			best = name
+		}
+	}
+
+	return best
+}
+
+// SelectLanguage selects and returns the best language from the allowed set
+// given an `Accept-Language` header. Language tags are compared without
+// regard to case, and a more specific tag like `de-CH` matches an allowed
+// `de` when no better match exists. A `*` matches the first allowed
+// language. If nothing matches, returns an empty string.
+func SelectLanguage(header string, allowed []string) string {
+	best := ""
+	bestQ := 0.0
+
+	for len(header) > 0 {
+		entry := header
+		header = ""
+		if i := strings.IndexByte(entry, ','); i != -1 {
+			entry, header = entry[:i], entry[i+1:]
+		}
+
+		tag := entry
+		params := ""
+		if i := strings.IndexByte(entry, ';'); i != -1 {
+			tag, params = entry[:i], entry[i+1:]
+		}
+		tag = strings.Trim(tag, " \t")
 
-			if q > bestQ || (q == bestQ && name == allowed[0]) {
-				bestQ = q
-				best = name
+		q := 1.0
+		params = strings.Trim(params, " \t")
+		if strings.HasPrefix(params, "q=") {
+			if parsed, err := strconv.ParseFloat(params[2:], 64); err == nil {
+				q = parsed
 			}
+		}
+		if q <= bestQ {
+			// Earlier languages are preferred if there is a tie.
 			continue
 		}
 
-		if char != ' ' && char != '\t' {
-			end = pos
-			if start == -1 {
-				start = pos
-			}
+		if match := matchLanguage(tag, allowed); match != "" {
+			bestQ = q
+			best = match
 		}
 	}
 
 	return best
 }
+
+// matchLanguage returns the allowed language matching the tag, removing
+// subtags from the end of the tag until one matches.
+func matchLanguage(tag string, allowed []string) string {
+	if tag == "*" && len(allowed) > 0 {
+		return allowed[0]
+	}
+	for tag != "" {
+		for _, lang := range allowed {
+			if strings.EqualFold(lang, tag) {
+				return lang
+			}
+		}
+		i := strings.LastIndexByte(tag, '-')
+		if i == -1 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return ""
+}
@@ -69,3 +69,42 @@ func BenchmarkMatchFast(b *testing.B) {
 		BenchResult = SelectQValueFast(header, allowed)
 	}
 }
+
+func TestAcceptFastEdgeCases(t *testing.T) {
+	allowed := []string{"a", "b", "c"}
+	assert.Equal(t, "b", SelectQValueFast("b", allowed))
+	assert.Equal(t, "b", SelectQValueFast("a;q=0.5, b, c", allowed))
+	assert.Equal(t, "c", SelectQValueFast("a;q=0.5, b;q=0.9, c", allowed))
+	assert.Equal(t, "b", SelectQValueFast("x, b;q=0.9", allowed))
+	assert.Equal(t, "b", SelectQValueFast("a;q=0, b;q=0.1", allowed))
+	assert.Equal(t, "a", SelectQValueFast("a;charset=utf-8;q=0.8 , b;q=0.5", allowed))
+	assert.Equal(t, "", SelectQValueFast("", allowed))
+	assert.Equal(t, "", SelectQValueFast(",;,", allowed))
+}
+
+func TestAcceptFastMatchesSlow(t *testing.T) {
+	allowed := []string{"application/json", "application/yaml", "application/cbor"}
+	for _, header := range []string{
+		"application/yaml",
+		"text/html, application/yaml;q=0.9",
+		"application/ion;q=0.6,application/json;q=0.5,application/yaml;q=0.5,text/*;q=0.2,application/cbor;q=0.9,application/msgpack;q=0.8,*/*",
+		"application/cbor; q=0.3, application/json",
+	} {
+		assert.Equal(t, SelectQValue(header, allowed), SelectQValueFast(header, allowed), header)
+	}
+}
+
+func TestSelectLanguage(t *testing.T) {
+	allowed := []string{"en", "de", "pt-BR"}
+	assert.Equal(t, "de", SelectLanguage("de", allowed))
+	assert.Equal(t, "de", SelectLanguage("de-CH", allowed))
+	assert.Equal(t, "de", SelectLanguage("DE-ch", allowed))
+	assert.Equal(t, "pt-BR", SelectLanguage("pt-br, en;q=0.5", allowed))
+	assert.Equal(t, "en", SelectLanguage("pt, en;q=0.5", allowed))
+	assert.Equal(t, "de", SelectLanguage("fr, de;q=0.8, en;q=0.7", allowed))
+	assert.Equal(t, "de", SelectLanguage("en;q=0.5, de", allowed))
+	assert.Equal(t, "en", SelectLanguage("fr, *;q=0.1", allowed))
+	assert.Equal(t, "", SelectLanguage("de;q=0", allowed))
+	assert.Equal(t, "", SelectLanguage("fr", allowed))
+	assert.Equal(t, "", SelectLanguage("", allowed))
+}
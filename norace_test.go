@@ -0,0 +1,5 @@
+//go:build !race
+
+package huma_test
+
+const raceEnabled = false
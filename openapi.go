@@ -153,6 +153,9 @@ type Info struct {
 	// Title of the API.
 	Title string `yaml:"title"`
 
+	// Summary is a short summary of the API.
+	Summary string `yaml:"summary,omitempty"`
+
 	// Description of the API. CommonMark syntax MAY be used for rich text representation.
 	Description string `yaml:"description,omitempty"`
 
@@ -176,6 +179,7 @@ type Info struct {
 func (i *Info) MarshalJSON() ([]byte, error) {
 	return marshalJSON([]jsonFieldInfo{
 		{"title", i.Title, omitNever},
+		{"summary", i.Summary, omitEmpty},
 		{"description", i.Description, omitEmpty},
 		{"termsOfService", i.TermsOfService, omitEmpty},
 		{"contact", i.Contact, omitEmpty},
@@ -828,15 +832,16 @@ type Operation struct {
 	DefaultStatus int `yaml:"-"`
 
 	// MaxBodyBytes is the maximum number of bytes to read from the request
-	// body. If not specified, the default is 1MB. Use -1 for unlimited. If
-	// the limit is reached, then an HTTP 413 error is returned.
+	// body. If not specified, the API config's `MaxBodyBytes` (1MB by default)
+	// is used. Use -1 for unlimited. If the limit is reached, then an HTTP 413
+	// error is returned.
 	MaxBodyBytes int64 `yaml:"-"`
 
 	// BodyReadTimeout is the maximum amount of time to wait for the request
-	// body to be read. If not specified, the default is 5 seconds. Use -1
-	// for unlimited. If the timeout is reached, then an HTTP 408 error is
-	// returned. This value supercedes the server's read timeout, and a value
-	// of -1 can unset the server's timeout.
+	// body to be read. If not specified, the API config's `BodyReadTimeout`
+	// (5 seconds by default) is used. Use -1 for unlimited. If the timeout is
+	// reached, then an HTTP 408 error is returned. This value supercedes the
+	// server's read timeout, and a value of -1 can unset the server's timeout.
 	BodyReadTimeout time.Duration `yaml:"-"`
 
 	// Errors is a list of HTTP status codes that the handler may return. If
@@ -857,11 +862,33 @@ type Operation struct {
 	// caution!
 	SkipValidateBody bool `yaml:"-"`
 
+	// FastBodyDecode decodes JSON request bodies directly into the input
+	// struct while validating them, instead of first parsing them into maps
+	// and slices for validation and then decoding them a second time. This
+	// cuts allocations and latency for hot endpoints with small bodies.
+	// Bodies which are invalid, or use features the fast path doesn't handle
+	// like `null` values or duplicate keys, fall back to the regular path so
+	// errors are unchanged. Since the fast path bypasses the API's formats,
+	// only use it when JSON uses `DefaultJSONFormat`. Registration panics if
+	// the body type isn't supported.
+	FastBodyDecode bool `yaml:"-"`
+
 	// Hidden will skip documenting this operation in the OpenAPI. This is
 	// useful for operations that are not intended to be used by clients but
 	// you'd still like the benefits of using Huma. Generally not recommended.
 	Hidden bool `yaml:"-"`
 
+	// Middlewares is a list of middleware functions that run only for this
+	// operation, after any middleware registered on the API via
+	// `api.UseMiddleware`.
+	Middlewares Middlewares `yaml:"-"`
+
+	// InputType and OutputType are the Go types of the handler's input &
+	// output structs. They are set by `huma.Register` and are useful for tools
+	// like client generators which reuse the same types.
+	InputType  reflect.Type `yaml:"-"`
+	OutputType reflect.Type `yaml:"-"`
+
 	// Metadata is a map of arbitrary data that can be attached to the operation.
 	// This can be used to store custom data, such as custom settings for
 	// functions which generate operations.
@@ -888,7 +915,8 @@ type Operation struct {
 	// be unique among all operations described in the API. The operationId value
 	// is case-sensitive. Tools and libraries MAY use the operationId to uniquely
 	// identify an operation, therefore, it is RECOMMENDED to follow common
-	// programming naming conventions.
+	// programming naming conventions. When registering an operation without an
+	// ID, one is created via `GenerateOperationID`.
 	OperationID string `yaml:"operationId,omitempty"`
 
 	// Parameters is a list of parameters that are applicable for this operation.
@@ -940,6 +968,13 @@ type Operation struct {
 }
 
 func (o *Operation) MarshalJSON() ([]byte, error) {
+	// An empty but non-nil security list is meaningful as it removes any
+	// top-level security requirements, so only omit it when nil.
+	var security any
+	if o.Security != nil {
+		security = o.Security
+	}
+
 	return marshalJSON([]jsonFieldInfo{
 		{"tags", o.Tags, omitEmpty},
 		{"summary", o.Summary, omitEmpty},
@@ -951,7 +986,7 @@ func (o *Operation) MarshalJSON() ([]byte, error) {
 		{"responses", o.Responses, omitEmpty},
 		{"callbacks", o.Callbacks, omitEmpty},
 		{"deprecated", o.Deprecated, omitEmpty},
-		{"security", o.Security, omitEmpty},
+		{"security", security, omitNil},
 		{"servers", o.Servers, omitEmpty},
 	}, o.Extensions)
 }
@@ -1450,6 +1485,14 @@ type OpenAPI struct {
 	// `AddOperation`. You may bypass this by directly writing to the `Paths`
 	// map instead.
 	OnAddOperation []AddOpFunc `yaml:"-"`
+
+	// operationIDs maps the IDs of operations added via `AddOperation` to the
+	// method & path of the operation using them, e.g. `GET /things`.
+	operationIDs map[string]string
+
+	// config is the config of the API using this OpenAPI, if created via
+	// `NewAPI`.
+	config *Config
 }
 
 // AddOperation adds an operation to the OpenAPI. This is the preferred way to
@@ -1467,32 +1510,57 @@ func (o *OpenAPI) AddOperation(op *Operation) {
 		o.Paths[op.Path] = item
 	}
 
+	var slot **Operation
 	switch op.Method {
 	case http.MethodGet:
-		item.Get = op
+		slot = &item.Get
 	case http.MethodPost:
-		item.Post = op
+		slot = &item.Post
 	case http.MethodPut:
-		item.Put = op
+		slot = &item.Put
 	case http.MethodPatch:
-		item.Patch = op
+		slot = &item.Patch
 	case http.MethodDelete:
-		item.Delete = op
+		slot = &item.Delete
 	case http.MethodHead:
-		item.Head = op
+		slot = &item.Head
 	case http.MethodOptions:
-		item.Options = op
+		slot = &item.Options
 	case http.MethodTrace:
-		item.Trace = op
+		slot = &item.Trace
 	default:
 		panic("unknown method " + op.Method)
 	}
 
+	key := op.Method + " " + op.Path
+	if o.operationIDs == nil {
+		o.operationIDs = map[string]string{}
+	}
+	if prev := *slot; prev != nil && o.operationIDs[prev.OperationID] == key {
+		// The operation is being replaced, so its ID is free again.
+		delete(o.operationIDs, prev.OperationID)
+	}
+	if op.OperationID != "" {
+		o.operationIDs[op.OperationID] = key
+	}
+	*slot = op
+
 	for _, f := range o.OnAddOperation {
 		f(o, op)
 	}
 }
 
+// SecurityRequirements returns the security requirements which apply to the
+// given operation. These are the operation's own requirements if set, or the
+// top-level requirements otherwise. This is useful for middleware which
+// enforces the documented security, e.g. via `ctx.Operation()`.
+func (o *OpenAPI) SecurityRequirements(op *Operation) []map[string][]string {
+	if op != nil && op.Security != nil {
+		return op.Security
+	}
+	return o.Security
+}
+
 func (o *OpenAPI) MarshalJSON() ([]byte, error) {
 	return marshalJSON([]jsonFieldInfo{
 		{"openapi", o.OpenAPI, omitNever},
@@ -1519,6 +1587,50 @@ func (o *OpenAPI) YAML() ([]byte, error) {
 	return buf.Bytes(), err
 }
 
+// downgradeRoot removes document-level fields which were introduced in
+// OpenAPI 3.1 and have no 3.0 equivalent.
+func downgradeRoot(m map[string]any) {
+	delete(m, "jsonSchemaDialect")
+	delete(m, "webhooks")
+	if info, ok := m["info"].(map[string]any); ok {
+		delete(info, "summary")
+		if license, ok := info["license"].(map[string]any); ok {
+			delete(license, "identifier")
+		}
+	}
+	if components, ok := m["components"].(map[string]any); ok {
+		delete(components, "pathItems")
+	}
+}
+
+// downgradeSchemas converts the component and inline schemas of a marshaled
+// spec to the OpenAPI 3.0 dialect. Examples are skipped since their values
+// are not schemas.
+func downgradeSchemas(input any) {
+	switch value := input.(type) {
+	case map[string]any:
+		for k, v := range value {
+			switch k {
+			case "schema":
+				downgradeSchema(v, DialectOpenAPI30)
+			case "schemas":
+				if schemas, ok := v.(map[string]any); ok {
+					for _, schema := range schemas {
+						downgradeSchema(schema, DialectOpenAPI30)
+					}
+				}
+			case "example", "examples":
+			default:
+				downgradeSchemas(v)
+			}
+		}
+	case []any:
+		for _, item := range value {
+			downgradeSchemas(item)
+		}
+	}
+}
+
 func downgradeSpec(input any) {
 	switch value := input.(type) {
 	case map[string]any:
@@ -1615,9 +1727,11 @@ func downgradeSpec(input any) {
 func (o OpenAPI) Downgrade() ([]byte, error) {
 	b, err := o.MarshalJSON()
 	if err == nil {
-		var v any
+		var v map[string]any
 		json.Unmarshal(b, &v)
 
+		downgradeRoot(v)
+		downgradeSchemas(v)
 		downgradeSpec(v)
 
 		b, err = json.Marshal(v)
@@ -1,6 +1,8 @@
 package huma_test
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/danielgtaylor/huma/v2"
@@ -176,11 +178,21 @@ func TestDowngrade(t *testing.T) {
 		OpenAPI: "3.1.0",
 		Info: &huma.Info{
 			Title:   "Test API",
+			Summary: "Removed in 3.0",
+			License: &huma.License{
+				Name:       "MIT",
+				Identifier: "MIT",
+			},
 			Version: "1.0.0",
 		},
+		JSONSchemaDialect: "https://spec.openapis.org/oas/3.1/dialect/base",
+		Webhooks: map[string]*huma.PathItem{
+			"test": {},
+		},
 		Paths: map[string]*huma.PathItem{
 			"/test": {
 				Get: &huma.Operation{
+					Summary: "Kept in 3.0",
 					Responses: map[string]*huma.Response{
 						"200": {
 							Description: "OK",
@@ -219,11 +231,15 @@ func TestDowngrade(t *testing.T) {
 		"openapi": "3.0.3",
 		"info": {
 			"title": "Test API",
+			"license": {
+				"name": "MIT"
+			},
 			"version": "1.0.0"
 		},
 		"paths": {
 			"/test": {
 				"get": {
+					"summary": "Kept in 3.0",
 					"responses": {
 						"200": {
 							"description": "OK",
@@ -265,3 +281,85 @@ func TestDowngrade(t *testing.T) {
 	// Check that the downgrade worked as expected.
 	assert.JSONEq(t, expected, string(v30))
 }
+
+func TestDowngradeConditionalSchema(t *testing.T) {
+	type Payment struct {
+		Method     string `json:"method" enum:"card,cash"`
+		CardNumber string `json:"card_number,omitempty" requiredIf:"method=card"`
+		Tip        int    `json:"tip,omitempty" dependentRequired:"method"`
+	}
+
+	registry := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	ref := registry.Schema(reflect.TypeOf(Payment{}), true, "")
+	v31 := &huma.OpenAPI{
+		OpenAPI: "3.1.0",
+		Info:    &huma.Info{Title: "Test API", Version: "1.0.0"},
+		Components: &huma.Components{
+			Schemas: registry,
+		},
+		Paths: map[string]*huma.PathItem{
+			"/pay": {
+				Post: &huma.Operation{
+					RequestBody: &huma.RequestBody{
+						Content: map[string]*huma.MediaType{
+							"application/json": {
+								Schema: &huma.Schema{
+									Type: huma.TypeObject,
+									Properties: map[string]*huma.Schema{
+										"payment": ref,
+										"inline": {
+											If:   &huma.Schema{Required: []string{"a"}},
+											Then: &huma.Schema{Required: []string{"b"}},
+										},
+									},
+									DependentRequired: map[string][]string{"inline": {"payment"}},
+								},
+							},
+						},
+					},
+					Responses: map[string]*huma.Response{
+						"200": {Description: "OK"},
+					},
+				},
+			},
+		},
+	}
+
+	// The 3.1 spec uses the conditional keywords.
+	b, err := json.Marshal(v31)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"if"`)
+	assert.Contains(t, string(b), `"dependentRequired"`)
+
+	v30, err := v31.Downgrade()
+	require.NoError(t, err)
+	assert.NotContains(t, string(v30), `"if"`)
+	assert.NotContains(t, string(v30), `"then"`)
+	assert.NotContains(t, string(v30), `"else"`)
+	assert.NotContains(t, string(v30), `"dependentRequired"`)
+	assert.Contains(t, string(v30), `"card_number"`)
+}
+
+func TestSecurityRequirements(t *testing.T) {
+	oapi := &huma.OpenAPI{
+		Security: []map[string][]string{{"bearer": {}}},
+	}
+
+	inherited := &huma.Operation{}
+	scoped := &huma.Operation{Security: []map[string][]string{{"oauth2": {"read"}}}}
+	public := &huma.Operation{Security: []map[string][]string{}}
+
+	assert.Equal(t, oapi.Security, oapi.SecurityRequirements(inherited))
+	assert.Equal(t, scoped.Security, oapi.SecurityRequirements(scoped))
+	assert.Empty(t, oapi.SecurityRequirements(public))
+
+	// An empty list must be written out to remove the top-level requirements,
+	// while an unset one is omitted.
+	b, err := json.Marshal(public)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"security": []}`, string(b))
+
+	b, err = json.Marshal(inherited)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(b))
+}
@@ -0,0 +1,64 @@
+// Package pagination provides reusable input & output types for cursor-based
+// pagination of list operations. Clients pass an opaque `cursor` and a `limit`
+// via query params, and servers return links to other pages via RFC 8288
+// `Link` headers, e.g. `Link: </things?cursor=abc&limit=20>; rel="next"`.
+//
+//	type ListThingsInput struct {
+//		pagination.Params
+//	}
+//
+//	type ListThingsOutput struct {
+//		pagination.Links
+//		Body []Thing
+//	}
+//
+//	huma.Get(api, "/things", func(ctx context.Context, input *ListThingsInput) (*ListThingsOutput, error) {
+//		things, next := db.ListThings(input.Cursor, input.Limit)
+//		resp := &ListThingsOutput{Body: things}
+//		if next != "" {
+//			resp.Link = append(resp.Link, input.Link("next", next))
+//		}
+//		return resp, nil
+//	})
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Params are the query params used to request a page of results. The cursor
+// is opaque to clients and should be taken from a previous response's links.
+type Params struct {
+	Cursor string `query:"cursor" doc:"Opaque cursor used to fetch a page of results, as returned in a previous response's Link header."`
+	Limit  int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Maximum number of items to return."`
+
+	// url is the request URL, used to generate links to other pages.
+	url url.URL
+}
+
+func (p *Params) Resolve(ctx huma.Context) []error {
+	p.url = ctx.URL()
+	return nil
+}
+
+// Link returns an RFC 8288 link header value with the given relation type,
+// e.g. `next`, pointing to the page of results for the given cursor. The link
+// is relative to the current request and keeps any other query params, like
+// filters, which were passed by the client.
+func (p *Params) Link(rel, cursor string) string {
+	u := p.url
+	query := u.Query()
+	query.Set("cursor", cursor)
+	query.Set("limit", strconv.Itoa(p.Limit))
+	u.RawQuery = query.Encode()
+	return "<" + u.RequestURI() + `>; rel="` + rel + `"`
+}
+
+// Links is an output mixin which documents and writes `Link` response headers
+// pointing to related pages of results. Use `Params.Link` to create values.
+type Links struct {
+	Link []string `header:"Link" doc:"RFC 8288 links to related pages of results, e.g. rel=next."`
+}
@@ -0,0 +1,70 @@
+package pagination
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	_, api := humatest.New(t)
+
+	items := []int{1, 2, 3, 4, 5}
+
+	huma.Get(api, "/items", func(ctx context.Context, input *struct {
+		Params
+		Filter string `query:"filter"`
+	}) (*struct {
+		Links
+		Body []int
+	}, error) {
+		start, _ := strconv.Atoi(input.Cursor)
+		end := start + input.Limit
+		if end > len(items) {
+			end = len(items)
+		}
+		resp := &struct {
+			Links
+			Body []int
+		}{Body: items[start:end]}
+		if end < len(items) {
+			resp.Link = append(resp.Link, input.Link("next", strconv.Itoa(end)))
+		}
+		return resp, nil
+	})
+
+	// Params & headers are documented.
+	op := api.OpenAPI().Paths["/items"].Get
+	names := []string{}
+	for _, p := range op.Parameters {
+		names = append(names, p.Name)
+	}
+	assert.Subset(t, names, []string{"cursor", "limit"})
+	assert.NotNil(t, op.Responses["200"].Headers["Link"])
+
+	resp := api.Get("/items?limit=2&filter=odd")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[1, 2]`, resp.Body.String())
+	assert.Contains(t, resp.Header().Values("Link"), `</items?cursor=2&filter=odd&limit=2>; rel="next"`)
+
+	resp = api.Get("/items?cursor=4&limit=2")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[5]`, resp.Body.String())
+	for _, link := range resp.Header().Values("Link") {
+		assert.NotContains(t, link, `rel="next"`)
+	}
+
+	// The limit is validated.
+	resp = api.Get("/items?limit=1000")
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	// The default limit is used when none is passed.
+	resp = api.Get("/items")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[1, 2, 3, 4, 5]`, resp.Body.String())
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package huma_test
+
+// raceEnabled is set when testing with `-race`, which makes `sync.Pool`
+// randomly drop items so allocation counts are not reliable.
+const raceEnabled = true
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// now returns the current time and can be replaced in tests.
+var now = time.Now
+
+// tokenBucket is an in-memory token bucket limiter. Each key starts with a
+// full bucket of tokens which refills at a constant rate, so requests may
+// burst up to the limit while averaging out to the limit over time.
+type tokenBucket struct {
+	limit Limit
+	rate  float64 // tokens per second
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	cleaned time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewTokenBucket creates an in-memory token bucket limiter. Limits are not
+// shared between instances of a service, so use a shared store instead when
+// running more than one.
+//
+//	limiter := ratelimit.NewTokenBucket(ratelimit.Limit{Requests: 100, Window: time.Minute})
+func NewTokenBucket(limit Limit) Limiter {
+	if limit.Requests <= 0 || limit.Window <= 0 {
+		panic("ratelimit: requests and window must be positive")
+	}
+	return &tokenBucket{
+		limit:   limit,
+		rate:    float64(limit.Requests) / limit.Window.Seconds(),
+		buckets: map[string]*bucket{},
+		cleaned: now(),
+	}
+}
+
+func (l *tokenBucket) Limit() Limit {
+	return l.limit
+}
+
+func (l *tokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := now()
+	if t.Sub(l.cleaned) > l.limit.Window {
+		// Full buckets are the same as missing ones, so drop them to keep memory
+		// use proportional to the number of recently active keys.
+		for k, b := range l.buckets {
+			if l.refill(b, t) >= float64(l.limit.Requests) {
+				delete(l.buckets, k)
+			}
+		}
+		l.cleaned = t
+	}
+
+	b := l.buckets[key]
+	if b == nil {
+		b = &bucket{tokens: float64(l.limit.Requests), updated: t}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, t)
+	b.updated = t
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.duration(1 - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = l.duration(float64(l.limit.Requests) - b.tokens)
+	return result, nil
+}
+
+// refill returns the number of tokens in the bucket at the given time.
+func (l *tokenBucket) refill(b *bucket, t time.Time) float64 {
+	tokens := b.tokens + t.Sub(b.updated).Seconds()*l.rate
+	if max := float64(l.limit.Requests); tokens > max {
+		return max
+	}
+	return tokens
+}
+
+// duration returns how long it takes to refill the given number of tokens.
+func (l *tokenBucket) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
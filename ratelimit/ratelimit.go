@@ -0,0 +1,194 @@
+// Package ratelimit provides rate limiting for Huma APIs. A limiter can be
+// used for the whole API and overridden per operation. Requests over the
+// limit get a `429 Too Many Requests` error response, and every limited
+// response includes the `RateLimit-*` headers so well-behaved clients can
+// slow down before hitting the limit.
+//
+// Limits are documented in the OpenAPI via an `x-ratelimit` extension on
+// each limited operation, along with its `429` response.
+//
+//	ratelimit.Enable(api, ratelimit.Options{
+//		Limiter: ratelimit.NewTokenBucket(ratelimit.Limit{Requests: 100, Window: time.Minute}),
+//		Key: func(ctx huma.Context) string {
+//			return ctx.Header("Authorization")
+//		},
+//	})
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// Limit describes how many requests are allowed within a window of time.
+type Limit struct {
+	// Requests is the number of requests allowed per window.
+	Requests int
+
+	// Window is the length of the window, e.g. `time.Minute`.
+	Window time.Duration
+}
+
+// Result describes the outcome of a request against a limit.
+type Result struct {
+	// Allowed is whether the request may proceed.
+	Allowed bool
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is how long until the full limit is available again.
+	Reset time.Duration
+
+	// RetryAfter is how long until the next request will be allowed, set when
+	// the request is not allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter decides whether requests may proceed. Implementations must be safe
+// for concurrent use, and can be backed by a shared store like Redis to
+// limit requests across multiple instances of a service.
+type Limiter interface {
+	// Limit returns the limit that is enforced, used for documentation and
+	// response headers.
+	Limit() Limit
+
+	// Allow records a request for the key and returns whether it may proceed.
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Options configures rate limiting for an API.
+type Options struct {
+	// Limiter is used for every operation which does not set its own. If nil,
+	// only operations with their own limiter are limited.
+	Limiter Limiter
+
+	// Key returns the key to limit requests by, such as a client ID or API
+	// key. Defaults to limiting all requests to an operation together.
+	Key func(ctx huma.Context) string
+}
+
+// Enable adds rate limiting to an API. The limiter for a single operation can
+// be overridden by setting the `ratelimit` operation metadata field to a
+// `ratelimit.Limiter`, or disabled by setting it to `false`.
+//
+// Only operations registered after calling `Enable` are limited & documented,
+// as middleware is bound to an operation when it is registered.
+func Enable(api huma.API, opts Options) {
+	oapi := api.OpenAPI()
+	oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+		document(oapi, op, &opts)
+	})
+
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		limiter := operationLimiter(op, &opts)
+		if limiter == nil {
+			next(ctx)
+			return
+		}
+
+		key := ""
+		if opts.Key != nil {
+			key = opts.Key(ctx)
+		}
+		if op != nil {
+			// Operations sharing a limiter get a separate limit each.
+			key = op.Method + " " + op.Path + " " + key
+		}
+
+		result, err := limiter.Allow(ctx.Context(), key)
+		if err != nil {
+			huma.WriteErr(api, ctx, http.StatusInternalServerError, "unable to check rate limit")
+			return
+		}
+
+		limit := limiter.Limit()
+		ctx.SetHeader("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		ctx.SetHeader("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.SetHeader("RateLimit-Reset", seconds(result.Reset))
+		ctx.SetHeader("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+seconds(limit.Window))
+
+		if !result.Allowed {
+			ctx.SetHeader("Retry-After", seconds(result.RetryAfter))
+			huma.WriteErr(api, ctx, http.StatusTooManyRequests, "rate limit exceeded, retry in "+seconds(result.RetryAfter)+" seconds")
+			return
+		}
+		next(ctx)
+	})
+}
+
+// seconds formats a duration as a whole number of seconds, rounding up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// operationLimiter returns the limiter for an operation, or nil if it is not
+// limited.
+func operationLimiter(op *huma.Operation, opts *Options) Limiter {
+	if op == nil || op.Metadata == nil {
+		return opts.Limiter
+	}
+	switch v := op.Metadata["ratelimit"].(type) {
+	case Limiter:
+		return v
+	case bool:
+		if !v {
+			return nil
+		}
+	}
+	return opts.Limiter
+}
+
+// document describes an operation's limit in the OpenAPI.
+func document(oapi *huma.OpenAPI, op *huma.Operation, opts *Options) {
+	limiter := operationLimiter(op, opts)
+	if limiter == nil {
+		return
+	}
+
+	limit := limiter.Limit()
+	if op.Extensions == nil {
+		op.Extensions = map[string]any{}
+	}
+	op.Extensions["x-ratelimit"] = map[string]any{
+		"requests": limit.Requests,
+		"window":   int(math.Ceil(limit.Window.Seconds())),
+	}
+
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	if op.Responses["429"] != nil {
+		return
+	}
+	errContentType := "application/json"
+	exampleErr := huma.NewError(0, "")
+	if ctf, ok := exampleErr.(huma.ContentTypeFilter); ok {
+		errContentType = ctf.ContentType(errContentType)
+	}
+	errType := reflect.TypeOf(exampleErr)
+	for errType.Kind() == reflect.Pointer {
+		errType = errType.Elem()
+	}
+	op.Responses["429"] = &huma.Response{
+		Description: http.StatusText(http.StatusTooManyRequests),
+		Headers: map[string]*huma.Header{
+			"Retry-After": {
+				Description: "Seconds until the next request will be allowed.",
+				Schema:      &huma.Schema{Type: huma.TypeInteger},
+			},
+		},
+		Content: map[string]*huma.MediaType{
+			errContentType: {
+				Schema: oapi.Components.Schemas.Schema(errType, true, "Error"),
+			},
+		},
+	}
+}
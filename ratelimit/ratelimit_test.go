@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func useClock(t *testing.T) *clock {
+	c := &clock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	now = c.now
+	t.Cleanup(func() { now = time.Now })
+	return c
+}
+
+func register(api huma.API, path string, metadata map[string]any) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get" + path,
+		Method:      http.MethodGet,
+		Path:        path,
+		Metadata:    metadata,
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	c := useClock(t)
+	_, api := humatest.New(t)
+
+	register(api, "/before", nil)
+	Enable(api, Options{
+		Limiter: NewTokenBucket(Limit{Requests: 2, Window: 10 * time.Second}),
+		Key: func(ctx huma.Context) string {
+			return ctx.Header("X-Client")
+		},
+	})
+	register(api, "/after", nil)
+	register(api, "/custom", map[string]any{"ratelimit": NewTokenBucket(Limit{Requests: 1, Window: time.Minute})})
+	register(api, "/unlimited", map[string]any{"ratelimit": false})
+
+	resp := api.Get("/after", "X-Client: a")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "5", resp.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=10", resp.Header().Get("RateLimit-Policy"))
+
+	resp = api.Get("/after", "X-Client: a")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+
+	resp = api.Get("/after", "X-Client: a")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "5", resp.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Body.String(), "rate limit exceeded")
+
+	// Other clients and operations have their own limits.
+	assert.Equal(t, http.StatusNoContent, api.Get("/after", "X-Client: b").Code)
+
+	// Operations registered before enabling rate limiting aren't limited.
+	for i := 0; i < 5; i++ {
+		resp = api.Get("/before", "X-Client: a")
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+	}
+
+	// Tokens refill over time.
+	c.advance(5 * time.Second)
+	assert.Equal(t, http.StatusNoContent, api.Get("/after", "X-Client: a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, api.Get("/after", "X-Client: a").Code)
+
+	assert.Equal(t, http.StatusNoContent, api.Get("/custom").Code)
+	resp = api.Get("/custom")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+
+	for i := 0; i < 5; i++ {
+		resp = api.Get("/unlimited")
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		assert.Empty(t, resp.Header().Get("RateLimit-Limit"))
+	}
+
+	paths := api.OpenAPI().Paths
+	op := paths["/after"].Get
+	assert.Equal(t, map[string]any{"requests": 2, "window": 10}, op.Extensions["x-ratelimit"])
+	require.NotNil(t, op.Responses["429"])
+	assert.NotNil(t, op.Responses["429"].Headers["Retry-After"])
+	assert.Equal(t, "#/components/schemas/ErrorModel", op.Responses["429"].Content["application/problem+json"].Schema.Ref)
+	assert.Nil(t, paths["/before"].Get.Extensions["x-ratelimit"])
+	assert.Nil(t, paths["/before"].Get.Responses["429"])
+	assert.Equal(t, map[string]any{"requests": 1, "window": 60}, paths["/custom"].Get.Extensions["x-ratelimit"])
+	assert.Nil(t, paths["/unlimited"].Get.Extensions["x-ratelimit"])
+	assert.Nil(t, paths["/unlimited"].Get.Responses["429"])
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Limit() Limit { return Limit{Requests: 1, Window: time.Second} }
+
+func (failingLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestRateLimitError(t *testing.T) {
+	_, api := humatest.New(t)
+	Enable(api, Options{Limiter: failingLimiter{}})
+	register(api, "/test", nil)
+
+	resp := api.Get("/test")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "store unavailable")
+}
+
+func TestTokenBucket(t *testing.T) {
+	c := useClock(t)
+	l := NewTokenBucket(Limit{Requests: 3, Window: 3 * time.Second}).(*tokenBucket)
+
+	for i := 2; i >= 0; i-- {
+		r, err := l.Allow(context.Background(), "a")
+		require.NoError(t, err)
+		assert.True(t, r.Allowed)
+		assert.Equal(t, i, r.Remaining)
+	}
+
+	r, _ := l.Allow(context.Background(), "a")
+	assert.False(t, r.Allowed)
+	assert.Equal(t, time.Second, r.RetryAfter)
+	assert.Equal(t, 3*time.Second, r.Reset)
+
+	// The bucket never holds more than the limit.
+	c.advance(time.Hour)
+	r, _ = l.Allow(context.Background(), "b")
+	assert.True(t, r.Allowed)
+	assert.NotContains(t, l.buckets, "a")
+	r, _ = l.Allow(context.Background(), "a")
+	assert.Equal(t, 2, r.Remaining)
+
+	assert.Panics(t, func() { NewTokenBucket(Limit{}) })
+}
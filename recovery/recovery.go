@@ -0,0 +1,118 @@
+// Package recovery provides a middleware which recovers from panics in
+// handlers, logs the panic value and stack trace, and returns a
+// `500 Internal Server Error` response instead of dropping the connection.
+// When used with the `requestid` middleware, the request ID is logged and
+// included in the error response for correlation.
+package recovery
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/middleware"
+	"github.com/danielgtaylor/huma/v2/requestid"
+)
+
+// Options configures the recovery middleware.
+type Options struct {
+	// Log is called with the recovered panic value and stack trace. Defaults
+	// to logging via the standard library `log` package.
+	Log func(ctx huma.Context, value any, stack []byte)
+}
+
+// New creates a middleware which recovers from panics and writes an error
+// response using the API's configured error model. Add it after the
+// `requestid` middleware, if used, and before any others so that panics in
+// other middleware are also recovered.
+//
+//	api.UseMiddleware(
+//		requestid.New(requestid.Options{}),
+//		recovery.New(api, recovery.Options{}),
+//	)
+func New(api huma.API, opts Options) func(ctx huma.Context, next func(huma.Context)) {
+	if opts.Log == nil {
+		opts.Log = logPanic
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		w := &writer{Wrapper: middleware.Wrap(ctx)}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				// Used to intentionally abort a response, so let the server
+				// handle it as usual.
+				panic(v)
+			}
+
+			opts.Log(ctx, v, debug.Stack())
+
+			if w.wroteStatus {
+				// Too late to send an error response.
+				return
+			}
+
+			msg := "An unexpected error occurred"
+			if id := requestid.FromContext(ctx.Context()); id != "" {
+				msg += " (request ID " + id + ")"
+			}
+			huma.WriteErr(api, ctx, http.StatusInternalServerError, msg)
+		}()
+		next(w)
+	}
+}
+
+func logPanic(ctx huma.Context, value any, stack []byte) {
+	if id := requestid.FromContext(ctx.Context()); id != "" {
+		log.Printf("panic: %v (request ID %s)\n%s", value, id, stack)
+		return
+	}
+	log.Printf("panic: %v\n%s", value, stack)
+}
+
+// writer wraps a `huma.Context` to track whether the response status has
+// already been sent.
+type writer struct {
+	middleware.Wrapper
+	wroteStatus bool
+}
+
+func (w *writer) SetStatus(code int) {
+	w.wroteStatus = true
+	w.Unwrap().SetStatus(code)
+}
+
+func (w *writer) BodyWriter() io.Writer {
+	return w
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	// Writing a body without a status implicitly sends 200 OK.
+	w.wroteStatus = true
+	return w.Unwrap().BodyWriter().Write(p)
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (w *writer) Flush() {
+	if _, ok := w.Unwrap().BodyWriter().(http.Flusher); ok {
+		w.wroteStatus = true
+	}
+	w.Wrapper.Flush()
+}
+
+// Hijack takes over the underlying connection, after which an error response
+// can no longer be sent.
+func (w *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	nc, rw, err := w.Wrapper.Hijack()
+	if err == nil {
+		w.wroteStatus = true
+	}
+	return nc, rw, err
+}
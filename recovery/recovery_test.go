@@ -0,0 +1,95 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/danielgtaylor/huma/v2/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	var logged any
+	var stack []byte
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(
+		requestid.New(requestid.Options{}),
+		New(api, Options{
+			Log: func(ctx huma.Context, value any, s []byte) {
+				logged, stack = value, s
+			},
+		}),
+	)
+
+	huma.Get(api, "/panic", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		panic("boom")
+	})
+
+	huma.Get(api, "/ok", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{"ok"}, nil
+	})
+
+	resp := api.Get("/panic", "X-Request-Id: abc123")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "application/problem+json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "abc123", resp.Header().Get("X-Request-Id"))
+
+	var model huma.ErrorModel
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &model))
+	assert.Equal(t, http.StatusInternalServerError, model.Status)
+	assert.Contains(t, model.Detail, "abc123")
+
+	assert.Equal(t, "boom", logged)
+	assert.Contains(t, string(stack), "recovery_test.go")
+
+	// Non-panicking requests are unaffected.
+	logged = nil
+	resp = api.Get("/ok")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Nil(t, logged)
+}
+
+func TestRecoveryAfterWrite(t *testing.T) {
+	logged := false
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(api, Options{
+		Log: func(ctx huma.Context, value any, stack []byte) {
+			logged = true
+		},
+	}))
+
+	huma.Get(api, "/stream", func(ctx context.Context, input *struct{}) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(ctx huma.Context) {
+				ctx.SetStatus(http.StatusAccepted)
+				ctx.BodyWriter().Write([]byte("partial"))
+				panic("boom")
+			},
+		}, nil
+	})
+
+	resp := api.Get("/stream")
+	assert.True(t, logged)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "partial", resp.Body.String())
+}
+
+func TestRecoveryAbort(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(api, Options{}))
+
+	huma.Get(api, "/abort", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		panic(http.ErrAbortHandler)
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		api.Get("/abort")
+	})
+}
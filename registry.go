@@ -21,6 +21,41 @@ type Registry interface {
 	RegisterTypeAlias(t reflect.Type, alias reflect.Type)
 }
 
+// SchemaOptions configures how a registry generates schemas from Go types.
+// `huma.Register` sets them from the API's `huma.Config`.
+type SchemaOptions struct {
+	// AdditionalProperties sets whether generated object schemas allow
+	// properties that are not defined on the struct. Individual structs can
+	// override it with `additionalProperties` on a `_` field.
+	AdditionalProperties bool
+
+	// SynthesizeExamples sets whether generated field schemas which have no
+	// `example` tag get a synthesized example value.
+	SynthesizeExamples bool
+
+	// generated holds the schemas registered via `RegisterGeneratedSchema`.
+	generated map[reflect.Type]generatedSchema
+}
+
+// ConfigurableRegistry is a registry which supports `SchemaOptions` and
+// generated schemas, like the one returned by `NewMapRegistry`. Change the
+// options before any schemas are generated.
+//
+//	registry.(huma.ConfigurableRegistry).SchemaOptions().SynthesizeExamples = true
+type ConfigurableRegistry interface {
+	Registry
+	SchemaOptions() *SchemaOptions
+}
+
+// schemaOptions returns the registry's schema options, or the defaults if it
+// does not support any.
+func schemaOptions(r Registry) SchemaOptions {
+	if c, ok := r.(ConfigurableRegistry); ok {
+		return *c.SchemaOptions()
+	}
+	return SchemaOptions{}
+}
+
 // DefaultSchemaNamer provides schema names for types. It uses the type name
 // when possible, ignoring the package name. If the type is generic, e.g.
 // `MyType[SubType]`, then the brackets are removed like `MyTypeSubType`.
@@ -65,6 +100,7 @@ type mapRegistry struct {
 	seen    map[reflect.Type]bool
 	namer   func(reflect.Type, string) string
 	aliases map[reflect.Type]reflect.Type
+	options SchemaOptions
 }
 
 func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema {
@@ -77,8 +113,8 @@ func (r *mapRegistry) Schema(t reflect.Type, allowRef bool, hint string) *Schema
 	}
 
 	getsRef := t.Kind() == reflect.Struct
-	if t == timeType {
-		// Special case: time.Time is always a string.
+	if t == timeType || isTextType(t) {
+		// Special case: time.Time and text marshalers are always strings.
 		getsRef = false
 	}
 
@@ -144,6 +180,10 @@ func (r *mapRegistry) MarshalYAML() (interface{}, error) {
 	return r.schemas, nil
 }
 
+func (r *mapRegistry) SchemaOptions() *SchemaOptions {
+	return &r.options
+}
+
 // RegisterTypeAlias(t, alias) makes the schema generator use the `alias` type instead of `t`.
 func (r *mapRegistry) RegisterTypeAlias(t reflect.Type, alias reflect.Type) {
 	r.aliases[t] = alias
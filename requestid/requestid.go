@@ -0,0 +1,104 @@
+// Package requestid provides a middleware which assigns every request an ID,
+// propagating an ID sent by the client or an upstream proxy if present. The
+// ID is returned in the response headers and is available to handlers and
+// other middleware for correlating logs & errors.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// DefaultHeader is the default request & response header for request IDs.
+const DefaultHeader = "X-Request-Id"
+
+// maxLength is the maximum length of an incoming request ID which will be
+// propagated. Longer values are replaced by a generated ID.
+const maxLength = 128
+
+type contextKey struct{}
+
+// Options configures the request ID middleware.
+type Options struct {
+	// Header is the request & response header name. Defaults to
+	// `X-Request-Id`.
+	Header string
+
+	// Generate creates a new request ID. Defaults to a random UUID.
+	Generate func() string
+}
+
+// New creates a middleware which assigns a request ID to every request. A
+// valid incoming ID is reused, otherwise a new one is generated. The ID is
+// set as a response header and can be retrieved via `FromContext`. Add it
+// before other middleware so they can use the ID.
+//
+//	api.UseMiddleware(requestid.New(requestid.Options{}))
+func New(opts Options) func(ctx huma.Context, next func(huma.Context)) {
+	if opts.Header == "" {
+		opts.Header = DefaultHeader
+	}
+	if opts.Generate == nil {
+		opts.Generate = NewID
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		id := ctx.Header(opts.Header)
+		if !valid(id) {
+			id = opts.Generate()
+		}
+		ctx.SetHeader(opts.Header, id)
+		next(huma.WithValue(ctx, contextKey{}, id))
+	}
+}
+
+// FromContext returns the request ID for the given context, or an empty
+// string if none has been assigned.
+//
+//	func handler(ctx context.Context, input *MyInput) (*MyOutput, error) {
+//		log.Printf("request %s", requestid.FromContext(ctx))
+//		// ...
+//	}
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// NewID returns a new random (version 4) UUID.
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// valid returns whether an incoming request ID may be propagated. Only short
+// values of visible ASCII characters are accepted to prevent log injection.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
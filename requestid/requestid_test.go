@@ -0,0 +1,66 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+)
+
+var uuidRe = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func register(api huma.API) {
+	huma.Get(api, "/id", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{FromContext(ctx)}, nil
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{}))
+	register(api)
+
+	// Generated IDs.
+	resp := api.Get("/id")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	id := resp.Header().Get("X-Request-Id")
+	assert.Regexp(t, uuidRe, id)
+	assert.Equal(t, `"`+id+`"`, strings.TrimSpace(resp.Body.String()))
+	assert.NotEqual(t, id, api.Get("/id").Header().Get("X-Request-Id"))
+
+	// Propagated IDs.
+	resp = api.Get("/id", "X-Request-Id: abc-123")
+	assert.Equal(t, "abc-123", resp.Header().Get("X-Request-Id"))
+	assert.Equal(t, `"abc-123"`, strings.TrimSpace(resp.Body.String()))
+
+	// Invalid IDs are replaced.
+	for _, invalid := range []string{"has space", strings.Repeat("a", maxLength+1)} {
+		resp = api.Get("/id", "X-Request-Id: "+invalid)
+		assert.Regexp(t, uuidRe, resp.Header().Get("X-Request-Id"))
+	}
+}
+
+func TestRequestIDOptions(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(New(Options{
+		Header:   "X-Correlation-Id",
+		Generate: func() string { return "generated" },
+	}))
+	register(api)
+
+	resp := api.Get("/id")
+	assert.Equal(t, "generated", resp.Header().Get("X-Correlation-Id"))
+	assert.Empty(t, resp.Header().Get("X-Request-Id"))
+
+	resp = api.Get("/id", "X-Correlation-Id: upstream")
+	assert.Equal(t, "upstream", resp.Header().Get("X-Correlation-Id"))
+}
+
+func TestFromContextMissing(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+}
@@ -1,6 +1,7 @@
 package huma
 
 import (
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -92,15 +93,23 @@ type Schema struct {
 	Extensions           map[string]any      `yaml:",inline"`
 	DependentRequired    map[string][]string `yaml:"dependentRequired,omitempty"`
 
-	OneOf []*Schema `yaml:"oneOf,omitempty"`
-	AnyOf []*Schema `yaml:"anyOf,omitempty"`
-	AllOf []*Schema `yaml:"allOf,omitempty"`
-	Not   *Schema   `yaml:"not,omitempty"`
+	OneOf         []*Schema      `yaml:"oneOf,omitempty"`
+	AnyOf         []*Schema      `yaml:"anyOf,omitempty"`
+	AllOf         []*Schema      `yaml:"allOf,omitempty"`
+	Not           *Schema        `yaml:"not,omitempty"`
+	If            *Schema        `yaml:"if,omitempty"`
+	Then          *Schema        `yaml:"then,omitempty"`
+	Else          *Schema        `yaml:"else,omitempty"`
+	Discriminator *Discriminator `yaml:"discriminator,omitempty"`
+	XML           *XML           `yaml:"xml,omitempty"`
 
 	patternRe     *regexp.Regexp  `yaml:"-"`
 	requiredMap   map[string]bool `yaml:"-"`
 	propertyNames []string        `yaml:"-"`
 
+	// Hidden properties are allowed but not documented or validated.
+	hiddenProperties map[string]bool `yaml:"-"`
+
 	// Precomputed validation messages. These prevent allocations during
 	// validation and are known at schema creation time.
 	msgEnum              string                       `yaml:"-"`
@@ -118,6 +127,39 @@ type Schema struct {
 	msgMaxProperties     string                       `yaml:"-"`
 	msgRequired          map[string]string            `yaml:"-"`
 	msgDependentRequired map[string]map[string]string `yaml:"-"`
+	msgDiscriminator     string                       `yaml:"-"`
+}
+
+// Discriminator helps select between the schemas of a `oneOf` or `anyOf`
+// based on the value of a property in the payload, like a tagged union. The
+// mapping goes from property value to schema reference.
+//
+//	discriminator:
+//	  propertyName: petType
+//	  mapping:
+//	    cat: '#/components/schemas/Cat'
+//	    dog: '#/components/schemas/Dog'
+type Discriminator struct {
+	// PropertyName is REQUIRED. The name of the property in the payload that
+	// will hold the discriminator value.
+	PropertyName string `yaml:"propertyName"`
+
+	// Mapping holds mappings between payload values and schema names or
+	// references.
+	Mapping map[string]string `yaml:"mapping,omitempty"`
+
+	// Extensions (user-defined properties), if any. Values in this map will
+	// be marshalled as siblings of the other properties above.
+	Extensions map[string]any `yaml:",inline"`
+}
+
+// MarshalJSON marshals the discriminator into JSON, respecting the
+// `Extensions` map to marshal extensions inline.
+func (d *Discriminator) MarshalJSON() ([]byte, error) {
+	return marshalJSON([]jsonFieldInfo{
+		{"propertyName", d.PropertyName, omitNever},
+		{"mapping", d.Mapping, omitEmpty},
+	}, d.Extensions)
 }
 
 // MarshalJSON marshals the schema into JSON, respecting the `Extensions` map
@@ -163,55 +205,236 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		{"anyOf", s.AnyOf, omitEmpty},
 		{"allOf", s.AllOf, omitEmpty},
 		{"not", s.Not, omitEmpty},
+		{"if", s.If, omitEmpty},
+		{"then", s.Then, omitEmpty},
+		{"else", s.Else, omitEmpty},
+		{"discriminator", s.Discriminator, omitEmpty},
+		{"xml", s.XML, omitEmpty},
 	}, s.Extensions)
 }
 
+// XML describes how a schema is represented in XML. It is generated from the
+// `xml` struct field tags and an `XMLName` field's tag.
+//
+//	xml:
+//	  name: animal
+//	  attribute: true
+type XML struct {
+	// Name replaces the name of the element or attribute.
+	Name string `yaml:"name,omitempty"`
+
+	// Namespace is the URI of the namespace definition.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Prefix used for the name.
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// Attribute declares whether the property is an attribute instead of an
+	// element.
+	Attribute bool `yaml:"attribute,omitempty"`
+
+	// Wrapped declares whether an array is wrapped in a parent element, e.g.
+	// `<books><book/><book/></books>`.
+	Wrapped bool `yaml:"wrapped,omitempty"`
+
+	// Extensions (user-defined properties), if any. Values in this map will
+	// be marshalled as siblings of the other properties above.
+	Extensions map[string]any `yaml:",inline"`
+}
+
+func (x *XML) MarshalJSON() ([]byte, error) {
+	return marshalJSON([]jsonFieldInfo{
+		{"name", x.Name, omitEmpty},
+		{"namespace", x.Namespace, omitEmpty},
+		{"prefix", x.Prefix, omitEmpty},
+		{"attribute", x.Attribute, omitEmpty},
+		{"wrapped", x.Wrapped, omitEmpty},
+	}, x.Extensions)
+}
+
+// xmlName splits an `xml` tag name into its namespace and local name.
+func xmlName(name string) (string, string) {
+	if i := strings.LastIndex(name, " "); i != -1 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// xmlFromField sets the XML metadata of a field's schema from its `xml` tag.
+// Names are only documented when they differ from the JSON property name,
+// which is the default in OpenAPI.
+func xmlFromField(f reflect.StructField, fs *Schema) {
+	tag := f.Tag.Get("xml")
+	if tag == "" || tag == "-" {
+		return
+	}
+
+	x := &XML{}
+	parts := strings.Split(tag, ",")
+	for _, flag := range parts[1:] {
+		switch flag {
+		case "attr":
+			x.Attribute = true
+		case "chardata", "cdata", "innerxml", "comment", "any":
+			// The value has no element of its own.
+			return
+		}
+	}
+
+	jsonName := f.Name
+	if j := strings.Split(f.Tag.Get("json"), ",")[0]; j != "" {
+		jsonName = j
+	}
+
+	var name string
+	x.Namespace, name = xmlName(parts[0])
+	if fs.Type == TypeArray && fs.Items != nil && !x.Attribute {
+		// Go repeats the item element for each array item, and `a>b` wraps the
+		// items in a parent element.
+		item := name
+		if i := strings.LastIndex(name, ">"); i != -1 {
+			x.Wrapped = true
+			name, item = name[:i], name[i+1:]
+			if j := strings.LastIndex(name, ">"); j != -1 {
+				name = name[j+1:]
+			}
+		} else {
+			name = ""
+		}
+		if item != "" && item != jsonName {
+			fs.Items.XML = &XML{Name: item}
+		}
+	}
+	if name != "" && name != jsonName {
+		x.Name = name
+	}
+
+	if x.Name != "" || x.Namespace != "" || x.Attribute || x.Wrapped {
+		fs.XML = x
+	}
+}
+
+// SchemaDialect is a JSON Schema dialect used to serialize schemas for tools
+// which do not support the default keywords.
+type SchemaDialect int
+
+const (
+	// DialectDraft202012 is JSON Schema draft 2020-12 as used by OpenAPI 3.1.
+	// This is the default.
+	DialectDraft202012 SchemaDialect = iota
+
+	// DialectDraft07 is JSON Schema draft-07, which uses `dependencies`
+	// instead of `dependentRequired`.
+	DialectDraft07
+
+	// DialectOpenAPI30 is the OpenAPI 3.0 schema object, which uses
+	// `nullable` instead of type arrays, boolean `exclusiveMinimum` &
+	// `exclusiveMaximum` alongside `minimum` & `maximum`, and a single
+	// `example`. Keywords without an equivalent, like `if`, are removed.
+	DialectOpenAPI30
+)
+
+// MarshalDialect marshals the schema into JSON using the given dialect.
+//
+//	b, err := schema.MarshalDialect(huma.DialectOpenAPI30)
+func (s *Schema) MarshalDialect(d SchemaDialect) ([]byte, error) {
+	b, err := json.Marshal(s)
+	if err != nil || d == DialectDraft202012 {
+		return b, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	if d == DialectOpenAPI30 {
+		downgradeSpec(v)
+	}
+	downgradeSchema(v, d)
+	return json.Marshal(v)
+}
+
+// downgradeSchema converts keywords of a marshaled schema and its subschemas
+// which were introduced after the given dialect.
+func downgradeSchema(input any, d SchemaDialect) {
+	m, ok := input.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if dr, ok := m["dependentRequired"]; ok {
+		delete(m, "dependentRequired")
+		if d == DialectDraft07 {
+			m["dependencies"] = dr
+		}
+	}
+	if d == DialectOpenAPI30 {
+		// Conditional schemas were added in draft-07.
+		delete(m, "if")
+		delete(m, "then")
+		delete(m, "else")
+	}
+
+	if props, ok := m["properties"].(map[string]any); ok {
+		for _, prop := range props {
+			downgradeSchema(prop, d)
+		}
+	}
+	for _, k := range []string{"items", "additionalProperties", "not", "if", "then", "else"} {
+		downgradeSchema(m[k], d)
+	}
+	for _, k := range []string{"oneOf", "anyOf", "allOf"} {
+		if schemas, ok := m[k].([]any); ok {
+			for _, item := range schemas {
+				downgradeSchema(item, d)
+			}
+		}
+	}
+}
+
 // PrecomputeMessages tries to precompute as many validation error messages
 // as possible so that new strings aren't allocated during request validation.
 func (s *Schema) PrecomputeMessages() {
-	s.msgEnum = "expected value to be one of \"" + strings.Join(mapTo(s.Enum, func(v any) string {
-		return fmt.Sprintf("%v", v)
-	}), ", ") + "\""
+	s.msgEnum = message(DefaultLanguage, MsgEnum, joinValues(s.Enum))
 	if s.Minimum != nil {
-		s.msgMinimum = fmt.Sprintf("expected number >= %v", *s.Minimum)
+		s.msgMinimum = message(DefaultLanguage, MsgMinimum, *s.Minimum)
 	}
 	if s.ExclusiveMinimum != nil {
-		s.msgExclusiveMinimum = fmt.Sprintf("expected number > %v", *s.ExclusiveMinimum)
+		s.msgExclusiveMinimum = message(DefaultLanguage, MsgExclusiveMinimum, *s.ExclusiveMinimum)
 	}
 	if s.Maximum != nil {
-		s.msgMaximum = fmt.Sprintf("expected number <= %v", *s.Maximum)
+		s.msgMaximum = message(DefaultLanguage, MsgMaximum, *s.Maximum)
 	}
 	if s.ExclusiveMaximum != nil {
-		s.msgExclusiveMaximum = fmt.Sprintf("expected number < %v", *s.ExclusiveMaximum)
+		s.msgExclusiveMaximum = message(DefaultLanguage, MsgExclusiveMaximum, *s.ExclusiveMaximum)
 	}
 	if s.MultipleOf != nil {
-		s.msgMultipleOf = fmt.Sprintf("expected number to be a multiple of %v", *s.MultipleOf)
+		s.msgMultipleOf = message(DefaultLanguage, MsgMultipleOf, *s.MultipleOf)
 	}
 	if s.MinLength != nil {
-		s.msgMinLength = fmt.Sprintf("expected length >= %d", *s.MinLength)
+		s.msgMinLength = message(DefaultLanguage, MsgMinLength, *s.MinLength)
 	}
 	if s.MaxLength != nil {
-		s.msgMaxLength = fmt.Sprintf("expected length <= %d", *s.MaxLength)
+		s.msgMaxLength = message(DefaultLanguage, MsgMaxLength, *s.MaxLength)
 	}
 	if s.Pattern != "" {
 		s.patternRe = regexp.MustCompile(s.Pattern)
 		if s.PatternDescription != "" {
-			s.msgPattern = "expected string to be " + s.PatternDescription
+			s.msgPattern = message(DefaultLanguage, MsgPatternDescription, s.PatternDescription)
 		} else {
-			s.msgPattern = "expected string to match pattern " + s.Pattern
+			s.msgPattern = message(DefaultLanguage, MsgPattern, s.Pattern)
 		}
 	}
 	if s.MinItems != nil {
-		s.msgMinItems = fmt.Sprintf("expected array length >= %d", *s.MinItems)
+		s.msgMinItems = message(DefaultLanguage, MsgMinItems, *s.MinItems)
 	}
 	if s.MaxItems != nil {
-		s.msgMaxItems = fmt.Sprintf("expected array length <= %d", *s.MaxItems)
+		s.msgMaxItems = message(DefaultLanguage, MsgMaxItems, *s.MaxItems)
 	}
 	if s.MinProperties != nil {
-		s.msgMinProperties = fmt.Sprintf("expected object with at least %d properties", *s.MinProperties)
+		s.msgMinProperties = message(DefaultLanguage, MsgMinProperties, *s.MinProperties)
 	}
 	if s.MaxProperties != nil {
-		s.msgMaxProperties = fmt.Sprintf("expected object with at most %d properties", *s.MaxProperties)
+		s.msgMaxProperties = message(DefaultLanguage, MsgMaxProperties, *s.MaxProperties)
 	}
 
 	if s.Required != nil {
@@ -219,7 +442,7 @@ func (s *Schema) PrecomputeMessages() {
 			s.msgRequired = map[string]string{}
 		}
 		for _, name := range s.Required {
-			s.msgRequired[name] = "expected required property " + name + " to be present"
+			s.msgRequired[name] = message(DefaultLanguage, MsgRequired, name)
 		}
 	}
 
@@ -232,11 +455,15 @@ func (s *Schema) PrecomputeMessages() {
 				if s.msgDependentRequired[name] == nil {
 					s.msgDependentRequired[name] = map[string]string{}
 				}
-				s.msgDependentRequired[name][dependent] = "expected property " + dependent + " to be present when " + name + " is present"
+				s.msgDependentRequired[name][dependent] = message(DefaultLanguage, MsgDependentRequired, dependent, name)
 			}
 		}
 	}
 
+	if s.Discriminator != nil {
+		s.msgDiscriminator = message(DefaultLanguage, MsgDiscriminator, s.Discriminator.PropertyName, discriminatorValues(s.Discriminator))
+	}
+
 	if s.propertyNames == nil {
 		s.propertyNames = make([]string, 0, len(s.Properties))
 		for name := range s.Properties {
@@ -271,9 +498,69 @@ func (s *Schema) PrecomputeMessages() {
 		sub.PrecomputeMessages()
 	}
 
-	if sub := s.Not; sub != nil {
-		sub.PrecomputeMessages()
+	for _, sub := range []*Schema{s.Not, s.If, s.Then, s.Else} {
+		if sub != nil {
+			sub.PrecomputeMessages()
+		}
+	}
+}
+
+// extensionTags adds any `x-` prefixed struct tags to the extensions, e.g.
+// `x-go-type:"int"`. Values which are valid JSON are decoded so that tags
+// like `x-internal:"true"` result in a boolean.
+func extensionTags(tag reflect.StructTag, extensions map[string]any) map[string]any {
+	// This follows the struct tag parsing in `reflect.StructTag.Lookup`.
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quoted := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		if !strings.HasPrefix(name, "x-") {
+			continue
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			continue
+		}
+		if extensions == nil {
+			extensions = map[string]any{}
+		}
+		var decoded any
+		if json.Unmarshal([]byte(value), &decoded) == nil {
+			extensions[name] = decoded
+		} else {
+			extensions[name] = value
+		}
 	}
+	return extensions
 }
 
 func boolTag(f reflect.StructField, tag string) bool {
@@ -455,7 +742,10 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 	if fs == nil {
 		return fs
 	}
+	fs.Title = f.Tag.Get("title")
 	fs.Description = f.Tag.Get("doc")
+	fs.Extensions = extensionTags(f.Tag, fs.Extensions)
+	xmlFromField(f, fs)
 	if fs.Format == "date-time" && f.Tag.Get("header") != "" {
 		// Special case: this is a header and uses a different date/time format.
 		// Note that it can still be overridden by the `format` or `timeFormat`
@@ -502,6 +792,10 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 		}
 	}
 
+	if prop := f.Tag.Get("discriminator"); prop != "" {
+		fs.Discriminator = discriminatorFor(registry, f.Name, fs, prop)
+	}
+
 	if _, ok := f.Tag.Lookup("nullable"); ok {
 		fs.Nullable = boolTag(f, "nullable")
 		if fs.Nullable && fs.Ref != "" {
@@ -531,12 +825,55 @@ func SchemaFromField(registry Registry, f reflect.StructField, hint string) *Sch
 	fs.MaxProperties = intTag(f, "maxProperties")
 	fs.ReadOnly = boolTag(f, "readOnly")
 	fs.WriteOnly = boolTag(f, "writeOnly")
+	if fs.ReadOnly && fs.WriteOnly {
+		// A field cannot be both sent only by the server and sent only by the
+		// client, so this is always a mistake in the struct definition.
+		panic(fmt.Errorf("field '%s' cannot be both readOnly and writeOnly: %w", f.Name, ErrSchemaInvalid))
+	}
 	fs.Deprecated = boolTag(f, "deprecated")
+
+	if schemaOptions(registry).SynthesizeExamples && len(fs.Examples) == 0 && fs.Default == nil {
+		// Docs already show the default, so only synthesize when there is none.
+		if e := SynthesizeExample(fs); e != nil {
+			fs.Examples = []any{e}
+		}
+	}
+
 	fs.PrecomputeMessages()
 
 	return fs
 }
 
+// discriminatorFor builds a discriminator for a `oneOf` or `anyOf` schema
+// which selects a member schema using the given property. Each referenced
+// member with a single `enum` value for that property is added to the mapping.
+func discriminatorFor(r Registry, fieldName string, s *Schema, prop string) *Discriminator {
+	members := s.OneOf
+	if len(members) == 0 {
+		members = s.AnyOf
+	}
+	if len(members) == 0 {
+		panic(fmt.Errorf("discriminator for field '%s' requires a oneOf or anyOf schema: %w", fieldName, ErrSchemaInvalid))
+	}
+
+	d := &Discriminator{PropertyName: prop, Mapping: map[string]string{}}
+	for _, member := range members {
+		if member.Ref == "" {
+			continue
+		}
+		ms := r.SchemaFromRef(member.Ref)
+		if ms == nil {
+			continue
+		}
+		if p := ms.Properties[prop]; p != nil && len(p.Enum) == 1 {
+			if value, ok := p.Enum[0].(string); ok {
+				d.Mapping[value] = member.Ref
+			}
+		}
+	}
+	return d
+}
+
 // fieldInfo stores information about a field, which may come from an
 // embedded type. The `Parent` stores the field's direct parent.
 type fieldInfo struct {
@@ -546,7 +883,9 @@ type fieldInfo struct {
 
 // getFields performs a breadth-first search for all fields including embedded
 // ones. It may return multiple fields with the same name, the first of which
-// represents the outermost declaration.
+// represents the outermost declaration. Like `encoding/json`, embedded structs
+// are flattened into the parent unless given a name via the `json` tag, and
+// the exported fields of unexported embedded structs are promoted.
 func getFields(typ reflect.Type, visited map[reflect.Type]struct{}) []fieldInfo {
 	fields := make([]fieldInfo, 0, typ.NumField())
 	var embedded []reflect.StructField
@@ -558,12 +897,19 @@ func getFields(typ reflect.Type, visited map[reflect.Type]struct{}) []fieldInfo
 
 	for i := 0; i < typ.NumField(); i++ {
 		f := typ.Field(i)
-		if !f.IsExported() {
-			continue
-		}
 
 		if f.Anonymous {
-			embedded = append(embedded, f)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" && deref(f.Type).Kind() == reflect.Struct {
+				embedded = append(embedded, f)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
 			continue
 		}
 
@@ -583,6 +929,23 @@ func getFields(typ reflect.Type, visited map[reflect.Type]struct{}) []fieldInfo
 	return fields
 }
 
+// isTextType returns true if the type is serialized as a string using its
+// `encoding.TextMarshaler` methods. As with `encoding/json`, JSON marshaling
+// methods take precedence when present. Types which only implement
+// `encoding.TextUnmarshaler` are still marshaled as their underlying type, so
+// they keep its schema.
+func isTextType(t reflect.Type) bool {
+	v := reflect.New(deref(t)).Interface()
+	if _, ok := v.(json.Marshaler); ok {
+		return false
+	}
+	if _, ok := v.(json.Unmarshaler); ok {
+		return false
+	}
+	_, ok := v.(encoding.TextMarshaler)
+	return ok
+}
+
 // SchemaProvider is an interface that can be implemented by types to provide
 // a custom schema for themselves, overriding the built-in schema generation.
 // This can be used by custom types with their own special serialization rules.
@@ -590,6 +953,82 @@ type SchemaProvider interface {
 	Schema(r Registry) *Schema
 }
 
+// generatedSchema is a pre-generated schema for a struct type.
+type generatedSchema struct {
+	properties []string
+	hidden     map[string]bool
+	fn         func(r Registry) *Schema
+}
+
+// RegisterGeneratedSchema registers a function with the registry which
+// returns the schema for a struct type, used instead of reflecting on the
+// struct's fields & tags. The property names should be given in field order,
+// which is the order used for validation errors. The hidden property names are
+// those of fields tagged `hidden:"true"`, which are left out of the schema but
+// still accepted in requests. This is meant to be called by
+// code generated by the `humagen` package or by adapters like `humaproto`,
+// before any schemas are created. The registry must implement
+// `ConfigurableRegistry`.
+func RegisterGeneratedSchema(r Registry, t reflect.Type, properties, hidden []string, fn func(r Registry) *Schema) {
+	c, ok := r.(ConfigurableRegistry)
+	if !ok {
+		panic(fmt.Errorf("registry %T does not support generated schemas", r))
+	}
+	opts := c.SchemaOptions()
+	if opts.generated == nil {
+		opts.generated = map[reflect.Type]generatedSchema{}
+	}
+	g := generatedSchema{properties: properties, fn: fn}
+	if len(hidden) > 0 {
+		g.hidden = map[string]bool{}
+		for _, name := range hidden {
+			g.hidden[name] = true
+		}
+	}
+	opts.generated[deref(t)] = g
+}
+
+// composeSchemas returns a schema (or reference) for each of the given types.
+func composeSchemas(r Registry, types []reflect.Type) []*Schema {
+	schemas := make([]*Schema, 0, len(types))
+	for _, t := range types {
+		schemas = append(schemas, r.Schema(t, true, deref(t).Name()))
+	}
+	return schemas
+}
+
+// OneOf returns a schema which matches when a value is valid against exactly
+// one of the schemas generated for the given types, e.g. to describe a body
+// which may take one of several shapes. Structs are added to the registry and
+// referenced as usual.
+//
+//	schema := huma.OneOf(registry, reflect.TypeOf(Cat{}), reflect.TypeOf(Dog{}))
+func OneOf(r Registry, types ...reflect.Type) *Schema {
+	s := &Schema{OneOf: composeSchemas(r, types)}
+	s.PrecomputeMessages()
+	return s
+}
+
+// AnyOf returns a schema which matches when a value is valid against at least
+// one of the schemas generated for the given types.
+//
+//	schema := huma.AnyOf(registry, reflect.TypeOf(Cat{}), reflect.TypeOf(Dog{}))
+func AnyOf(r Registry, types ...reflect.Type) *Schema {
+	s := &Schema{AnyOf: composeSchemas(r, types)}
+	s.PrecomputeMessages()
+	return s
+}
+
+// AllOf returns a schema which matches when a value is valid against all of
+// the schemas generated for the given types.
+//
+//	schema := huma.AllOf(registry, reflect.TypeOf(Base{}), reflect.TypeOf(Extra{}))
+func AllOf(r Registry, types ...reflect.Type) *Schema {
+	s := &Schema{AllOf: composeSchemas(r, types)}
+	s.PrecomputeMessages()
+	return s
+}
+
 // SchemaFromType returns a schema for a given type, using the registry to
 // possibly create references for nested structs. The schema that is returned
 // can then be passed to `huma.Validate` to efficiently validate incoming
@@ -605,6 +1044,15 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		return sp.Schema(r)
 	}
 
+	if g, ok := schemaOptions(r).generated[deref(t)]; ok {
+		// Special case: the schema was generated ahead of time.
+		s := g.fn(r)
+		s.propertyNames = g.properties
+		s.hiddenProperties = g.hidden
+		s.PrecomputeMessages()
+		return s
+	}
+
 	isPointer := t.Kind() == reflect.Pointer
 
 	s := Schema{}
@@ -622,6 +1070,12 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		return &Schema{}
 	}
 
+	if isTextType(t) {
+		// Special case: the type is marshaled to/from a string by its text
+		// marshaling methods, regardless of the underlying Go kind.
+		return &Schema{Type: TypeString, Nullable: isPointer}
+	}
+
 	minZero := 0.0
 	switch t.Kind() {
 	case reflect.Bool:
@@ -690,17 +1144,12 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		fieldSet := map[string]struct{}{}
 		props := map[string]*Schema{}
 		dependentRequiredMap := map[string][]string{}
+		var conditions []string
+		requiredIf := map[string][]string{}
+		hidden := map[string]bool{}
 		for _, info := range getFields(t, make(map[reflect.Type]struct{})) {
 			f := info.Field
 
-			if _, ok := fieldSet[f.Name]; ok {
-				// This field was overridden by an ancestor type, so we
-				// should ignore it.
-				continue
-			}
-
-			fieldSet[f.Name] = struct{}{}
-
 			// Controls whether the field is required or not. All fields start as
 			// required, then can be made optional with the `omitempty` JSON tag or it
 			// can be overridden manually via the `required` tag.
@@ -720,13 +1169,22 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 				continue
 			}
 
+			if _, ok := fieldSet[name]; ok {
+				// This field was overridden by an ancestor type, so we
+				// should ignore it.
+				continue
+			}
+
+			fieldSet[name] = struct{}{}
+
 			if _, ok := f.Tag.Lookup("required"); ok {
 				fieldRequired = boolTag(f, "required")
 			}
 
 			if boolTag(f, "hidden") {
-				// This field is deliberately ignored. It may still exist, but won't
-				// be documented.
+				// This field is deliberately not documented. It may still be sent by
+				// clients, so it's allowed even if additional properties are not.
+				hidden[name] = true
 				continue
 			}
 
@@ -734,6 +1192,17 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 				dependentRequiredMap[name] = strings.Split(dr, ",")
 			}
 
+			if cond := f.Tag.Get("requiredIf"); cond != "" {
+				// Conditionally required fields are otherwise optional.
+				if _, ok := f.Tag.Lookup("required"); !ok {
+					fieldRequired = false
+				}
+				if requiredIf[cond] == nil {
+					conditions = append(conditions, cond)
+				}
+				requiredIf[cond] = append(requiredIf[cond], name)
+			}
+
 			fs := SchemaFromField(r, f, t.Name()+f.Name+"Struct")
 			if fs != nil {
 				props[name] = fs
@@ -773,7 +1242,37 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 			panic(errors.New(strings.Join(errs, "; ")))
 		}
 
-		additionalProps := false
+		// Each `requiredIf:"field=value"` condition becomes an `if` / `then`
+		// pair, combined via `allOf` when there are several.
+		for _, cond := range conditions {
+			field, value, ok := strings.Cut(cond, "=")
+			if !ok || props[field] == nil {
+				panic(fmt.Errorf("invalid requiredIf condition '%s' for fields %v: expected 'field=value' with an existing field", cond, requiredIf[cond]))
+			}
+			// The properties are listed so the validator checks they are present,
+			// but their schemas are only validated once by the parent.
+			then := &Schema{Type: TypeObject, Properties: map[string]*Schema{}, Required: requiredIf[cond]}
+			for _, name := range requiredIf[cond] {
+				then.Properties[name] = &Schema{}
+			}
+			sub := &Schema{
+				If: &Schema{
+					Type: TypeObject,
+					Properties: map[string]*Schema{
+						field: {Enum: []any{jsonTagValue(r, field, props[field], value)}},
+					},
+					Required: []string{field},
+				},
+				Then: then,
+			}
+			if len(conditions) == 1 {
+				s.If, s.Then = sub.If, sub.Then
+			} else {
+				s.AllOf = append(s.AllOf, sub)
+			}
+		}
+
+		additionalProps := schemaOptions(r).AdditionalProperties
 		if f, ok := t.FieldByName("_"); ok {
 			if _, ok = f.Tag.Lookup("additionalProperties"); ok {
 				additionalProps = boolTag(f, "additionalProperties")
@@ -783,6 +1282,19 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 				// Allow overriding nullability per struct.
 				s.Nullable = boolTag(f, "nullable")
 			}
+
+			// Allow documenting the struct itself.
+			s.Title = f.Tag.Get("title")
+			s.Description = f.Tag.Get("doc")
+			s.Extensions = extensionTags(f.Tag, s.Extensions)
+		}
+		if f, ok := t.FieldByName("XMLName"); ok && f.Type == xmlNameType {
+			// The root element name, e.g. `xml:"urn:example thing"`.
+			if name := strings.Split(f.Tag.Get("xml"), ",")[0]; name != "" {
+				x := &XML{}
+				x.Namespace, x.Name = xmlName(name)
+				s.XML = x
+			}
 		}
 		s.AdditionalProperties = additionalProps
 
@@ -791,6 +1303,9 @@ func SchemaFromType(r Registry, t reflect.Type) *Schema {
 		s.Required = required
 		s.DependentRequired = dependentRequiredMap
 		s.requiredMap = requiredMap
+		if len(hidden) > 0 {
+			s.hiddenProperties = hidden
+		}
 		s.PrecomputeMessages()
 	case reflect.Interface:
 		// Interfaces mean any object.
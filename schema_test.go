@@ -35,6 +35,42 @@ type RecursiveChildLoop struct {
 	ByRef   *RecursiveChildKey                       `json:"byRef"`
 }
 
+type TextMarshalerStruct struct {
+	a, b string
+}
+
+func (t TextMarshalerStruct) MarshalText() ([]byte, error) {
+	return []byte(t.a + "-" + t.b), nil
+}
+
+type TextMarshalerInt int
+
+func (t TextMarshalerInt) MarshalText() ([]byte, error) {
+	return []byte("active"), nil
+}
+
+func (t *TextMarshalerInt) UnmarshalText(b []byte) error {
+	return nil
+}
+
+// TextUnmarshalerInt is still marshaled as an integer by `encoding/json`.
+type TextUnmarshalerInt int
+
+func (t *TextUnmarshalerInt) UnmarshalText(b []byte) error {
+	return nil
+}
+
+// JSONAndTextMarshaller uses its JSON methods, which take precedence.
+type JSONAndTextMarshaller int
+
+func (t JSONAndTextMarshaller) MarshalJSON() ([]byte, error) {
+	return []byte("0"), nil
+}
+
+func (t JSONAndTextMarshaller) MarshalText() ([]byte, error) {
+	return []byte("zero"), nil
+}
+
 type EmbeddedChild struct {
 	// This one should be ignored as it is overridden by `Embedded`.
 	Value string `json:"value" doc:"old doc"`
@@ -45,6 +81,10 @@ type Embedded struct {
 	Value string `json:"value" doc:"new doc"`
 }
 
+type embeddedUnexported struct {
+	ID string `json:"id"`
+}
+
 type CustomSchema struct{}
 
 func (c CustomSchema) Schema(r huma.Registry) *huma.Schema {
@@ -154,6 +194,31 @@ func TestSchema(t *testing.T) {
 			input:    &json.RawMessage{},
 			expected: `{}`,
 		},
+		{
+			name:     "text-marshaler",
+			input:    TextMarshalerStruct{},
+			expected: `{"type": "string"}`,
+		},
+		{
+			name: "field-text-marshaler",
+			input: struct {
+				Status      TextMarshalerInt      `json:"status" enum:"active,inactive"`
+				Ptr         *TextMarshalerStruct  `json:"ptr,omitempty"`
+				JSON        JSONAndTextMarshaller `json:"json"`
+				Unmarshaler TextUnmarshalerInt    `json:"unmarshaler"`
+			}{},
+			expected: `{
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["status", "json", "unmarshaler"],
+				"properties": {
+					"status": {"type": "string", "enum": ["active", "inactive"]},
+					"ptr": {"type": "string"},
+					"json": {"type": "integer", "format": "int64"},
+					"unmarshaler": {"type": "integer", "format": "int64"}
+				}
+			}`,
+		},
 		{
 			name:     "bytes",
 			input:    []byte("test"),
@@ -191,6 +256,30 @@ func TestSchema(t *testing.T) {
 				"additionalProperties": true
 			}`,
 		},
+		{
+			name: "struct-docs-extensions",
+			input: struct {
+				_     struct{} `json:"-" title:"Thing" doc:"A thing" x-internal:"true"`
+				Value string   `json:"value" title:"Value" doc:"The value" x-go-type:"mypkg.Value" x-order:"1"`
+			}{},
+			expected: `{
+				"type": "object",
+				"title": "Thing",
+				"description": "A thing",
+				"x-internal": true,
+				"properties": {
+					"value": {
+						"type": "string",
+						"title": "Value",
+						"description": "The value",
+						"x-go-type": "mypkg.Value",
+						"x-order": 1
+					}
+				},
+				"required": ["value"],
+				"additionalProperties": false
+			}`,
+		},
 		{
 			name: "field-int",
 			input: struct {
@@ -605,11 +694,54 @@ func TestSchema(t *testing.T) {
 			expected: `{
 				"type": "object",
 				"additionalProperties": false,
-				"required": ["override"],
+				"required": ["override", "value"],
 				"properties": {
 					"override": {
 						"type": "string",
 						"description": "override"
+					},
+					"value": {
+						"type": "string",
+						"description": "new doc"
+					}
+				}
+			}`,
+		},
+		{
+			name: "field-embed-unexported",
+			input: struct {
+				// Fields of unexported embedded structs are still promoted.
+				embeddedUnexported
+				Name string `json:"name"`
+			}{},
+			expected: `{
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["name", "id"],
+				"properties": {
+					"id": {
+						"type": "string"
+					},
+					"name": {
+						"type": "string"
+					}
+				}
+			}`,
+		},
+		{
+			name: "field-embed-named",
+			input: struct {
+				// A name in the JSON tag means the struct is not flattened.
+				EmbeddedChild      `json:"child"`
+				embeddedUnexported `json:"-"`
+			}{},
+			expected: `{
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["child"],
+				"properties": {
+					"child": {
+						"$ref": "#/components/schemas/EmbeddedChild"
 					}
 				}
 			}`,
@@ -804,6 +936,13 @@ func TestSchema(t *testing.T) {
 			}{},
 			panics: `dependent field 'missing1' for field 'value1' does not exist; dependent field 'missing2' for field 'value1' does not exist; dependent field 'missing2' for field 'value2' does not exist`,
 		},
+		{
+			name: "panic-read-write-only",
+			input: struct {
+				Value string `json:"value" readOnly:"true" writeOnly:"true"`
+			}{},
+			panics: `field 'Value' cannot be both readOnly and writeOnly: schema is invalid`,
+		},
 		{
 			name: "panic-nullable-struct",
 			input: struct {
@@ -832,6 +971,328 @@ func TestSchema(t *testing.T) {
 	}
 }
 
+type CompositionCat struct {
+	Meow bool `json:"meow"`
+}
+
+type CompositionDog struct {
+	Bark string `json:"bark"`
+}
+
+type CompositionPet interface{}
+
+type CompositionPetSchema struct{}
+
+func (CompositionPetSchema) Schema(r huma.Registry) *huma.Schema {
+	return huma.OneOf(r, reflect.TypeOf(CompositionCat{}), reflect.TypeOf(CompositionDog{}))
+}
+
+func TestSchemaComposition(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	cat := reflect.TypeOf(CompositionCat{})
+	dog := reflect.TypeOf(CompositionDog{})
+
+	for name, s := range map[string]*huma.Schema{
+		"oneOf": huma.OneOf(r, cat, dog),
+		"anyOf": huma.AnyOf(r, cat, dog),
+		"allOf": huma.AllOf(r, cat, dog),
+	} {
+		b, _ := json.Marshal(s)
+		assert.JSONEq(t, `{"`+name+`": [
+			{"$ref": "#/components/schemas/CompositionCat"},
+			{"$ref": "#/components/schemas/CompositionDog"}
+		]}`, string(b))
+	}
+	assert.Contains(t, r.Map(), "CompositionCat")
+	assert.Contains(t, r.Map(), "CompositionDog")
+
+	// Interface fields can be described by aliasing the interface to a type
+	// which provides the composed schema.
+	r.RegisterTypeAlias(reflect.TypeOf((*CompositionPet)(nil)).Elem(), reflect.TypeOf(CompositionPetSchema{}))
+	s := r.Schema(reflect.TypeOf(struct {
+		Pet CompositionPet `json:"pet"`
+	}{}), false, "PetOwner")
+	require.Len(t, s.Properties["pet"].OneOf, 2)
+
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	res := &huma.ValidateResult{}
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{"pet": map[string]any{"meow": true}}, res)
+	assert.Empty(t, res.Errors)
+
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{"pet": map[string]any{"purr": true}}, res)
+	assert.NotEmpty(t, res.Errors)
+}
+
+type DiscriminatorCat struct {
+	Kind string `json:"kind" enum:"cat"`
+	Meow bool   `json:"meow"`
+}
+
+type DiscriminatorDog struct {
+	Kind string `json:"kind" enum:"dog"`
+	Bark bool   `json:"bark"`
+}
+
+type DiscriminatorPet interface{}
+
+type DiscriminatorPetSchema struct{}
+
+func (DiscriminatorPetSchema) Schema(r huma.Registry) *huma.Schema {
+	return huma.OneOf(r, reflect.TypeOf(DiscriminatorCat{}), reflect.TypeOf(DiscriminatorDog{}))
+}
+
+func TestSchemaDiscriminator(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	r.RegisterTypeAlias(reflect.TypeOf((*DiscriminatorPet)(nil)).Elem(), reflect.TypeOf(DiscriminatorPetSchema{}))
+
+	s := r.Schema(reflect.TypeOf(struct {
+		Pet DiscriminatorPet `json:"pet" discriminator:"kind"`
+	}{}), false, "DiscriminatorOwner")
+
+	b, _ := json.Marshal(s.Properties["pet"].Discriminator)
+	assert.JSONEq(t, `{
+		"propertyName": "kind",
+		"mapping": {
+			"cat": "#/components/schemas/DiscriminatorCat",
+			"dog": "#/components/schemas/DiscriminatorDog"
+		}
+	}`, string(b))
+
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	res := &huma.ValidateResult{}
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{"pet": map[string]any{"kind": "cat", "meow": true}}, res)
+	assert.Empty(t, res.Errors)
+
+	// Only the selected schema is used, so errors are specific to it.
+	res.Reset()
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{"pet": map[string]any{"kind": "dog", "meow": true}}, res)
+	require.Len(t, res.Errors, 2)
+	assert.Contains(t, res.Errors[0].Error(), "pet")
+	assert.NotContains(t, res.Errors[0].Error(), "oneOf")
+
+	res.Reset()
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{"pet": map[string]any{"kind": "fish"}}, res)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, `expected property kind to be one of "cat, dog" (pet.kind: fish)`, res.Errors[0].Error())
+
+	assert.Panics(t, func() {
+		r.Schema(reflect.TypeOf(struct {
+			Name string `json:"name" discriminator:"kind"`
+		}{}), false, "DiscriminatorInvalid")
+	})
+}
+
+func TestSchemaDefaultAdditionalProperties(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	r.(huma.ConfigurableRegistry).SchemaOptions().AdditionalProperties = true
+
+	loose := r.Schema(reflect.TypeOf(struct {
+		Name string `json:"name"`
+	}{}), false, "Loose")
+	assert.Equal(t, true, loose.AdditionalProperties)
+
+	// Structs can still opt back into strict validation.
+	strict := r.Schema(reflect.TypeOf(struct {
+		_    struct{} `additionalProperties:"false"`
+		Name string   `json:"name"`
+	}{}), false, "Strict")
+	assert.Equal(t, false, strict.AdditionalProperties)
+
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	res := &huma.ValidateResult{}
+	huma.Validate(r, loose, pb, huma.ModeWriteToServer, map[string]any{"name": "a", "extra": true}, res)
+	assert.Empty(t, res.Errors)
+
+	huma.Validate(r, strict, pb, huma.ModeWriteToServer, map[string]any{"name": "a", "extra": true}, res)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, "extra", res.Errors[0].(*huma.ErrorDetail).Location)
+}
+
+type GeneratedSchemaModel struct {
+	First  string `json:"first"`
+	Second string `json:"second"`
+}
+
+func TestSchemaGenerated(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	huma.RegisterGeneratedSchema(r, reflect.TypeOf(GeneratedSchemaModel{}), []string{"second", "first"}, []string{"secret"}, func(r huma.Registry) *huma.Schema {
+		return &huma.Schema{
+			Type:                 huma.TypeObject,
+			Description:          "generated",
+			AdditionalProperties: false,
+			Properties: map[string]*huma.Schema{
+				"first":  {Type: huma.TypeString},
+				"second": {Type: huma.TypeString},
+			},
+			Required: []string{"first", "second"},
+		}
+	})
+
+	s := r.Schema(reflect.TypeOf(&GeneratedSchemaModel{}), false, "")
+	assert.Equal(t, "generated", s.Description)
+	assert.NotNil(t, r.Map()["GeneratedSchemaModel"])
+
+	// Validation uses the given property order.
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	res := &huma.ValidateResult{}
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{}, res)
+	require.Len(t, res.Errors, 2)
+	assert.Contains(t, res.Errors[0].Error(), "second")
+	assert.Contains(t, res.Errors[1].Error(), "first")
+
+	// Hidden properties are allowed even though they are not in the schema.
+	res.Reset()
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, map[string]any{"first": "a", "second": "b", "secret": "c"}, res)
+	assert.Empty(t, res.Errors)
+
+	// Other registries keep using reflection.
+	other := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s = huma.SchemaFromType(other, reflect.TypeOf(GeneratedSchemaModel{}))
+	assert.Empty(t, s.Description)
+}
+
+func TestSchemaMarshalDialect(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	s := r.Schema(reflect.TypeOf(struct {
+		Name   *string `json:"name,omitempty" nullable:"true" example:"abc"`
+		Nested struct {
+			Count int    `json:"count,omitempty" exclusiveMinimum:"0" dependentRequired:"unit"`
+			Unit  string `json:"unit,omitempty"`
+		} `json:"nested"`
+	}{}), false, "Dialect")
+
+	b, err := s.MarshalDialect(huma.DialectDraft202012)
+	require.NoError(t, err)
+	expected, _ := json.Marshal(s)
+	assert.Equal(t, string(expected), string(b))
+
+	b, err = s.MarshalDialect(huma.DialectDraft07)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": ["string", "null"], "examples": ["abc"]},
+			"nested": {"$ref": "#/components/schemas/NestedStruct"}
+		},
+		"required": ["nested"]
+	}`, string(b))
+
+	b, err = r.Map()["NestedStruct"].MarshalDialect(huma.DialectDraft07)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"count": {"type": "integer", "format": "int64", "exclusiveMinimum": 0},
+			"unit": {"type": "string"}
+		},
+		"dependencies": {"count": ["unit"]}
+	}`, string(b))
+
+	b, err = s.MarshalDialect(huma.DialectOpenAPI30)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "nullable": true, "example": "abc"},
+			"nested": {"$ref": "#/components/schemas/NestedStruct"}
+		},
+		"required": ["nested"]
+	}`, string(b))
+
+	b, err = r.Map()["NestedStruct"].MarshalDialect(huma.DialectOpenAPI30)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"count": {"type": "integer", "format": "int64", "minimum": 0, "exclusiveMinimum": true},
+			"unit": {"type": "string"}
+		}
+	}`, string(b))
+}
+
+func TestSchemaSynthesizeExamples(t *testing.T) {
+	r := huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	r.(huma.ConfigurableRegistry).SchemaOptions().SynthesizeExamples = true
+
+	s := r.Schema(reflect.TypeOf(struct {
+		Tagged    string    `json:"tagged" example:"tagged"`
+		Defaulted int       `json:"defaulted" default:"5"`
+		Enum      string    `json:"enum" enum:"one,two"`
+		Bool      bool      `json:"bool"`
+		Int       int       `json:"int" minimum:"10" maximum:"20"`
+		ExclInt   int       `json:"exclInt" exclusiveMaximum:"0"`
+		Multiple  int       `json:"multiple" multipleOf:"5"`
+		Float     float64   `json:"float" exclusiveMinimum:"1" exclusiveMaximum:"2"`
+		Str       string    `json:"str" minLength:"8" maxLength:"10"`
+		Short     string    `json:"short" maxLength:"3"`
+		Pattern   string    `json:"pattern" pattern:"^[a-z]+$"`
+		Email     string    `json:"email" format:"email"`
+		UUID      string    `json:"uuid" format:"uuid"`
+		Time      time.Time `json:"time"`
+		Bytes     []byte    `json:"bytes"`
+		Tags      []string  `json:"tags" minItems:"2"`
+	}{}), false, "Synth")
+
+	examples := map[string]any{}
+	for name, prop := range s.Properties {
+		if len(prop.Examples) > 0 {
+			examples[name] = prop.Examples[0]
+		}
+	}
+
+	b, _ := json.Marshal(examples)
+	assert.JSONEq(t, `{
+		"tagged": "tagged",
+		"enum": "one",
+		"bool": true,
+		"int": 15,
+		"exclInt": -1,
+		"multiple": 5,
+		"float": 1.5,
+		"str": "stringss",
+		"short": "str",
+		"pattern": "string",
+		"email": "user@example.com",
+		"uuid": "123e4567-e89b-12d3-a456-426614174000",
+		"time": "2024-01-01T12:00:00Z",
+		"bytes": "c3RyaW5n",
+		"tags": ["string", "string"]
+	}`, string(b))
+
+	// Synthesized examples are valid.
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	res := &huma.ValidateResult{}
+	var parsed any
+	json.Unmarshal(b, &parsed)
+	parsed.(map[string]any)["defaulted"] = 5.0
+	huma.Validate(r, s, pb, huma.ModeWriteToServer, parsed, res)
+	assert.Empty(t, res.Errors)
+}
+
+func TestSynthesizeExampleInfeasible(t *testing.T) {
+	one := 1
+	two := 2
+	for name, s := range map[string]*huma.Schema{
+		"ref":     {Ref: "#/components/schemas/Thing"},
+		"object":  {Type: huma.TypeObject},
+		"pattern": {Type: huma.TypeString, Pattern: "^[0-9]+$"},
+		"format":  {Type: huma.TypeString, Format: "email", MaxLength: &one},
+		"int":     {Type: huma.TypeInteger, Minimum: floatPtr(0.2), Maximum: floatPtr(0.8)},
+		"unique":  {Type: huma.TypeArray, MinItems: &two, UniqueItems: true, Items: &huma.Schema{Type: huma.TypeString}},
+		"items":   {Type: huma.TypeArray, Items: &huma.Schema{Type: huma.TypeObject}},
+	} {
+		assert.Nil(t, huma.SynthesizeExample(s), name)
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
 type GreetingInput struct {
 	ID string `path:"id"`
 }
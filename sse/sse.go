@@ -47,7 +47,8 @@ func (s Sender) Data(data any) error {
 // the type of the data that will be sent. The `f` function is called with
 // the context, input, and a `send` function that can be used to send messages
 // to the client. Flushing is handled automatically as long as the adapter's
-// `BodyWriter` implements `http.Flusher`.
+// `BodyWriter` implements `http.Flusher`. Once the client disconnects, `send`
+// returns the context's error so long-running handlers know to stop.
 func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]any, f func(ctx context.Context, input *I, send Sender)) {
 	// Start by defining the SSE schema & operation response.
 	if op.Responses == nil {
@@ -117,9 +118,15 @@ func Register[I any](api huma.API, op huma.Operation, eventTypeMap map[string]an
 		return &huma.StreamResponse{
 			Body: func(ctx huma.Context) {
 				ctx.SetHeader("Content-Type", "text/event-stream")
+				ctx.SetHeader("Cache-Control", "no-cache")
 				bw := ctx.BodyWriter()
 				encoder := json.NewEncoder(bw)
 				send := func(msg Message) error {
+					if err := ctx.Context().Err(); err != nil {
+						// The client has disconnected, so stop sending.
+						return err
+					}
+
 					if d, ok := bw.(interface{ SetWriteDeadline(time.Time) error }); ok {
 						d.SetWriteDeadline(time.Now().Add(WriteTimeout))
 					} else {
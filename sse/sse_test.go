@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -106,3 +107,28 @@ data: {"error": "encode error: json: unsupported type: chan int"}
 	req, _ = http.NewRequest(http.MethodGet, "/sse", nil)
 	api.Adapter().ServeHTTP(w, req)
 }
+
+func TestSSEClientDisconnect(t *testing.T) {
+	_, api := humatest.New(t)
+
+	var sendErr error
+	sse.Register(api, huma.Operation{
+		OperationID: "sse",
+		Method:      http.MethodGet,
+		Path:        "/sse",
+	}, map[string]any{
+		"message": &DefaultMessage{},
+	}, func(ctx context.Context, input *struct{}, send sse.Sender) {
+		sendErr = send.Data(DefaultMessage{Message: "Hello, world!"})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/sse", nil)
+	resp := httptest.NewRecorder()
+	api.Adapter().ServeHTTP(resp, req)
+	assert.Equal(t, "no-cache", resp.Header().Get("Cache-Control"))
+	assert.ErrorIs(t, sendErr, context.Canceled)
+	assert.Empty(t, resp.Body.String())
+}
@@ -2,12 +2,15 @@ package huma
 
 import (
 	"bytes"
+	"encoding/xml"
 	"path"
 	"reflect"
 )
 
+var xmlNameType = reflect.TypeOf(xml.Name{})
+
 type schemaField struct {
-	Schema string `json:"$schema"`
+	Schema string `json:"$schema" xml:"-"`
 }
 
 // SchemaLinkTransformer is a transform that adds a `$schema` field to the
@@ -51,6 +54,14 @@ func (t *SchemaLinkTransformer) addSchemaField(oapi *OpenAPI, content *MediaType
 		return true
 	}
 
+	if typ := oapi.Components.Schemas.TypeFromRef(content.Schema.Ref); typ != nil {
+		if _, ok := reflect.PointerTo(typ).MethodByName("ProtoReflect"); ok {
+			// Generated protobuf messages are marshaled from their internal state
+			// rather than their fields, so they can't be copied into a wrapper.
+			return true
+		}
+	}
+
 	schema := oapi.Components.Schemas.SchemaFromRef(content.Schema.Ref)
 	if schema.Type != TypeObject || (schema.Properties != nil && schema.Properties["$schema"] != nil) {
 		return true
@@ -125,6 +136,16 @@ func (t *SchemaLinkTransformer) OnAddOperation(oapi *OpenAPI, op *Operation) {
 				}
 			}
 
+			if _, ok := typ.FieldByName("XMLName"); !ok {
+				// Keep the original element name when marshaling as XML, as the new
+				// type is anonymous.
+				fields = append(fields, reflect.StructField{
+					Name: "XMLName",
+					Type: xmlNameType,
+					Tag:  reflect.StructTag(`json:"-" cbor:"-" xml:"` + path.Base(content.Schema.Ref) + `"`),
+				})
+			}
+
 			newType := reflect.StructOf(fields)
 			info := t.types[typ]
 			info.t = newType
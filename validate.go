@@ -1,6 +1,7 @@
 package huma
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -11,6 +12,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode/utf8"
 	"unsafe"
@@ -181,80 +183,139 @@ func (r *ValidateResult) Reset() {
 	r.Errors = r.Errors[:0]
 }
 
-func validateFormat(path *PathBuffer, str string, s *Schema, res *ValidateResult) {
-	switch s.Format {
-	case "date-time":
-		found := false
+// FormatValidator checks whether a string value matches a named format,
+// returning an error describing the problem if it does not. The error message
+// is used as the validation error message.
+type FormatValidator func(value string) error
+
+var formats = map[string]FormatValidator{
+	"date-time": func(value string) error {
 		for _, format := range []string{time.RFC3339, time.RFC3339Nano} {
-			if _, err := time.Parse(format, str); err == nil {
-				found = true
-				break
+			if _, err := time.Parse(format, value); err == nil {
+				return nil
 			}
 		}
-		if !found {
-			res.Add(path, str, "expected string to be RFC 3339 date-time")
-		}
-	case "date-time-http":
-		if _, err := time.Parse(time.RFC1123, str); err != nil {
-			res.Add(path, str, "expected string to be RFC 1123 date-time")
-		}
-	case "date":
-		if _, err := time.Parse("2006-01-02", str); err != nil {
-			res.Add(path, str, "expected string to be RFC 3339 date")
-		}
-	case "time":
-		if _, err := time.Parse("15:04:05", str); err != nil {
-			if _, err := time.Parse("15:04:05Z07:00", str); err != nil {
-				res.Add(path, str, "expected string to be RFC 3339 time")
+		return errors.New("expected string to be RFC 3339 date-time")
+	},
+	"date-time-http": func(value string) error {
+		if _, err := time.Parse(time.RFC1123, value); err != nil {
+			return errors.New("expected string to be RFC 1123 date-time")
+		}
+		return nil
+	},
+	"date": func(value string) error {
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return errors.New("expected string to be RFC 3339 date")
+		}
+		return nil
+	},
+	"time": func(value string) error {
+		if _, err := time.Parse("15:04:05", value); err != nil {
+			if _, err := time.Parse("15:04:05Z07:00", value); err != nil {
+				return errors.New("expected string to be RFC 3339 time")
 			}
 		}
-		// TODO: duration
-	case "email", "idn-email":
-		if _, err := mail.ParseAddress(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 5322 email: %v", err)
-		}
-	case "hostname":
-		if !(rxHostname.MatchString(str) && len(str) < 256) {
-			res.Add(path, str, "expected string to be RFC 5890 hostname")
-		}
+		return nil
+	},
+	// TODO: duration
+	"email":     validateEmail,
+	"idn-email": validateEmail,
+	"hostname": func(value string) error {
+		if !(rxHostname.MatchString(value) && len(value) < 256) {
+			return errors.New("expected string to be RFC 5890 hostname")
+		}
+		return nil
+	},
 	// TODO: proper idn-hostname support... need to figure out how.
-	case "ipv4":
-		if ip := net.ParseIP(str); ip == nil || ip.To4() == nil {
-			res.Add(path, str, "expected string to be RFC 2673 ipv4")
-		}
-	case "ipv6":
-		if ip := net.ParseIP(str); ip == nil || ip.To16() == nil {
-			res.Add(path, str, "expected string to be RFC 2373 ipv6")
-		}
-	case "uri", "uri-reference", "iri", "iri-reference":
-		if _, err := url.Parse(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 3986 uri: %v", err)
-		}
-		// TODO: check if it's actually a reference?
-	case "uuid":
-		if err := validateUUID(str); err != nil {
-			res.Addf(path, str, "expected string to be RFC 4122 uuid: %v", err)
-		}
-	case "uri-template":
-		u, err := url.Parse(str)
+	"ipv4": func(value string) error {
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+			return errors.New("expected string to be RFC 2673 ipv4")
+		}
+		return nil
+	},
+	"ipv6": func(value string) error {
+		if ip := net.ParseIP(value); ip == nil || ip.To16() == nil {
+			return errors.New("expected string to be RFC 2373 ipv6")
+		}
+		return nil
+	},
+	// TODO: check if it's actually a reference?
+	"uri":           validateURI,
+	"uri-reference": validateURI,
+	"iri":           validateURI,
+	"iri-reference": validateURI,
+	"uuid": func(value string) error {
+		if err := validateUUID(value); err != nil {
+			return fmt.Errorf("expected string to be RFC 4122 uuid: %w", err)
+		}
+		return nil
+	},
+	"uri-template": func(value string) error {
+		u, err := url.Parse(value)
 		if err != nil {
-			res.Addf(path, str, "expected string to be RFC 3986 uri: %v", err)
-			return
+			return fmt.Errorf("expected string to be RFC 3986 uri: %w", err)
 		}
 		if !rxURITemplate.MatchString(u.Path) {
-			res.Add(path, str, "expected string to be RFC 6570 uri-template")
-		}
-	case "json-pointer":
-		if !rxJSONPointer.MatchString(str) {
-			res.Add(path, str, "expected string to be RFC 6901 json-pointer")
-		}
-	case "relative-json-pointer":
-		if !rxRelJSONPointer.MatchString(str) {
-			res.Add(path, str, "expected string to be RFC 6901 relative-json-pointer")
-		}
-	case "regex":
-		if _, err := regexp.Compile(str); err != nil {
-			res.Addf(path, str, "expected string to be regex: %v", err)
+			return errors.New("expected string to be RFC 6570 uri-template")
+		}
+		return nil
+	},
+	"json-pointer": func(value string) error {
+		if !rxJSONPointer.MatchString(value) {
+			return errors.New("expected string to be RFC 6901 json-pointer")
+		}
+		return nil
+	},
+	"relative-json-pointer": func(value string) error {
+		if !rxRelJSONPointer.MatchString(value) {
+			return errors.New("expected string to be RFC 6901 relative-json-pointer")
+		}
+		return nil
+	},
+	"regex": func(value string) error {
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("expected string to be regex: %w", err)
+		}
+		return nil
+	},
+}
+
+func validateEmail(value string) error {
+	if _, err := mail.ParseAddress(value); err != nil {
+		return fmt.Errorf("expected string to be RFC 5322 email: %w", err)
+	}
+	return nil
+}
+
+func validateURI(value string) error {
+	if _, err := url.Parse(value); err != nil {
+		return fmt.Errorf("expected string to be RFC 3986 uri: %w", err)
+	}
+	return nil
+}
+
+// RegisterFormat registers a validator for a named string format, which is
+// used by `Validate` for any string schema with that `format`. Registering a
+// built-in format like `uuid` or `email` replaces its validator. Formats are
+// not safe to register concurrently with validation, so this should be done
+// at startup before registering operations.
+//
+//	huma.RegisterFormat("semver", func(value string) error {
+//		if !semver.IsValid("v" + value) {
+//			return errors.New("expected string to be a semantic version")
+//		}
+//		return nil
+//	})
+func RegisterFormat(name string, validator FormatValidator) {
+	formats[name] = validator
+}
+
+func validateFormat(path *PathBuffer, str string, s *Schema, res *ValidateResult) {
+	// Unknown formats are annotations only and are not validated.
+	if validator := formats[s.Format]; validator != nil {
+		if err := validator(str); err != nil {
+			msg := err.Error()
+			res.addMessage(path, str, msg, MsgFormat, s.Format, msg)
 		}
 	}
 }
@@ -266,17 +327,48 @@ func validateOneOf(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v
 		Validate(r, sub, path, mode, v, subRes)
 		if len(subRes.Errors) == 0 {
 			if found {
-				res.Add(path, v, "expected value to match exactly one schema but matched multiple")
+				res.addMessage(path, v, messages[DefaultLanguage][MsgOneOfMultiple], MsgOneOfMultiple)
 			}
 			found = true
 		}
 		subRes.Reset()
 	}
 	if !found {
-		res.Add(path, v, "expected value to match exactly one schema but matched none")
+		res.addMessage(path, v, messages[DefaultLanguage][MsgOneOfNone], MsgOneOfNone)
 	}
 }
 
+// validateDiscriminator validates the value against the member schema selected
+// by the discriminator property, returning false if no selection could be made
+// and the normal `oneOf`/`anyOf` validation should be used instead.
+func validateDiscriminator(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any, res *ValidateResult) bool {
+	var value any
+	switch m := v.(type) {
+	case map[string]any:
+		value = m[s.Discriminator.PropertyName]
+	case map[any]any:
+		value = m[s.Discriminator.PropertyName]
+	default:
+		return false
+	}
+
+	str, ok := value.(string)
+	if !ok || len(s.Discriminator.Mapping) == 0 {
+		return false
+	}
+
+	ref, ok := s.Discriminator.Mapping[str]
+	if !ok {
+		path.Push(s.Discriminator.PropertyName)
+		res.addMessage(path, value, s.msgDiscriminator, MsgDiscriminator, s.Discriminator.PropertyName, discriminatorValues(s.Discriminator))
+		path.Pop()
+		return true
+	}
+
+	Validate(r, r.SchemaFromRef(ref), path, mode, v, res)
+	return true
+}
+
 func validateAnyOf(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any, res *ValidateResult) {
 	matches := 0
 	subRes := &ValidateResult{}
@@ -289,7 +381,7 @@ func validateAnyOf(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v
 	}
 
 	if matches == 0 {
-		res.Add(path, v, "expected value to match at least one schema but matched none")
+		res.addMessage(path, v, messages[DefaultLanguage][MsgAnyOfNone], MsgAnyOfNone)
 	}
 }
 
@@ -315,12 +407,16 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		s = r.SchemaFromRef(s.Ref)
 	}
 
-	if s.OneOf != nil {
-		validateOneOf(r, s, path, mode, v, res)
-	}
+	if s.Discriminator != nil && validateDiscriminator(r, s, path, mode, v, res) {
+		// The discriminator selected a single schema to validate against.
+	} else {
+		if s.OneOf != nil {
+			validateOneOf(r, s, path, mode, v, res)
+		}
 
-	if s.AnyOf != nil {
-		validateAnyOf(r, s, path, mode, v, res)
+		if s.AnyOf != nil {
+			validateAnyOf(r, s, path, mode, v, res)
+		}
 	}
 
 	if s.AllOf != nil {
@@ -333,7 +429,20 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		subRes := &ValidateResult{}
 		Validate(r, s.Not, path, mode, v, subRes)
 		if len(subRes.Errors) == 0 {
-			res.Add(path, v, "expected value to not match schema")
+			res.addMessage(path, v, messages[DefaultLanguage][MsgNotMatch], MsgNotMatch)
+		}
+	}
+
+	if s.If != nil {
+		// Only the `then` or `else` errors are reported, never those from `if`.
+		subRes := &ValidateResult{}
+		Validate(r, s.If, path, mode, v, subRes)
+		if len(subRes.Errors) == 0 {
+			if s.Then != nil {
+				Validate(r, s.Then, path, mode, v, res)
+			}
+		} else if s.Else != nil {
+			Validate(r, s.Else, path, mode, v, res)
 		}
 	}
 
@@ -344,7 +453,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 	switch s.Type {
 	case TypeBoolean:
 		if _, ok := v.(bool); !ok {
-			res.Add(path, v, "expected boolean")
+			res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedBoolean], MsgExpectedBoolean)
 			return
 		}
 	case TypeNumber, TypeInteger:
@@ -375,34 +484,47 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			num = float64(v)
 		case uint64:
 			num = float64(v)
+		case json.Number:
+			// Support values decoded with `UseNumber()` to preserve precision.
+			f, err := v.Float64()
+			if err != nil {
+				res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedNumber], MsgExpectedNumber)
+				return
+			}
+			num = f
 		default:
-			res.Add(path, v, "expected number")
+			res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedNumber], MsgExpectedNumber)
+			return
+		}
+
+		if s.Type == TypeInteger && num != math.Trunc(num) {
+			res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedInteger], MsgExpectedInteger)
 			return
 		}
 
 		if s.Minimum != nil {
 			if num < *s.Minimum {
-				res.Addf(path, v, s.msgMinimum)
+				res.addMessage(path, v, s.msgMinimum, MsgMinimum, *s.Minimum)
 			}
 		}
 		if s.ExclusiveMinimum != nil {
 			if num <= *s.ExclusiveMinimum {
-				res.Addf(path, v, s.msgExclusiveMinimum)
+				res.addMessage(path, v, s.msgExclusiveMinimum, MsgExclusiveMinimum, *s.ExclusiveMinimum)
 			}
 		}
 		if s.Maximum != nil {
 			if num > *s.Maximum {
-				res.Add(path, v, s.msgMaximum)
+				res.addMessage(path, v, s.msgMaximum, MsgMaximum, *s.Maximum)
 			}
 		}
 		if s.ExclusiveMaximum != nil {
 			if num >= *s.ExclusiveMaximum {
-				res.Addf(path, v, s.msgExclusiveMaximum)
+				res.addMessage(path, v, s.msgExclusiveMaximum, MsgExclusiveMaximum, *s.ExclusiveMaximum)
 			}
 		}
 		if s.MultipleOf != nil {
 			if math.Mod(num, *s.MultipleOf) != 0 {
-				res.Addf(path, v, s.msgMultipleOf)
+				res.addMessage(path, v, s.msgMultipleOf, MsgMultipleOf, *s.MultipleOf)
 			}
 		}
 	case TypeString:
@@ -411,24 +533,28 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			if b, ok := v.([]byte); ok {
 				str = *(*string)(unsafe.Pointer(&b))
 			} else {
-				res.Add(path, v, "expected string")
+				res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedString], MsgExpectedString)
 				return
 			}
 		}
 
 		if s.MinLength != nil {
 			if utf8.RuneCountInString(str) < *s.MinLength {
-				res.Addf(path, str, s.msgMinLength)
+				res.addMessage(path, str, s.msgMinLength, MsgMinLength, *s.MinLength)
 			}
 		}
 		if s.MaxLength != nil {
 			if utf8.RuneCountInString(str) > *s.MaxLength {
-				res.Add(path, str, s.msgMaxLength)
+				res.addMessage(path, str, s.msgMaxLength, MsgMaxLength, *s.MaxLength)
 			}
 		}
 		if s.patternRe != nil {
 			if !s.patternRe.MatchString(str) {
-				res.Add(path, v, s.msgPattern)
+				if s.PatternDescription != "" {
+					res.addMessage(path, v, s.msgPattern, MsgPatternDescription, s.PatternDescription)
+				} else {
+					res.addMessage(path, v, s.msgPattern, MsgPattern, s.Pattern)
+				}
 			}
 		}
 
@@ -438,7 +564,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 
 		if s.ContentEncoding == "base64" {
 			if !rxBase64.MatchString(str) {
-				res.Add(path, str, "expected string to be base64 encoded")
+				res.addMessage(path, str, messages[DefaultLanguage][MsgBase64], MsgBase64)
 			}
 		}
 	case TypeArray:
@@ -471,7 +597,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		case []float64:
 			handleArray(r, s, path, mode, res, arr)
 		default:
-			res.Add(path, v, "expected array")
+			res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedArray], MsgExpectedArray)
 			return
 		}
 	case TypeObject:
@@ -480,7 +606,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 		} else if vv, ok := v.(map[any]any); ok {
 			handleMapAny(r, s, path, mode, vv, res)
 		} else {
-			res.Add(path, v, "expected object")
+			res.addMessage(path, v, messages[DefaultLanguage][MsgExpectedObject], MsgExpectedObject)
 			return
 		}
 	}
@@ -494,7 +620,7 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 			}
 		}
 		if !found {
-			res.Add(path, v, s.msgEnum)
+			res.addMessage(path, v, s.msgEnum, MsgEnum, joinValues(s.Enum))
 		}
 	}
 }
@@ -502,12 +628,12 @@ func Validate(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, v any,
 func handleArray[T any](r Registry, s *Schema, path *PathBuffer, mode ValidateMode, res *ValidateResult, arr []T) {
 	if s.MinItems != nil {
 		if len(arr) < *s.MinItems {
-			res.Addf(path, arr, s.msgMinItems)
+			res.addMessage(path, arr, s.msgMinItems, MsgMinItems, *s.MinItems)
 		}
 	}
 	if s.MaxItems != nil {
 		if len(arr) > *s.MaxItems {
-			res.Addf(path, arr, s.msgMaxItems)
+			res.addMessage(path, arr, s.msgMaxItems, MsgMaxItems, *s.MaxItems)
 		}
 	}
 
@@ -515,7 +641,7 @@ func handleArray[T any](r Registry, s *Schema, path *PathBuffer, mode ValidateMo
 		seen := make(map[any]struct{}, len(arr))
 		for _, item := range arr {
 			if _, ok := seen[item]; ok {
-				res.Add(path, arr, "expected array items to be unique")
+				res.addMessage(path, arr, messages[DefaultLanguage][MsgUniqueItems], MsgUniqueItems)
 			}
 			seen[item] = struct{}{}
 		}
@@ -531,12 +657,12 @@ func handleArray[T any](r Registry, s *Schema, path *PathBuffer, mode ValidateMo
 func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m map[string]any, res *ValidateResult) {
 	if s.MinProperties != nil {
 		if len(m) < *s.MinProperties {
-			res.Add(path, m, s.msgMinProperties)
+			res.addMessage(path, m, s.msgMinProperties, MsgMinProperties, *s.MinProperties)
 		}
 	}
 	if s.MaxProperties != nil {
 		if len(m) > *s.MaxProperties {
-			res.Add(path, m, s.msgMaxProperties)
+			res.addMessage(path, m, s.msgMaxProperties, MsgMaxProperties, *s.MaxProperties)
 		}
 	}
 
@@ -559,7 +685,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 
 		// Be stricter for responses, enabling validation of the server if desired.
 		if mode == ModeReadFromServer && writeOnly && m[k] != nil && !reflect.ValueOf(m[k]).IsZero() {
-			res.Add(path, m[k], "write only property is non-zero")
+			res.addMessage(path, m[k], messages[DefaultLanguage][MsgWriteOnly], MsgWriteOnly)
 			continue
 		}
 
@@ -572,7 +698,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 				// These are not required for the current mode.
 				continue
 			}
-			res.Add(path, m, s.msgRequired[k])
+			res.addMessage(path, m, s.msgRequired[k], MsgRequired, k)
 			continue
 		}
 
@@ -588,7 +714,7 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 					continue
 				}
 
-				res.Add(path, m, s.msgDependentRequired[k][dependent])
+				res.addMessage(path, m, s.msgDependentRequired[k][dependent], MsgDependentRequired, dependent, k)
 			}
 		}
 
@@ -600,9 +726,9 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 	if addl, ok := s.AdditionalProperties.(bool); ok && !addl {
 		for k := range m {
 			// No additional properties allowed.
-			if _, ok := s.Properties[k]; !ok {
+			if _, ok := s.Properties[k]; !ok && !s.hiddenProperties[k] {
 				path.Push(k)
-				res.Add(path, m, "unexpected property")
+				res.addMessage(path, m, messages[DefaultLanguage][MsgUnexpectedProperty], MsgUnexpectedProperty)
 				path.Pop()
 			}
 		}
@@ -625,12 +751,12 @@ func handleMapString(r Registry, s *Schema, path *PathBuffer, mode ValidateMode,
 func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m map[any]any, res *ValidateResult) {
 	if s.MinProperties != nil {
 		if len(m) < *s.MinProperties {
-			res.Add(path, m, s.msgMinProperties)
+			res.addMessage(path, m, s.msgMinProperties, MsgMinProperties, *s.MinProperties)
 		}
 	}
 	if s.MaxProperties != nil {
 		if len(m) > *s.MaxProperties {
-			res.Add(path, m, s.msgMaxProperties)
+			res.addMessage(path, m, s.msgMaxProperties, MsgMaxProperties, *s.MaxProperties)
 		}
 	}
 
@@ -653,7 +779,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 
 		// Be stricter for responses, enabling validation of the server if desired.
 		if mode == ModeReadFromServer && writeOnly && m[k] != nil && !reflect.ValueOf(m[k]).IsZero() {
-			res.Add(path, m[k], "write only property is non-zero")
+			res.addMessage(path, m[k], messages[DefaultLanguage][MsgWriteOnly], MsgWriteOnly)
 			continue
 		}
 
@@ -666,7 +792,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 				// These are not required for the current mode.
 				continue
 			}
-			res.Add(path, m, s.msgRequired[k])
+			res.addMessage(path, m, s.msgRequired[k], MsgRequired, k)
 			continue
 		}
 
@@ -682,7 +808,7 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 					continue
 				}
 
-				res.Add(path, m, s.msgDependentRequired[k][dependent])
+				res.addMessage(path, m, s.msgDependentRequired[k][dependent], MsgDependentRequired, dependent, k)
 			}
 		}
 
@@ -700,9 +826,9 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 			} else {
 				kStr = fmt.Sprint(k)
 			}
-			if _, ok := s.Properties[kStr]; !ok {
+			if _, ok := s.Properties[kStr]; !ok && !s.hiddenProperties[kStr] {
 				path.Push(kStr)
-				res.Add(path, m, "unexpected property")
+				res.addMessage(path, m, messages[DefaultLanguage][MsgUnexpectedProperty], MsgUnexpectedProperty)
 				path.Pop()
 			}
 		}
@@ -725,10 +851,10 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 }
 
 // ModelValidator is a utility for validating e.g. JSON loaded data against a
-// Go struct model. It is not goroutine-safe and should not be used in HTTP
-// handlers! Schemas are generated on-the-fly on first use and re-used on
-// subsequent calls. This utility can be used to easily validate data outside
-// of the normal request/response flow, for example on application startup:
+// Go struct model. It is safe for concurrent use. Schemas are generated
+// on-the-fly on first use and re-used on subsequent calls. This utility can be
+// used to easily validate data outside of the normal request/response flow,
+// for example on application startup:
 //
 //	type MyExample struct {
 //		Name string `json:"name" maxLength:"5"`
@@ -744,9 +870,13 @@ func handleMapAny(r Registry, s *Schema, path *PathBuffer, mode ValidateMode, m
 //		fmt.Println("Validation error", errs)
 //	}
 type ModelValidator struct {
+	// mu guards the registry, which is written while generating a schema and
+	// read while validating.
+	mu       sync.RWMutex
 	registry Registry
-	pb       *PathBuffer
-	result   *ValidateResult
+
+	// schemas caches the generated schema for each `reflect.Type`.
+	schemas sync.Map
 }
 
 // NewModelValidator creates a new model validator with all the components
@@ -754,8 +884,29 @@ type ModelValidator struct {
 func NewModelValidator() *ModelValidator {
 	return &ModelValidator{
 		registry: NewMapRegistry("#/components/schemas/", DefaultSchemaNamer),
-		pb:       NewPathBuffer([]byte(""), 0),
-		result:   &ValidateResult{},
+	}
+}
+
+// Reset drops all cached schemas so they are generated again on next use.
+// Call it after changing anything which affects schema generation, like the
+// model types' `SchemaProvider` implementations.
+func (v *ModelValidator) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.registry = NewMapRegistry("#/components/schemas/", DefaultSchemaNamer)
+	v.schemas.Range(func(key, _ any) bool {
+		v.schemas.Delete(key)
+		return true
+	})
+}
+
+// generate creates and caches the schema for the type unless it already
+// exists.
+func (v *ModelValidator) generate(typ reflect.Type) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.schemas.Load(typ); !ok {
+		v.schemas.Store(typ, v.registry.Schema(typ, true, typ.Name()))
 	}
 }
 
@@ -777,15 +928,30 @@ func NewModelValidator() *ModelValidator {
 //		fmt.Println("Validation error", errs)
 //	}
 func (v *ModelValidator) Validate(typ reflect.Type, value any) []error {
-	v.pb.Reset()
-	v.result.Reset()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	// Schemas are generated once per type and reused for later validations.
+	s, ok := v.schemas.Load(typ)
+	for !ok {
+		v.mu.RUnlock()
+		v.generate(typ)
+		v.mu.RLock()
+		s, ok = v.schemas.Load(typ)
+	}
 
-	s := v.registry.Schema(typ, true, typ.Name())
+	deps := validatePool.Get().(*validateDeps)
+	defer func() {
+		deps.pb.Reset()
+		deps.res.Reset()
+		validatePool.Put(deps)
+	}()
 
-	Validate(v.registry, s, v.pb, ModeReadFromServer, value, v.result)
+	Validate(v.registry, s.(*Schema), deps.pb, ModeReadFromServer, value, deps.res)
 
-	if len(v.result.Errors) > 0 {
-		return v.result.Errors
+	if len(deps.res.Errors) > 0 {
+		// The result is reused, so return a copy of its errors.
+		return append([]error(nil), deps.res.Errors...)
 	}
 	return nil
 }
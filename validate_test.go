@@ -2,9 +2,11 @@ package huma_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -55,6 +57,36 @@ var validateTests = []struct {
 		typ:   reflect.TypeOf(0),
 		input: float64(0),
 	},
+	{
+		name:  "int from float64 fraction fail",
+		typ:   reflect.TypeOf(0),
+		input: float64(1.5),
+		errs:  []string{"expected integer"},
+	},
+	{
+		name:  "int from json.Number success",
+		typ:   reflect.TypeOf(0),
+		input: json.Number("5"),
+	},
+	{
+		name: "int from json.Number minimum fail",
+		typ: reflect.TypeOf(struct {
+			Value int `json:"value" minimum:"10"`
+		}{}),
+		input: map[string]any{"value": json.Number("5")},
+		errs:  []string{"expected number >= 10"},
+	},
+	{
+		name:  "float from json.Number fraction success",
+		typ:   reflect.TypeOf(0.0),
+		input: json.Number("1.5"),
+	},
+	{
+		name:  "json.Number invalid fail",
+		typ:   reflect.TypeOf(0.0),
+		input: json.Number("abc"),
+		errs:  []string{"expected number"},
+	},
 	{
 		name:  "int from int8 success",
 		typ:   reflect.TypeOf(0),
@@ -1091,6 +1123,77 @@ var validateTests = []struct {
 			"expected property dependent3 to be present when value2 is present",
 		},
 	},
+	{
+		name: "requiredIf condition not met success",
+		typ: reflect.TypeOf(struct {
+			Method     string `json:"payment_method" enum:"card,cash"`
+			CardNumber string `json:"card_number" requiredIf:"payment_method=card"`
+		}{}),
+		input: map[string]any{"payment_method": "cash"},
+	},
+	{
+		name: "requiredIf condition met success",
+		typ: reflect.TypeOf(struct {
+			Method     string `json:"payment_method" enum:"card,cash"`
+			CardNumber string `json:"card_number" requiredIf:"payment_method=card"`
+		}{}),
+		input: map[string]any{"payment_method": "card", "card_number": "4242"},
+	},
+	{
+		name: "requiredIf failure",
+		typ: reflect.TypeOf(struct {
+			Method     string `json:"payment_method" enum:"card,cash"`
+			CardNumber string `json:"card_number" requiredIf:"payment_method=card"`
+			Expiry     string `json:"expiry" requiredIf:"payment_method=card"`
+		}{}),
+		input: map[string]any{"payment_method": "card"},
+		errs: []string{
+			"expected required property card_number to be present",
+			"expected required property expiry to be present",
+		},
+	},
+	{
+		name: "requiredIf multiple conditions failure",
+		typ: reflect.TypeOf(struct {
+			Count int    `json:"count,omitempty"`
+			Kind  string `json:"kind,omitempty"`
+			Extra string `json:"extra" requiredIf:"count=2"`
+			Other string `json:"other" requiredIf:"kind=special"`
+		}{}),
+		input: map[any]any{"count": 2.0, "kind": "special"},
+		errs: []string{
+			"expected required property extra to be present",
+			"expected required property other to be present",
+		},
+	},
+	{
+		name: "requiredIf invalid field panic",
+		typ: reflect.TypeOf(struct {
+			Extra string `json:"extra" requiredIf:"missing=2"`
+		}{}),
+		panic: "invalid requiredIf condition",
+	},
+	{
+		name: "if then else schema",
+		s: &huma.Schema{
+			Type: huma.TypeObject,
+			If: &huma.Schema{
+				Type:       huma.TypeObject,
+				Properties: map[string]*huma.Schema{"country": {Enum: []any{"US"}}},
+				Required:   []string{"country"},
+			},
+			Then: &huma.Schema{
+				Type:       huma.TypeObject,
+				Properties: map[string]*huma.Schema{"postal_code": {Type: huma.TypeString, Pattern: "^[0-9]{5}$"}},
+			},
+			Else: &huma.Schema{
+				Type:       huma.TypeObject,
+				Properties: map[string]*huma.Schema{"postal_code": {Type: huma.TypeString, MaxLength: Ptr(10)}},
+			},
+		},
+		input: map[string]any{"country": "CA", "postal_code": "K1A 0B1 XYZW"},
+		errs:  []string{"expected length <= 10"},
+	},
 	{
 		name: "dependentRequired empty success any",
 		typ: reflect.TypeOf(struct {
@@ -1300,6 +1403,77 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestRegisterFormat(t *testing.T) {
+	huma.RegisterFormat("test-even-length", func(value string) error {
+		if len(value)%2 != 0 {
+			return errors.New("expected string to have an even length")
+		}
+		return nil
+	})
+
+	type Model struct {
+		Value string `json:"value" format:"test-even-length"`
+	}
+
+	validator := huma.NewModelValidator()
+	assert.Empty(t, validator.Validate(reflect.TypeOf(Model{}), map[string]any{"value": "ab"}))
+
+	errs := validator.Validate(reflect.TypeOf(Model{}), map[string]any{"value": "abc"})
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "expected string to have an even length (value: abc)", errs[0].Error())
+}
+
+func TestModelValidatorCache(t *testing.T) {
+	type Model struct {
+		Name string `json:"name" maxLength:"5"`
+	}
+
+	typ := reflect.TypeOf(Model{})
+	value := map[string]any{"name": "foo"}
+	validator := huma.NewModelValidator()
+	assert.Empty(t, validator.Validate(typ, value))
+
+	if raceEnabled {
+		return
+	}
+
+	// Once the schema is cached, successful validation should not allocate.
+	allocs := testing.AllocsPerRun(10, func() {
+		validator.Validate(typ, value)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestModelValidatorConcurrent(t *testing.T) {
+	type Child struct {
+		Value int `json:"value" minimum:"1"`
+	}
+	type Model struct {
+		Name  string `json:"name" maxLength:"5"`
+		Child Child  `json:"child"`
+	}
+	type Other struct {
+		Count int `json:"count" maximum:"10"`
+	}
+
+	// Run with `-race` to check concurrent use while schemas are generated.
+	validator := huma.NewModelValidator()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%5 == 0 {
+				validator.Reset()
+			}
+			assert.Empty(t, validator.Validate(reflect.TypeOf(Model{}), map[string]any{"name": "foo", "child": map[string]any{"value": 1.0}}))
+			assert.Len(t, validator.Validate(reflect.TypeOf(Model{}), map[string]any{"name": "foobar", "child": map[string]any{"value": 0.0}}), 2)
+			assert.Len(t, validator.Validate(reflect.TypeOf(Other{}), map[string]any{"count": 11.0}), 1)
+		}(i)
+	}
+	wg.Wait()
+}
+
 func ExampleModelValidator() {
 	// Define a type you want to validate.
 	type Model struct {
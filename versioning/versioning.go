@@ -0,0 +1,305 @@
+// Package versioning provides support for running multiple versions of an API
+// side by side. Each version is its own `huma.API` with a separate OpenAPI
+// document, sharing the same router. Requests are routed to a version either
+// by a path prefix like `/v1` or by a request header like `Accept-Version`.
+//
+// Deprecated versions automatically document their operations as deprecated
+// and send `Deprecation`, `Sunset`, and `Link` response headers so clients
+// can find out about upcoming removals.
+package versioning
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// DefaultHeader is the default request header used to select a version with
+// the `Header` strategy.
+const DefaultHeader = "Accept-Version"
+
+// Strategy determines how requests are routed to a version.
+type Strategy int
+
+const (
+	// PathPrefix registers each version's operations, OpenAPI, docs, and
+	// schemas under a `/{version}` path prefix, e.g. `/v1/things`.
+	PathPrefix Strategy = iota
+
+	// Header routes requests to a version using a request header, e.g.
+	// `Accept-Version: v2`, so versions share the same operation paths. Each
+	// version's OpenAPI, docs, and schemas are still served under a
+	// `/{version}` path prefix so they can be viewed in a browser.
+	Header
+)
+
+// Options configures how versions are routed.
+type Options struct {
+	// Strategy used to route requests to versions. Defaults to `PathPrefix`.
+	Strategy Strategy
+
+	// Header is the request header used to select a version with the
+	// `Header` strategy. Defaults to `Accept-Version`.
+	Header string
+
+	// Default is the version used by the `Header` strategy when a request
+	// does not select one. Defaults to the most recently added version.
+	Default string
+}
+
+// VersionOptions configures a single version.
+type VersionOptions struct {
+	// Deprecation marks the version as deprecated from the given time. Its
+	// operations are documented as deprecated and responses include an
+	// RFC 9745 `Deprecation` header.
+	Deprecation time.Time
+
+	// Sunset is when the version will stop working, sent to clients as an
+	// RFC 8594 `Sunset` header.
+	Sunset time.Time
+
+	// Link is a URL with more information about the deprecation, e.g. a
+	// migration guide, sent as a `Link` header with `rel="deprecation"`.
+	Link string
+}
+
+// Versions manages the versions of an API which share a router.
+type Versions struct {
+	adapter huma.Adapter
+	config  huma.Config
+	opts    Options
+	names   []string
+	apis    map[string]huma.API
+
+	// routes maps `METHOD /path` to each version's handler when using the
+	// `Header` strategy.
+	routes map[string]map[string]route
+}
+
+// route is a version's operation & handler for a shared path.
+type route struct {
+	op      *huma.Operation
+	handler func(huma.Context)
+}
+
+// New creates a set of API versions using the given router adapter. The
+// config is used as a template for each version's API and OpenAPI document.
+//
+//	versions := versioning.New(humachi.NewAdapter(router), config, versioning.Options{})
+//	v1 := versions.Version("v1", versioning.VersionOptions{
+//		Deprecation: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Sunset:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+//	})
+//	v2 := versions.Version("v2", versioning.VersionOptions{})
+//
+//	huma.Get(v1, "/things", listThingsV1)
+//	huma.Get(v2, "/things", listThingsV2)
+func New(adapter huma.Adapter, config huma.Config, opts Options) *Versions {
+	if opts.Header == "" {
+		opts.Header = DefaultHeader
+	}
+	return &Versions{
+		adapter: adapter,
+		config:  config,
+		opts:    opts,
+		apis:    map[string]huma.API{},
+		routes:  map[string]map[string]route{},
+	}
+}
+
+// Version creates a new version of the API with the given name, e.g. `v1`.
+// Register the version's operations on the returned API. The name is used as
+// the path prefix and header value, and as the OpenAPI `info.version`.
+// Panics if the version already exists.
+func (v *Versions) Version(name string, opts VersionOptions) huma.API {
+	if name == "" || strings.Contains(name, "/") {
+		panic(fmt.Errorf("invalid version name %q", name))
+	}
+	if v.apis[name] != nil {
+		panic(fmt.Errorf("duplicate version %q", name))
+	}
+
+	prefix := "/" + name
+	config := v.config
+
+	// Each version gets its own OpenAPI document & schema registry.
+	oapi := huma.OpenAPI{}
+	if config.OpenAPI != nil {
+		oapi = *config.OpenAPI
+	}
+	oapi.Paths = nil
+	oapi.OnAddOperation = append([]huma.AddOpFunc{}, oapi.OnAddOperation...)
+	if oapi.Info != nil {
+		info := *oapi.Info
+		info.Version = name
+		oapi.Info = &info
+	}
+	components := huma.Components{}
+	if oapi.Components != nil {
+		components = *oapi.Components
+	}
+	components.Schemas = huma.NewMapRegistry("#/components/schemas/", huma.DefaultSchemaNamer)
+	oapi.Components = &components
+	config.OpenAPI = &oapi
+	config.Transformers = append([]huma.Transformer{}, config.Transformers...)
+	config.CreateHooks = append([]func(huma.Config) huma.Config{}, config.CreateHooks...)
+
+	adapter := &versionAdapter{Adapter: v.adapter, versions: v, name: name}
+	if v.opts.Strategy == PathPrefix {
+		adapter.prefix = prefix
+		servers := make([]*huma.Server, 0, len(oapi.Servers))
+		for _, server := range oapi.Servers {
+			s := *server
+			s.URL = strings.TrimSuffix(s.URL, "/") + prefix
+			servers = append(servers, &s)
+		}
+		if len(servers) == 0 {
+			servers = append(servers, &huma.Server{URL: prefix})
+		}
+		oapi.Servers = servers
+	} else {
+		// Docs are served directly under the version prefix as browsers can't
+		// easily send a version header.
+		adapter.direct = true
+		for _, p := range []*string{&config.OpenAPIPath, &config.DocsPath, &config.SchemasPath} {
+			if *p != "" {
+				*p = prefix + *p
+			}
+		}
+		header := v.opts.Header
+		oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+			op.Parameters = append(op.Parameters, &huma.Param{
+				Name:        header,
+				In:          "header",
+				Description: "API version to use.",
+				Schema:      &huma.Schema{Type: huma.TypeString, Enum: []any{name}},
+			})
+		})
+	}
+
+	api := huma.NewAPI(config, adapter)
+	adapter.direct = false
+
+	if !opts.Deprecation.IsZero() {
+		oapi := api.OpenAPI()
+		oapi.OnAddOperation = append(oapi.OnAddOperation, func(oapi *huma.OpenAPI, op *huma.Operation) {
+			op.Deprecated = true
+		})
+	}
+	if !opts.Deprecation.IsZero() || !opts.Sunset.IsZero() {
+		api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+			if !opts.Deprecation.IsZero() {
+				ctx.SetHeader("Deprecation", "@"+strconv.FormatInt(opts.Deprecation.Unix(), 10))
+			}
+			if !opts.Sunset.IsZero() {
+				ctx.SetHeader("Sunset", opts.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if opts.Link != "" {
+				ctx.AppendHeader("Link", "<"+opts.Link+">; rel=\"deprecation\"")
+			}
+			next(ctx)
+		})
+	}
+
+	v.names = append(v.names, name)
+	v.apis[name] = api
+	return api
+}
+
+// API returns the API for the named version, or `nil` if it does not exist.
+func (v *Versions) API(name string) huma.API {
+	return v.apis[name]
+}
+
+// Names returns the names of all versions in the order they were added.
+func (v *Versions) Names() []string {
+	return append([]string{}, v.names...)
+}
+
+// defaultVersion returns the version used when a request doesn't select one.
+func (v *Versions) defaultVersion() string {
+	if v.opts.Default != "" {
+		return v.opts.Default
+	}
+	if len(v.names) == 0 {
+		return ""
+	}
+	return v.names[len(v.names)-1]
+}
+
+// handle registers a version's handler for the `Header` strategy. The first
+// version to register a method & path adds a route to the router which
+// dispatches to the selected version.
+func (v *Versions) handle(name string, op *huma.Operation, handler func(huma.Context)) {
+	key := op.Method + " " + op.Path
+	routes := v.routes[key]
+	if routes == nil {
+		routes = map[string]route{}
+		v.routes[key] = routes
+		v.adapter.Handle(op, func(ctx huma.Context) {
+			ctx.AppendHeader("Vary", v.opts.Header)
+
+			selected := ctx.Header(v.opts.Header)
+			if selected == "" {
+				selected = v.defaultVersion()
+			}
+
+			r, ok := routes[selected]
+			if !ok {
+				api := v.apis[v.defaultVersion()]
+				if v.apis[selected] == nil {
+					huma.WriteErr(api, ctx, http.StatusBadRequest, fmt.Sprintf("unknown API version %q", selected), &huma.ErrorDetail{
+						Location: "header." + v.opts.Header,
+						Value:    selected,
+					})
+					return
+				}
+				huma.WriteErr(api, ctx, http.StatusNotFound, fmt.Sprintf("not found in API version %q", selected))
+				return
+			}
+			r.handler(&operationContext{humaContext: ctx, op: r.op})
+		})
+	}
+	routes[name] = route{op: op, handler: handler}
+}
+
+// versionAdapter registers a version's routes with the shared router.
+type versionAdapter struct {
+	huma.Adapter
+	versions *Versions
+	name     string
+
+	// prefix is prepended to each route path for the `PathPrefix` strategy.
+	prefix string
+
+	// direct registers routes without header dispatch, used for the docs
+	// routes with the `Header` strategy.
+	direct bool
+}
+
+func (a *versionAdapter) Handle(op *huma.Operation, handler func(huma.Context)) {
+	if a.prefix != "" || a.direct {
+		routed := *op
+		routed.Path = a.prefix + op.Path
+		a.Adapter.Handle(&routed, handler)
+		return
+	}
+	a.versions.handle(a.name, op, handler)
+}
+
+type humaContext huma.Context
+
+// operationContext returns the selected version's operation for requests
+// dispatched via a shared route.
+type operationContext struct {
+	humaContext
+	op *huma.Operation
+}
+
+func (c *operationContext) Operation() *huma.Operation {
+	return c.op
+}
@@ -0,0 +1,187 @@
+package versioning
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type thingV1 struct {
+	Name string `json:"name"`
+}
+
+type thingV2 struct {
+	Title string `json:"title"`
+}
+
+func setup(t *testing.T, opts Options) (http.Handler, *Versions) {
+	router := chi.NewMux()
+	versions := New(humatest.NewAdapter(router), huma.DefaultConfig("Test", "1.0.0"), opts)
+
+	v1 := versions.Version("v1", VersionOptions{
+		Deprecation: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Link:        "https://example.com/migrate",
+	})
+	v2 := versions.Version("v2", VersionOptions{})
+
+	huma.Get(v1, "/things", func(ctx context.Context, input *struct{}) (*struct{ Body thingV1 }, error) {
+		return &struct{ Body thingV1 }{thingV1{Name: "one"}}, nil
+	})
+	huma.Get(v1, "/legacy", func(ctx context.Context, input *struct{}) (*struct{ Body string }, error) {
+		return &struct{ Body string }{"legacy"}, nil
+	})
+
+	var op *huma.Operation
+	v2.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		op = ctx.Operation()
+		next(ctx)
+	})
+	huma.Get(v2, "/things", func(ctx context.Context, input *struct{}) (*struct{ Body thingV2 }, error) {
+		assert.Equal(t, "get-things", op.OperationID)
+		assert.Equal(t, "v2", versions.API("v2").OpenAPI().Info.Version)
+		return &struct{ Body thingV2 }{thingV2{Title: "two"}}, nil
+	})
+
+	return router, versions
+}
+
+func request(handler http.Handler, path string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for _, h := range headers {
+		name, value, _ := strings.Cut(h, ": ")
+		req.Header.Set(name, value)
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestPathPrefix(t *testing.T) {
+	router, versions := setup(t, Options{})
+	assert.Equal(t, []string{"v1", "v2"}, versions.Names())
+
+	resp := request(router, "/v1/things")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"one"`)
+	assert.Equal(t, "@1704067200", resp.Header().Get("Deprecation"))
+	assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", resp.Header().Get("Sunset"))
+	assert.Contains(t, resp.Header().Values("Link"), `<https://example.com/migrate>; rel="deprecation"`)
+	assert.Contains(t, strings.Join(resp.Header().Values("Link"), ", "), "/v1/schemas/ThingV1.json")
+
+	resp = request(router, "/v2/things")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"title":"two"`)
+	assert.Empty(t, resp.Header().Get("Deprecation"))
+	assert.Empty(t, resp.Header().Get("Sunset"))
+
+	assert.Equal(t, http.StatusNotFound, request(router, "/v2/legacy").Code)
+	assert.Equal(t, http.StatusNotFound, request(router, "/things").Code)
+
+	// Each version has its own OpenAPI document.
+	for name, schemas := range map[string][2]string{"v1": {"ThingV1", "ThingV2"}, "v2": {"ThingV2", "ThingV1"}} {
+		resp = request(router, "/"+name+"/openapi.json")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var doc struct {
+			Info    huma.Info
+			Servers []huma.Server
+			Paths   map[string]struct {
+				Get struct{ Deprecated bool }
+			}
+			Components struct {
+				Schemas map[string]any
+			}
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &doc))
+		assert.Equal(t, name, doc.Info.Version)
+		assert.Equal(t, "/"+name, doc.Servers[0].URL)
+		assert.Contains(t, doc.Paths, "/things")
+		assert.Equal(t, name == "v1", doc.Paths["/things"].Get.Deprecated)
+		assert.Contains(t, doc.Components.Schemas, schemas[0])
+		assert.NotContains(t, doc.Components.Schemas, schemas[1])
+	}
+
+	assert.Equal(t, http.StatusOK, request(router, "/v1/docs").Code)
+	assert.Equal(t, http.StatusOK, request(router, "/v2/schemas/ThingV2.json").Code)
+}
+
+func TestPathPrefixServers(t *testing.T) {
+	config := huma.DefaultConfig("Test", "1.0.0")
+	config.Servers = []*huma.Server{{URL: "https://api.example.com/"}}
+	versions := New(humatest.NewAdapter(chi.NewMux()), config, Options{})
+
+	v1 := versions.Version("v1", VersionOptions{})
+	assert.Equal(t, "https://api.example.com/v1", v1.OpenAPI().Servers[0].URL)
+	assert.Equal(t, "https://api.example.com/", config.Servers[0].URL)
+}
+
+func TestHeader(t *testing.T) {
+	router, _ := setup(t, Options{Strategy: Header})
+
+	// Defaults to the latest version.
+	resp := request(router, "/things")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"title":"two"`)
+	assert.Equal(t, "Accept-Version", resp.Header().Get("Vary"))
+
+	resp = request(router, "/things", "Accept-Version: v1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"one"`)
+	assert.Equal(t, "@1704067200", resp.Header().Get("Deprecation"))
+
+	resp = request(router, "/things", "Accept-Version: v3")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), `unknown API version`)
+
+	assert.Equal(t, http.StatusOK, request(router, "/legacy", "Accept-Version: v1").Code)
+	assert.Equal(t, http.StatusNotFound, request(router, "/legacy").Code)
+
+	// Docs are served under the version prefix.
+	resp = request(router, "/v1/openapi.json")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var doc struct {
+		Servers []huma.Server
+		Paths   map[string]struct {
+			Get struct{ Parameters []huma.Param }
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &doc))
+	assert.Empty(t, doc.Servers)
+	params := doc.Paths["/things"].Get.Parameters
+	require.Len(t, params, 1)
+	assert.Equal(t, "Accept-Version", params[0].Name)
+	assert.Equal(t, []any{"v1"}, params[0].Schema.Enum)
+
+	assert.Equal(t, http.StatusOK, request(router, "/v2/schemas/ThingV2.json").Code)
+}
+
+func TestHeaderDefault(t *testing.T) {
+	router, _ := setup(t, Options{Strategy: Header, Header: "X-API-Version", Default: "v1"})
+
+	resp := request(router, "/things")
+	assert.Contains(t, resp.Body.String(), `"name":"one"`)
+	assert.Equal(t, "X-API-Version", resp.Header().Get("Vary"))
+
+	resp = request(router, "/things", "X-API-Version: v2")
+	assert.Contains(t, resp.Body.String(), `"title":"two"`)
+}
+
+func TestVersionPanics(t *testing.T) {
+	versions := New(humatest.NewAdapter(chi.NewMux()), huma.DefaultConfig("Test", "1.0.0"), Options{})
+	versions.Version("v1", VersionOptions{})
+
+	assert.Panics(t, func() { versions.Version("v1", VersionOptions{}) })
+	assert.Panics(t, func() { versions.Version("", VersionOptions{}) })
+	assert.Panics(t, func() { versions.Version("v1/beta", VersionOptions{}) })
+}
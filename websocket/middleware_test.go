@@ -0,0 +1,38 @@
+//go:build go1.21
+
+package websocket
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humaslog"
+	"github.com/danielgtaylor/huma/v2/recovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSocketMiddleware(t *testing.T) {
+	// Middleware which wraps the context must not hide the hijacker.
+	api, server, _ := newServer(t,
+		func(api huma.API) func(huma.Context, func(huma.Context)) {
+			return recovery.New(api, recovery.Options{})
+		},
+		func(api huma.API) func(huma.Context, func(huma.Context)) {
+			return humaslog.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		},
+	)
+
+	c := dial(t, server, "/echo?prefix=re:")
+	c.send(EchoRequest{Text: "hello"})
+	_, payload := c.read()
+	assert.JSONEq(t, `{"text": "re:hello", "count": 1}`, string(payload))
+	c.close(CloseNormal)
+	c.expectClose(CloseNormal)
+
+	// The recorder can't be hijacked, even though the wrappers can.
+	resp := api.Get("/echo", "Connection: keep-alive, Upgrade", "Upgrade: websocket", "Sec-WebSocket-Version: 13", "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
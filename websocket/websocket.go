@@ -0,0 +1,573 @@
+// Package websocket provides WebSocket endpoints for Huma APIs. Operations
+// registered with this package upgrade the connection and give the handler a
+// typed connection for receiving and sending JSON messages. The message
+// schemas are documented via the `x-websocket` operation extension.
+//
+// Ping/pong keep-alives and the closing handshake are handled automatically,
+// and incoming messages are validated against their schema.
+//
+//	websocket.Register(api, huma.Operation{
+//		OperationID: "chat",
+//		Method:      http.MethodGet,
+//		Path:        "/chat",
+//	}, func(ctx context.Context, input *struct{}, conn *websocket.Conn[ChatIn, ChatOut]) error {
+//		for {
+//			msg, err := conn.Receive()
+//			if err != nil {
+//				return err
+//			}
+//			if err := conn.Send(ChatOut{Text: msg.Text}); err != nil {
+//				return err
+//			}
+//		}
+//	})
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// WriteTimeout is the timeout for writing a message to the client.
+var WriteTimeout = 5 * time.Second
+
+// PingInterval is how often pings are sent to the client. Connections which
+// don't receive anything from the client, including the pong reply, within
+// twice this interval are closed.
+var PingInterval = 30 * time.Second
+
+// ReadLimit is the maximum size in bytes of a message from the client.
+// Larger messages close the connection with `CloseMessageTooBig`. It also
+// limits the messages buffered until the handler calls `Receive`, after which
+// the oldest unread messages are dropped.
+var ReadLimit int64 = 1 << 20
+
+// CheckOrigin returns whether to accept a connection. The default only
+// accepts requests without an `Origin` header or from the same host, which
+// prevents other sites from connecting on behalf of a browser's user.
+var CheckOrigin = func(ctx huma.Context) bool {
+	origin := ctx.Header("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, ctx.Host())
+}
+
+// Close codes, see RFC 6455 section 7.4.1.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatus        = 1005
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+)
+
+// CloseError is returned by `Receive` once the connection has been closed.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return "websocket closed: " + strconv.Itoa(e.Code)
+	}
+	return "websocket closed: " + strconv.Itoa(e.Code) + " " + e.Reason
+}
+
+// The magic value used to compute the handshake response, see RFC 6455
+// section 1.3.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// Register a new WebSocket operation. `In` is the type of messages received
+// from the client and `Out` the type of messages sent to it. The handler is
+// called once the connection is upgraded, and the connection is closed when
+// it returns, using `CloseInternalError` if it returns an error other than a
+// `*CloseError`. The handler's context is canceled once the connection is
+// closed.
+//
+// Upgrading requires an adapter whose `BodyWriter` implements
+// `http.Hijacker`, which is the case for the `net/http` based adapters.
+func Register[I, In, Out any](api huma.API, op huma.Operation, f func(ctx context.Context, input *I, conn *Conn[In, Out]) error) {
+	registry := api.OpenAPI().Components.Schemas
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	outType := reflect.TypeOf((*Out)(nil)).Elem()
+	inSchema := registry.Schema(inType, true, op.OperationID+"-client-message")
+	outSchema := registry.Schema(outType, true, op.OperationID+"-server-message")
+
+	if op.Extensions == nil {
+		op.Extensions = map[string]any{}
+	}
+	op.Extensions["x-websocket"] = map[string]any{
+		"clientMessage": inSchema,
+		"serverMessage": outSchema,
+	}
+	if op.Responses == nil {
+		op.Responses = map[string]*huma.Response{}
+	}
+	if op.Responses["101"] == nil {
+		op.Responses["101"] = &huma.Response{
+			Description: "Switching Protocols to WebSocket",
+		}
+	}
+
+	huma.Register(api, op, func(ctx context.Context, input *I) (*huma.StreamResponse, error) {
+		return &huma.StreamResponse{
+			Body: func(hctx huma.Context) {
+				nc, rw, err := upgrade(api, hctx)
+				if err != nil {
+					return
+				}
+
+				ctx, cancel := context.WithCancel(hctx.Context())
+				c := &conn{nc: nc, br: rw.Reader, bw: rw.Writer, ready: make(chan struct{}, 1), done: make(chan struct{}), cancel: cancel}
+				go c.readLoop()
+				go c.pingLoop()
+
+				typed := &Conn[In, Out]{c: c, registry: registry, schema: inSchema}
+				err = f(ctx, input, typed)
+
+				code, reason := CloseNormal, ""
+				var ce *CloseError
+				if errors.As(err, &ce) {
+					code, reason = ce.Code, ce.Reason
+				} else if err != nil {
+					code, reason = CloseInternalError, "internal error"
+				}
+				c.close(code, reason)
+			},
+		}, nil
+	})
+}
+
+// upgrade validates the handshake request and hijacks the connection. If it
+// fails, an error response has already been written.
+func upgrade(api huma.API, ctx huma.Context) (net.Conn, *bufio.ReadWriter, error) {
+	if !headerContains(ctx.Header("Connection"), "upgrade") || !headerContains(ctx.Header("Upgrade"), "websocket") {
+		ctx.SetHeader("Upgrade", "websocket")
+		err := errors.New("expected websocket upgrade")
+		huma.WriteErr(api, ctx, http.StatusUpgradeRequired, err.Error())
+		return nil, nil, err
+	}
+	if ctx.Header("Sec-WebSocket-Version") != "13" {
+		ctx.SetHeader("Sec-WebSocket-Version", "13")
+		err := errors.New("unsupported websocket version")
+		huma.WriteErr(api, ctx, http.StatusUpgradeRequired, err.Error())
+		return nil, nil, err
+	}
+	key := ctx.Header("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		err := errors.New("invalid websocket key")
+		huma.WriteErr(api, ctx, http.StatusBadRequest, err.Error())
+		return nil, nil, err
+	}
+	if !CheckOrigin(ctx) {
+		err := errors.New("origin not allowed")
+		huma.WriteErr(api, ctx, http.StatusForbidden, err.Error())
+		return nil, nil, err
+	}
+
+	hj, ok := ctx.BodyWriter().(http.Hijacker)
+	if !ok {
+		err := fmt.Errorf("unable to upgrade: %w", http.ErrNotSupported)
+		huma.WriteErr(api, ctx, http.StatusInternalServerError, err.Error())
+		return nil, nil, err
+	}
+	nc, rw, err := hj.Hijack()
+	if err != nil {
+		// Middleware wrappers always implement `http.Hijacker`, but the
+		// underlying writer may not support it.
+		err = fmt.Errorf("unable to upgrade: %w", err)
+		huma.WriteErr(api, ctx, http.StatusInternalServerError, err.Error())
+		return nil, nil, err
+	}
+
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: ")
+	rw.WriteString(base64.StdEncoding.EncodeToString(h.Sum(nil)))
+	rw.WriteString("\r\n\r\n")
+	nc.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	if err := rw.Flush(); err != nil {
+		nc.Close()
+		return nil, nil, err
+	}
+	return nc, rw, nil
+}
+
+// headerContains returns whether a comma-separated header contains the token.
+func headerContains(header, token string) bool {
+	for _, part := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn is a WebSocket connection which receives `In` messages from the client
+// and sends `Out` messages to it, encoded as JSON. It is safe to call `Send`
+// concurrently with `Receive`, and to call `Send` from multiple goroutines.
+type Conn[In, Out any] struct {
+	c        *conn
+	registry huma.Registry
+	schema   *huma.Schema
+}
+
+// Receive waits for the next message from the client. Messages which are not
+// valid JSON or don't match the schema return a `huma.StatusError` with the
+// validation errors, and the connection stays open. Once the connection is
+// closed, a `*CloseError` is returned.
+func (c *Conn[In, Out]) Receive() (In, error) {
+	var msg In
+	data, err := c.c.receive()
+	if err != nil {
+		return msg, err
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return msg, huma.Error400BadRequest("invalid JSON message", err)
+	}
+	pb := huma.NewPathBuffer([]byte(""), 0)
+	pb.Push("message")
+	res := &huma.ValidateResult{}
+	huma.Validate(c.registry, c.schema, pb, huma.ModeWriteToServer, value, res)
+	if len(res.Errors) > 0 {
+		return msg, huma.Error422UnprocessableEntity("validation failed", res.Errors...)
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, huma.Error400BadRequest("invalid JSON message", err)
+	}
+	return msg, nil
+}
+
+// Send a message to the client as a JSON text message.
+func (c *Conn[In, Out]) Send(msg Out) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.c.writeFrame(opText, data)
+}
+
+// Close the connection with the given close code and reason. Closing an
+// already closed connection does nothing.
+func (c *Conn[In, Out]) Close(code int, reason string) error {
+	return c.c.close(code, reason)
+}
+
+// conn implements the WebSocket protocol on a hijacked connection.
+type conn struct {
+	nc net.Conn
+	br *bufio.Reader
+
+	wmu sync.Mutex
+	bw  *bufio.Writer
+
+	// Messages are queued so control frames keep being read while the
+	// handler isn't receiving, e.g. when it only sends.
+	qmu    sync.Mutex
+	queue  [][]byte
+	queued int64
+	ready  chan struct{}
+
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	closeOnce sync.Once
+	errMu     sync.Mutex
+	closeErr  *CloseError
+}
+
+// readLoop reads frames until the connection is closed, answering control
+// frames and queueing complete messages for `receive`.
+func (c *conn) readLoop() {
+	defer close(c.done)
+	defer c.cancel()
+
+	var message []byte
+	var messageOp byte
+	for {
+		c.nc.SetReadDeadline(time.Now().Add(2 * PingInterval))
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			code := CloseGoingAway
+			var ce *CloseError
+			if errors.As(err, &ce) {
+				code = ce.Code
+			}
+			c.setCloseErr(&CloseError{Code: code, Reason: err.Error()})
+			if code != CloseGoingAway {
+				c.writeClose(code, "")
+			}
+			c.nc.Close()
+			return
+		}
+
+		switch op {
+		case opPing:
+			c.writeFrame(opPong, payload)
+		case opPong:
+			// The read deadline was already extended.
+		case opClose:
+			ce := &CloseError{Code: CloseNoStatus}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			c.setCloseErr(ce)
+			c.writeClose(ce.Code, "")
+			c.nc.Close()
+			return
+		case opText, opBinary, opContinuation:
+			if (op == opContinuation) != (message != nil) {
+				c.setCloseErr(&CloseError{Code: CloseProtocolError, Reason: "unexpected frame"})
+				c.writeClose(CloseProtocolError, "unexpected frame")
+				c.nc.Close()
+				return
+			}
+			if int64(len(message)+len(payload)) > ReadLimit {
+				c.setCloseErr(&CloseError{Code: CloseMessageTooBig, Reason: "message too big"})
+				c.writeClose(CloseMessageTooBig, "message too big")
+				c.nc.Close()
+				return
+			}
+			if op != opContinuation {
+				messageOp = op
+			}
+			message = append(message, payload...)
+			if message == nil {
+				message = []byte{}
+			}
+			if fin {
+				if messageOp == opText && !utf8.Valid(message) {
+					c.setCloseErr(&CloseError{Code: CloseInvalidPayload, Reason: "invalid UTF-8"})
+					c.writeClose(CloseInvalidPayload, "invalid UTF-8")
+					c.nc.Close()
+					return
+				}
+				c.enqueue(message)
+				message = nil
+			}
+		default:
+			c.setCloseErr(&CloseError{Code: CloseProtocolError, Reason: "unknown opcode"})
+			c.writeClose(CloseProtocolError, "unknown opcode")
+			c.nc.Close()
+			return
+		}
+	}
+}
+
+// enqueue adds a complete message for `receive`, dropping the oldest unread
+// messages once more than `ReadLimit` bytes are queued.
+func (c *conn) enqueue(message []byte) {
+	c.qmu.Lock()
+	c.queue = append(c.queue, message)
+	c.queued += int64(len(message))
+	for c.queued > ReadLimit && len(c.queue) > 1 {
+		c.queued -= int64(len(c.queue[0]))
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+	}
+	c.qmu.Unlock()
+
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// dequeue returns the oldest unread message, if any.
+func (c *conn) dequeue() ([]byte, bool) {
+	c.qmu.Lock()
+	defer c.qmu.Unlock()
+	if len(c.queue) == 0 {
+		return nil, false
+	}
+	msg := c.queue[0]
+	c.queue[0] = nil
+	c.queue = c.queue[1:]
+	c.queued -= int64(len(msg))
+	return msg, true
+}
+
+// receive returns the next complete message from the client. Messages which
+// arrived before the connection was closed are returned first.
+func (c *conn) receive() ([]byte, error) {
+	for {
+		if msg, ok := c.dequeue(); ok {
+			return msg, nil
+		}
+		select {
+		case <-c.ready:
+			continue
+		case <-c.done:
+		}
+		if msg, ok := c.dequeue(); ok {
+			return msg, nil
+		}
+		c.errMu.Lock()
+		defer c.errMu.Unlock()
+		return nil, c.closeErr
+	}
+}
+
+func (c *conn) setCloseErr(err *CloseError) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	if c.closeErr == nil {
+		c.closeErr = err
+	}
+}
+
+// pingLoop sends pings until the connection is closed.
+func (c *conn) pingLoop() {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// readFrame reads a single frame from the client, unmasking its payload.
+func (c *conn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	op = header[0] & 0x0f
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, &CloseError{Code: CloseProtocolError, Reason: "reserved bits set"}
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, &CloseError{Code: CloseProtocolError, Reason: "client frames must be masked"}
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(b[:]))
+	}
+	if op >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, &CloseError{Code: CloseProtocolError, Reason: "invalid control frame"}
+	}
+	if length < 0 || length > ReadLimit {
+		return false, 0, nil, &CloseError{Code: CloseMessageTooBig, Reason: "message too big"}
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame writes a single unmasked frame to the client.
+func (c *conn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | op
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.nc.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	c.bw.Write(header)
+	c.bw.Write(payload)
+	return c.bw.Flush()
+}
+
+// writeClose sends a close frame, at most once per connection.
+func (c *conn) writeClose(code int, reason string) {
+	c.closeOnce.Do(func() {
+		var payload []byte
+		if code != CloseNoStatus {
+			if len(reason) > 123 {
+				reason = reason[:123]
+			}
+			payload = binary.BigEndian.AppendUint16(nil, uint16(code))
+			payload = append(payload, reason...)
+		}
+		c.writeFrame(opClose, payload)
+	})
+}
+
+// close starts the closing handshake and waits for the client to reply
+// before closing the connection.
+func (c *conn) close(code int, reason string) error {
+	c.setCloseErr(&CloseError{Code: code, Reason: reason})
+	c.writeClose(code, reason)
+	select {
+	case <-c.done:
+	case <-time.After(WriteTimeout):
+	}
+	return c.nc.Close()
+}
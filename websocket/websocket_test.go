@@ -0,0 +1,350 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type EchoRequest struct {
+	Text string `json:"text" minLength:"1"`
+}
+
+type EchoResponse struct {
+	Text  string `json:"text"`
+	Count int    `json:"count"`
+}
+
+// client is a minimal WebSocket client for tests.
+type client struct {
+	t  *testing.T
+	nc net.Conn
+	br *bufio.Reader
+}
+
+func dial(t *testing.T, server *httptest.Server, path string) *client {
+	nc, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { nc.Close() })
+	nc.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	require.NoError(t, req.Write(nc))
+
+	br := bufio.NewReader(nc)
+	resp, err := http.ReadResponse(br, req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return &client{t: t, nc: nc, br: br}
+}
+
+func (c *client) write(fin bool, op byte, payload []byte) {
+	header := []byte{op, 0x80}
+	if fin {
+		header[0] |= 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] |= byte(n)
+	case n <= 0xffff:
+		header[1] |= 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] |= 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	mask := make([]byte, 4)
+	rand.Read(mask)
+	masked := make([]byte, len(payload))
+	for i := range payload {
+		masked[i] = payload[i] ^ mask[i%4]
+	}
+	_, err := c.nc.Write(append(append(header, mask...), masked...))
+	require.NoError(c.t, err)
+}
+
+func (c *client) read() (byte, []byte) {
+	var header [2]byte
+	_, err := io.ReadFull(c.br, header[:])
+	require.NoError(c.t, err)
+	require.Zero(c.t, header[1]&0x80, "server frames must not be masked")
+	length := int(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var b [2]byte
+		io.ReadFull(c.br, b[:])
+		length = int(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		io.ReadFull(c.br, b[:])
+		length = int(binary.BigEndian.Uint64(b[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.br, payload)
+	require.NoError(c.t, err)
+	return header[0] & 0x0f, payload
+}
+
+func (c *client) send(v any) {
+	b, _ := json.Marshal(v)
+	c.write(true, opText, b)
+}
+
+func (c *client) close(code int) {
+	c.write(true, opClose, binary.BigEndian.AppendUint16(nil, uint16(code)))
+}
+
+func (c *client) expectClose(code int) {
+	op, payload := c.read()
+	require.Equal(c.t, byte(opClose), op, string(payload))
+	require.GreaterOrEqual(c.t, len(payload), 2)
+	assert.Equal(c.t, code, int(binary.BigEndian.Uint16(payload)))
+}
+
+// newServer creates an echo server, adding middleware created by each of the
+// given functions.
+func newServer(t *testing.T, middlewares ...func(api huma.API) func(huma.Context, func(huma.Context))) (humatest.TestAPI, *httptest.Server, chan error) {
+	router, api := humatest.New(t)
+	for _, m := range middlewares {
+		api.UseMiddleware(m(api))
+	}
+	done := make(chan error, 1)
+
+	Register(api, huma.Operation{
+		OperationID: "echo",
+		Method:      http.MethodGet,
+		Path:        "/echo",
+	}, func(ctx context.Context, input *struct {
+		Prefix string `query:"prefix"`
+	}, conn *Conn[EchoRequest, EchoResponse]) error {
+		count := 0
+		for {
+			msg, err := conn.Receive()
+			var se huma.StatusError
+			if errors.As(err, &se) {
+				conn.Send(EchoResponse{Text: err.Error()})
+				continue
+			}
+			if err != nil {
+				done <- err
+				return err
+			}
+			if msg.Text == "fail" {
+				done <- nil
+				return errors.New("failed")
+			}
+			count++
+			if err := conn.Send(EchoResponse{Text: input.Prefix + msg.Text, Count: count}); err != nil {
+				return err
+			}
+		}
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return api, server, done
+}
+
+func TestWebSocket(t *testing.T) {
+	api, server, done := newServer(t)
+	c := dial(t, server, "/echo?prefix=re:")
+
+	c.send(EchoRequest{Text: "hello"})
+	op, payload := c.read()
+	assert.Equal(t, byte(opText), op)
+	assert.JSONEq(t, `{"text": "re:hello", "count": 1}`, string(payload))
+
+	// Fragmented messages are reassembled and control frames are answered
+	// in between fragments.
+	c.write(false, opText, []byte(`{"text":`))
+	c.write(true, opPing, []byte("hi"))
+	op, payload = c.read()
+	assert.Equal(t, byte(opPong), op)
+	assert.Equal(t, "hi", string(payload))
+	c.write(true, opContinuation, []byte(`"world"}`))
+	_, payload = c.read()
+	assert.JSONEq(t, `{"text": "re:world", "count": 2}`, string(payload))
+
+	// Invalid messages are rejected without closing the connection.
+	c.send(map[string]any{"text": ""})
+	_, payload = c.read()
+	assert.Contains(t, string(payload), "validation failed")
+	c.write(true, opText, []byte("{"))
+	_, payload = c.read()
+	assert.Contains(t, string(payload), "invalid JSON message")
+
+	c.close(CloseNormal)
+	c.expectClose(CloseNormal)
+
+	var ce *CloseError
+	require.ErrorAs(t, <-done, &ce)
+	assert.Equal(t, CloseNormal, ce.Code)
+
+	// Message schemas are documented.
+	op2 := api.OpenAPI().Paths["/echo"].Get
+	require.NotNil(t, op2)
+	assert.Contains(t, op2.Responses, "101")
+	ext := op2.Extensions["x-websocket"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/EchoRequest", ext["clientMessage"].(*huma.Schema).Ref)
+	assert.Equal(t, "#/components/schemas/EchoResponse", ext["serverMessage"].(*huma.Schema).Ref)
+}
+
+func TestWebSocketHandlerError(t *testing.T) {
+	_, server, done := newServer(t)
+	c := dial(t, server, "/echo")
+
+	c.send(EchoRequest{Text: "fail"})
+	c.expectClose(CloseInternalError)
+	c.close(CloseInternalError)
+	assert.NoError(t, <-done)
+}
+
+func TestWebSocketProtocolErrors(t *testing.T) {
+	_, server, _ := newServer(t)
+
+	// Unmasked client frames are rejected.
+	c := dial(t, server, "/echo")
+	c.nc.Write([]byte{0x81, 0x00})
+	c.expectClose(CloseProtocolError)
+
+	// Messages over the limit are rejected.
+	c = dial(t, server, "/echo")
+	c.write(true, opText, make([]byte, ReadLimit+1))
+	c.expectClose(CloseMessageTooBig)
+
+	// Continuation frames must follow a fragment.
+	c = dial(t, server, "/echo")
+	c.write(true, opContinuation, []byte("{}"))
+	c.expectClose(CloseProtocolError)
+
+	// Text messages must be valid UTF-8, even when split between fragments.
+	c = dial(t, server, "/echo")
+	c.write(true, opText, []byte("{\"text\": \"\xff\"}"))
+	c.expectClose(CloseInvalidPayload)
+
+	c = dial(t, server, "/echo")
+	c.write(false, opText, []byte("{\"text\": \"\xe2\x82"))
+	c.write(true, opContinuation, []byte("\xac\"}"))
+	_, payload := c.read()
+	assert.JSONEq(t, `{"text": "\u20ac", "count": 1}`, string(payload))
+}
+
+func TestWebSocketSendOnly(t *testing.T) {
+	router, api := humatest.New(t)
+	done := make(chan struct{})
+
+	Register(api, huma.Operation{
+		OperationID: "push",
+		Method:      http.MethodGet,
+		Path:        "/push",
+	}, func(ctx context.Context, input *struct{}, conn *Conn[EchoRequest, EchoResponse]) error {
+		// Never receives, so client messages are buffered while control frames
+		// are still handled.
+		conn.Send(EchoResponse{Text: "ready"})
+		<-ctx.Done()
+		close(done)
+		return nil
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	c := dial(t, server, "/push")
+	_, payload := c.read()
+	assert.JSONEq(t, `{"text": "ready", "count": 0}`, string(payload))
+
+	c.send(EchoRequest{Text: "one"})
+	c.send(EchoRequest{Text: "two"})
+	c.write(true, opPing, []byte("hi"))
+	op, payload := c.read()
+	assert.Equal(t, byte(opPong), op)
+	assert.Equal(t, "hi", string(payload))
+
+	c.close(CloseNormal)
+	c.expectClose(CloseNormal)
+
+	select {
+	case <-done:
+	case <-time.After(WriteTimeout):
+		t.Fatal("handler context was not canceled after the client closed")
+	}
+}
+
+func TestWebSocketQueueLimit(t *testing.T) {
+	orig := ReadLimit
+	ReadLimit = 10
+	defer func() { ReadLimit = orig }()
+
+	c := &conn{ready: make(chan struct{}, 1), done: make(chan struct{})}
+	c.enqueue([]byte("aaaa"))
+	c.enqueue([]byte("bbbb"))
+	c.enqueue([]byte("cccc"))
+
+	// The oldest message was dropped to stay within the limit.
+	msg, err := c.receive()
+	require.NoError(t, err)
+	assert.Equal(t, "bbbb", string(msg))
+	msg, err = c.receive()
+	require.NoError(t, err)
+	assert.Equal(t, "cccc", string(msg))
+
+	c.closeErr = &CloseError{Code: CloseNormal}
+	close(c.done)
+	_, err = c.receive()
+	assert.Equal(t, c.closeErr, err)
+}
+
+func TestWebSocketPing(t *testing.T) {
+	orig := PingInterval
+	PingInterval = 50 * time.Millisecond
+	defer func() { PingInterval = orig }()
+
+	_, server, _ := newServer(t)
+	c := dial(t, server, "/echo")
+
+	op, _ := c.read()
+	assert.Equal(t, byte(opPing), op)
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	api, _, _ := newServer(t)
+
+	resp := api.Get("/echo")
+	assert.Equal(t, http.StatusUpgradeRequired, resp.Code)
+	assert.Equal(t, "websocket", resp.Header().Get("Upgrade"))
+
+	resp = api.Get("/echo", "Connection: Upgrade", "Upgrade: websocket", "Sec-WebSocket-Version: 8")
+	assert.Equal(t, http.StatusUpgradeRequired, resp.Code)
+	assert.Equal(t, "13", resp.Header().Get("Sec-WebSocket-Version"))
+
+	resp = api.Get("/echo", "Connection: Upgrade", "Upgrade: websocket", "Sec-WebSocket-Version: 13", "Sec-WebSocket-Key: bad")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = api.Get("/echo", "Connection: keep-alive, Upgrade", "Upgrade: websocket", "Sec-WebSocket-Version: 13", "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==", "Origin: https://evil.example.com")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	// The recorder can't be hijacked.
+	resp = api.Get("/echo", "Connection: keep-alive, Upgrade", "Upgrade: websocket", "Sec-WebSocket-Version: 13", "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
@@ -0,0 +1,155 @@
+package huma
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// xmlNode records which attributes & child elements were present in an XML
+// element, by local name.
+type xmlNode struct {
+	attrs    map[string]bool
+	children map[string][]*xmlNode
+}
+
+// isXMLContentType returns whether a request content type is XML, e.g.
+// `application/xml` or `application/my-format+xml; charset=utf-8`.
+func isXMLContentType(contentType string) bool {
+	start := strings.IndexRune(contentType, '+') + 1
+	end := strings.IndexRune(contentType, ';')
+	if end == -1 {
+		end = len(contentType)
+	}
+	ct := strings.TrimSpace(contentType[start:end])
+	return ct == "xml" || strings.HasSuffix(ct, "/xml")
+}
+
+// xmlPresence reads the XML document's tokens and returns the presence tree
+// of its root element.
+func xmlPresence(data []byte) (*xmlNode, error) {
+	doc := &xmlNode{children: map[string][]*xmlNode{}}
+	stack := []*xmlNode{doc}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{attrs: map[string]bool{}, children: map[string][]*xmlNode{}}
+			for _, a := range t.Attr {
+				n.attrs[a.Name.Local] = true
+			}
+			parent := stack[len(stack)-1]
+			parent.children[t.Name.Local] = append(parent.children[t.Name.Local], n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for _, nodes := range doc.children {
+		return nodes[0], nil
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+// prunePresent removes properties from a generic value converted from the
+// typed value `t` which were not present in the XML element `n`, so that
+// required properties are enforced and optional ones are not validated using
+// their zero values.
+func prunePresent(t reflect.Type, v any, n *xmlNode) {
+	t = deref(t)
+	if m, ok := v.(map[string]any); ok && t.Kind() == reflect.Struct {
+		pruneFields(t, m, n)
+	}
+}
+
+// pruneFields removes the properties of struct `t` from `m` which were not
+// present in the XML element `n`.
+func pruneFields(t reflect.Type, m map[string]any, n *xmlNode) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type == xmlNameType {
+			continue
+		}
+		tag := f.Tag.Get("xml")
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" || jsonName == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && jsonName == "" && deref(f.Type).Kind() == reflect.Struct {
+			// Embedded struct fields are part of the parent element.
+			pruneFields(deref(f.Type), m, n)
+			continue
+		}
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+
+		parts := strings.Split(tag, ",")
+		attr := false
+		other := false
+		for _, flag := range parts[1:] {
+			switch flag {
+			case "attr":
+				attr = true
+			case "chardata", "cdata", "innerxml", "comment", "any":
+				other = true
+			}
+		}
+		if other {
+			// The value has no element of its own to check.
+			continue
+		}
+		_, name := xmlName(parts[0])
+		if name == "" {
+			name = f.Name
+		}
+
+		if attr {
+			if !n.attrs[name] {
+				delete(m, jsonName)
+			}
+			continue
+		}
+
+		// Follow `a>b` paths down to the field's elements.
+		nodes := []*xmlNode{n}
+		for _, part := range strings.Split(name, ">") {
+			var next []*xmlNode
+			for _, node := range nodes {
+				next = append(next, node.children[part]...)
+			}
+			nodes = next
+		}
+		if len(nodes) == 0 {
+			delete(m, jsonName)
+			continue
+		}
+
+		value, ok := m[jsonName]
+		if !ok {
+			continue
+		}
+		ft := deref(f.Type)
+		switch ft.Kind() {
+		case reflect.Struct:
+			prunePresent(ft, value, nodes[0])
+		case reflect.Slice, reflect.Array:
+			if items, ok := value.([]any); ok {
+				for j := range items {
+					if j < len(nodes) {
+						prunePresent(ft.Elem(), items[j], nodes[j])
+					}
+				}
+			}
+		}
+	}
+}